@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// BackInStockHandler defines dependencies for back-in-stock handler.
+type BackInStockHandler struct {
+	backInStockService service.BackInStockService
+}
+
+// NewBackInStockHandler returns new instance of BackInStockHandler.
+func NewBackInStockHandler() *BackInStockHandler {
+	return &BackInStockHandler{}
+}
+
+// SetBackInStockService injects back-in-stock's service for BackInStockHandler.
+func (h *BackInStockHandler) SetBackInStockService(service service.BackInStockService) *BackInStockHandler {
+	h.backInStockService = service
+	return h
+}
+
+// Validate validates if all dependency for BackInStockHandler is complete.
+func (h *BackInStockHandler) Validate() *BackInStockHandler {
+	if h.backInStockService == nil {
+		log.Panic("Back-in-stock handler need back-in-stock service")
+	}
+	return h
+}
+
+// Subscription handles endpoint with prefix /product/subscription
+func (h *BackInStockHandler) Subscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Subscription")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.BackInStockRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.backInStockService.Subscribe(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		query := r.URL.Query()
+		customerID, _ := strconv.ParseInt(query.Get("customer_id"), 10, 64)
+		request := model.BackInStockRequest{
+			CustomerID: customerID,
+			SKU:        query.Get("sku"),
+		}
+
+		httpCode, resp = h.backInStockService.Unsubscribe(ctx, request)
+	} else if r.Method == http.MethodGet {
+		customerID := r.URL.Query().Get("customer_id")
+
+		httpCode, resp = h.backInStockService.List(ctx, customerID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
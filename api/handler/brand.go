@@ -10,12 +10,14 @@ import (
 
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
 	"github.com/richardsahvic/jamtangan/service"
 )
 
 // BrandHandler defines dependencies for brand handler.
 type BrandHandler struct {
 	brandService service.BrandService
+	adminToken   string
 }
 
 // NewBrandHandler returns new instance of BrandHandler
@@ -29,6 +31,12 @@ func (h *BrandHandler) SetBrandService(service service.BrandService) *BrandHandl
 	return h
 }
 
+// SetAdminToken sets the token admins restore deleted brands with.
+func (h *BrandHandler) SetAdminToken(token string) *BrandHandler {
+	h.adminToken = token
+	return h
+}
+
 // Validate validates if all dependency for BrandHandler is complete.
 func (h *BrandHandler) Validate() *BrandHandler {
 	if h.brandService == nil {
@@ -37,7 +45,7 @@ func (h *BrandHandler) Validate() *BrandHandler {
 	return h
 }
 
-// Brand handles endpoint with prefix /brand.
+// Brand handles endpoint with prefix /brand, GET without an id lists the brands.
 func (h *BrandHandler) Brand(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.GetLoggerContext(ctx, "handler", "Brand")
@@ -55,6 +63,63 @@ func (h *BrandHandler) Brand(w http.ResponseWriter, r *http.Request) {
 		json.Unmarshal(body, &request)
 
 		httpCode, resp = h.brandService.Create(ctx, request)
+	} else if r.Method == http.MethodGet {
+		query := r.URL.Query()
+
+		if brandID := query.Get("id"); brandID != "" {
+			httpCode, resp = h.brandService.GetByID(ctx, brandID)
+		} else {
+			request := model.ListBrandRequest{
+				Name:   query.Get("name"),
+				Limit:  query.Get("limit"),
+				Cursor: query.Get("cursor"),
+			}
+
+			httpCode, resp = h.brandService.List(ctx, request)
+		}
+	} else if r.Method == http.MethodPut {
+		var request model.UpdateBrandRequest
+		json.Unmarshal(body, &request)
+		request.ID = r.URL.Query().Get("id")
+
+		httpCode, resp = h.brandService.Update(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		request := model.DeleteBrandRequest{
+			ID:      r.URL.Query().Get("id"),
+			Cascade: r.URL.Query().Get("cascade") == "true",
+		}
+
+		httpCode, resp = h.brandService.Delete(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Restore handles endpoint with prefix /admin/brand/restore, with cascade=true the
+// products deleted along with the brand are restored too.
+func (h *BrandHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Restore")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodPost {
+		request := model.DeleteBrandRequest{
+			ID:      r.URL.Query().Get("id"),
+			Cascade: r.URL.Query().Get("cascade") == "true",
+		}
+
+		httpCode, resp = h.brandService.Restore(ctx, request)
 	} else {
 		httpCode = http.StatusMethodNotAllowed
 	}
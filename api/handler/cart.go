@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// CartHandler defines dependencies for cart handler.
+type CartHandler struct {
+	cartService service.CartService
+}
+
+// NewCartHandler returns new instance of CartHandler.
+func NewCartHandler() *CartHandler {
+	return &CartHandler{}
+}
+
+// SetCartService injects cart's service for CartHandler.
+func (h *CartHandler) SetCartService(service service.CartService) *CartHandler {
+	h.cartService = service
+	return h
+}
+
+// Validate validates if all dependency for CartHandler is complete.
+func (h *CartHandler) Validate() *CartHandler {
+	if h.cartService == nil {
+		log.Panic("Cart handler need cart service")
+	}
+	return h
+}
+
+// Cart handles endpoint with prefix /cart
+func (h *CartHandler) Cart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Cart")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.CreateCartRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.cartService.Create(ctx, request)
+	} else if r.Method == http.MethodGet {
+		cartID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.cartService.Get(ctx, cartID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CartItem handles endpoint with prefix /cart/item
+func (h *CartHandler) CartItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "CartItem")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.CartItemRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.cartService.AddItem(ctx, request)
+	} else if r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		var request model.CartItemRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.cartService.UpdateItem(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		query := r.URL.Query()
+
+		httpCode, resp = h.cartService.RemoveItem(ctx, query.Get("cart_id"), query.Get("sku"))
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Checkout handles endpoint with prefix /cart/checkout
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Checkout")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.CheckoutCartRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.cartService.Checkout(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
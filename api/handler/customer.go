@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// CustomerHandler defines dependencies for customer handler.
+type CustomerHandler struct {
+	customerService service.CustomerService
+}
+
+// NewCustomerHandler returns new instance of CustomerHandler.
+func NewCustomerHandler() *CustomerHandler {
+	return &CustomerHandler{}
+}
+
+// SetCustomerService injects customer's service for CustomerHandler.
+func (h *CustomerHandler) SetCustomerService(service service.CustomerService) *CustomerHandler {
+	h.customerService = service
+	return h
+}
+
+// Validate validates if all dependency for CustomerHandler is complete.
+func (h *CustomerHandler) Validate() *CustomerHandler {
+	if h.customerService == nil {
+		log.Panic("Customer handler need customer service")
+	}
+	return h
+}
+
+// Customer handles endpoint with prefix /customer
+func (h *CustomerHandler) Customer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Customer")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPut {
+		var request model.SaveCustomerRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.customerService.Save(ctx, request)
+	} else if r.Method == http.MethodGet {
+		customerID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.customerService.Get(ctx, customerID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Preferences handles endpoint with prefix /customer/preferences
+func (h *CustomerHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Preferences")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPut {
+		var request model.SaveNotificationPreferencesRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.customerService.SavePreferences(ctx, request)
+	} else if r.Method == http.MethodGet {
+		customerID := r.URL.Query().Get("customer_id")
+
+		httpCode, resp = h.customerService.GetPreferences(ctx, customerID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
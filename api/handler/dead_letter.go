@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// DeadLetterHandler defines dependencies for dead letter handler, every endpoint
+// needs the admin token.
+type DeadLetterHandler struct {
+	deadLetterService service.DeadLetterService
+	adminToken        string
+}
+
+// NewDeadLetterHandler returns new instance of DeadLetterHandler.
+func NewDeadLetterHandler() *DeadLetterHandler {
+	return &DeadLetterHandler{}
+}
+
+// SetDeadLetterService injects dead letter's service for DeadLetterHandler.
+func (h *DeadLetterHandler) SetDeadLetterService(service service.DeadLetterService) *DeadLetterHandler {
+	h.deadLetterService = service
+	return h
+}
+
+// SetAdminToken sets the token admins manage dead letters with.
+func (h *DeadLetterHandler) SetAdminToken(token string) *DeadLetterHandler {
+	h.adminToken = token
+	return h
+}
+
+// Validate validates if all dependency for DeadLetterHandler is complete.
+func (h *DeadLetterHandler) Validate() *DeadLetterHandler {
+	if h.deadLetterService == nil {
+		log.Panic("Dead letter handler need dead letter service")
+	}
+	return h
+}
+
+// DeadLetters handles endpoint with prefix /admin/dead-letters
+func (h *DeadLetterHandler) DeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "DeadLetters")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request := model.ListDeadLetterRequest{
+			EventType: query.Get("event_type"),
+			Status:    query.Get("status"),
+			Limit:     query.Get("limit"),
+			Cursor:    query.Get("cursor"),
+		}
+
+		httpCode, resp = h.deadLetterService.List(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		var request model.PurgeDeadLetterRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.deadLetterService.Purge(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeadLetter handles endpoint with prefix /admin/dead-letter
+func (h *DeadLetterHandler) DeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "DeadLetter")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodGet {
+		httpCode, resp = h.deadLetterService.Get(ctx, r.URL.Query().Get("id"))
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Retry handles endpoint with prefix /admin/dead-letter/retry
+func (h *DeadLetterHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Retry")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodPost {
+		var request model.RetryDeadLetterRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.deadLetterService.Retry(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// NotificationHandler defines dependencies for notification handler.
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler returns new instance of NotificationHandler.
+func NewNotificationHandler() *NotificationHandler {
+	return &NotificationHandler{}
+}
+
+// SetNotificationService injects notification's service for NotificationHandler.
+func (h *NotificationHandler) SetNotificationService(service service.NotificationService) *NotificationHandler {
+	h.notificationService = service
+	return h
+}
+
+// Validate validates if all dependency for NotificationHandler is complete.
+func (h *NotificationHandler) Validate() *NotificationHandler {
+	if h.notificationService == nil {
+		log.Panic("Notification handler need notification service")
+	}
+	return h
+}
+
+// Notification handles endpoint with prefix /notification
+func (h *NotificationHandler) Notification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Notification")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodDelete {
+		query := r.URL.Query()
+
+		httpCode, resp = h.notificationService.Delete(ctx, query.Get("customer_id"), query.Get("id"))
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Notifications handles endpoint with prefix /notifications
+func (h *NotificationHandler) Notifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Notifications")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request := model.ListNotificationRequest{
+			CustomerID: query.Get("customer_id"),
+			UnreadOnly: query.Get("unread"),
+			Limit:      query.Get("limit"),
+			Cursor:     query.Get("cursor"),
+		}
+
+		httpCode, resp = h.notificationService.List(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UnreadCount handles endpoint with prefix /notifications/unread
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "UnreadCount")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodGet {
+		httpCode, resp = h.notificationService.UnreadCount(ctx, r.URL.Query().Get("customer_id"))
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// MarkRead handles endpoint with prefix /notifications/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "MarkRead")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.MarkNotificationReadRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.notificationService.MarkRead(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
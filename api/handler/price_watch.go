@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// PriceWatchHandler defines dependencies for price watch handler.
+type PriceWatchHandler struct {
+	priceWatchService service.PriceWatchService
+}
+
+// NewPriceWatchHandler returns new instance of PriceWatchHandler.
+func NewPriceWatchHandler() *PriceWatchHandler {
+	return &PriceWatchHandler{}
+}
+
+// SetPriceWatchService injects price watch's service for PriceWatchHandler.
+func (h *PriceWatchHandler) SetPriceWatchService(service service.PriceWatchService) *PriceWatchHandler {
+	h.priceWatchService = service
+	return h
+}
+
+// Validate validates if all dependency for PriceWatchHandler is complete.
+func (h *PriceWatchHandler) Validate() *PriceWatchHandler {
+	if h.priceWatchService == nil {
+		log.Panic("Price watch handler need price watch service")
+	}
+	return h
+}
+
+// Watch handles endpoint with prefix /product/watch
+func (h *PriceWatchHandler) Watch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Watch")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.PriceWatchRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.priceWatchService.Watch(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		query := r.URL.Query()
+		customerID, _ := strconv.ParseInt(query.Get("customer_id"), 10, 64)
+		request := model.PriceWatchRequest{
+			CustomerID: customerID,
+			SKU:        query.Get("sku"),
+		}
+
+		httpCode, resp = h.priceWatchService.Unwatch(ctx, request)
+	} else if r.Method == http.MethodGet {
+		customerID := r.URL.Query().Get("customer_id")
+
+		httpCode, resp = h.priceWatchService.List(ctx, customerID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
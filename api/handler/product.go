@@ -10,12 +10,14 @@ import (
 
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
 	"github.com/richardsahvic/jamtangan/service"
 )
 
 // ProductHandler defines dependencies for product handler.
 type ProductHandler struct {
 	productService service.ProductService
+	adminToken     string
 }
 
 // NewProductHandler returns new instance of ProductHandler.
@@ -29,6 +31,12 @@ func (h *ProductHandler) SetProductService(service service.ProductService) *Prod
 	return h
 }
 
+// SetAdminToken sets the token admins restore deleted products with.
+func (h *ProductHandler) SetAdminToken(token string) *ProductHandler {
+	h.adminToken = token
+	return h
+}
+
 // Validate validates if all dependency for ProductHandler is complete.
 func (h *ProductHandler) Validate() *ProductHandler {
 	if h.productService == nil {
@@ -37,7 +45,8 @@ func (h *ProductHandler) Validate() *ProductHandler {
 	return h
 }
 
-// Product handles endpoint with prefix /product
+// Product handles endpoint with prefix /product, the version of the product is
+// returned as its ETag.
 func (h *ProductHandler) Product(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.GetLoggerContext(ctx, "handler", "Product")
@@ -59,10 +68,28 @@ func (h *ProductHandler) Product(w http.ResponseWriter, r *http.Request) {
 		productID := r.URL.Query().Get("id")
 
 		httpCode, resp = h.productService.GetByID(ctx, productID)
+	} else if r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		var request model.UpdateProductRequest
+		json.Unmarshal(body, &request)
+		request.ID = r.URL.Query().Get("id")
+		request.IfMatch = r.Header.Get("If-Match")
+		request.Partial = r.Method == http.MethodPatch
+
+		httpCode, resp = h.productService.Update(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		productID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.productService.Delete(ctx, productID)
 	} else {
 		httpCode = http.StatusMethodNotAllowed
 	}
 
+	if base, ok := resp.(*model.BaseResponse); ok {
+		if product, ok := base.ResultData.(model.GetProductResponse); ok {
+			w.Header().Set("ETag", utils.ETag(product.Version))
+		}
+	}
+
 	w.WriteHeader(httpCode)
 	json.NewEncoder(w).Encode(resp)
 }
@@ -90,3 +117,117 @@ func (h *ProductHandler) ProductByBrand(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(httpCode)
 	json.NewEncoder(w).Encode(resp)
 }
+
+// Products handles endpoint with prefix /products
+func (h *ProductHandler) Products(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Products")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request := model.ListProductRequest{
+			BrandID:     query.Get("brand_id"),
+			MinPrice:    query.Get("min_price"),
+			MaxPrice:    query.Get("max_price"),
+			InStock:     query.Get("in_stock"),
+			SKUPrefix:   query.Get("sku_prefix"),
+			CreatedFrom: query.Get("created_from"),
+			CreatedTo:   query.Get("created_to"),
+			Sort:        query.Get("sort"),
+			Limit:       query.Get("limit"),
+			Cursor:      query.Get("cursor"),
+		}
+
+		httpCode, resp = h.productService.List(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Restock handles endpoint with prefix /product/restock
+func (h *ProductHandler) Restock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Restock")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.RestockProductRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.productService.Restock(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UpdatePrice handles endpoint with prefix /product/price
+func (h *ProductHandler) UpdatePrice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "UpdatePrice")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.UpdatePriceRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.productService.UpdatePrice(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Restore handles endpoint with prefix /admin/product/restore
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Restore")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodPost {
+		productID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.productService.Restore(ctx, productID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
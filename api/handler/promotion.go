@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// PromotionHandler defines dependencies for promotion handler.
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+// NewPromotionHandler returns new instance of PromotionHandler.
+func NewPromotionHandler() *PromotionHandler {
+	return &PromotionHandler{}
+}
+
+// SetPromotionService injects promotion's service for PromotionHandler.
+func (h *PromotionHandler) SetPromotionService(service service.PromotionService) *PromotionHandler {
+	h.promotionService = service
+	return h
+}
+
+// Validate validates if all dependency for PromotionHandler is complete.
+func (h *PromotionHandler) Validate() *PromotionHandler {
+	if h.promotionService == nil {
+		log.Panic("Promotion handler need promotion service")
+	}
+	return h
+}
+
+// Promotion handles endpoint with prefix /promotion
+func (h *PromotionHandler) Promotion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Promotion")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.CreatePromotionRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.promotionService.Create(ctx, request)
+	} else if r.Method == http.MethodGet {
+		promotionID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.promotionService.GetByID(ctx, promotionID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// ReminderHandler defines dependencies for reminder handler.
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+// NewReminderHandler returns new instance of ReminderHandler.
+func NewReminderHandler() *ReminderHandler {
+	return &ReminderHandler{}
+}
+
+// SetReminderService injects reminder's service for ReminderHandler.
+func (h *ReminderHandler) SetReminderService(service service.ReminderService) *ReminderHandler {
+	h.reminderService = service
+	return h
+}
+
+// Validate validates if all dependency for ReminderHandler is complete.
+func (h *ReminderHandler) Validate() *ReminderHandler {
+	if h.reminderService == nil {
+		log.Panic("Reminder handler need reminder service")
+	}
+	return h
+}
+
+// Unsubscribe handles endpoint with prefix /reminder/unsubscribe, reached from
+// the link in reminders.
+func (h *ReminderHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Unsubscribe")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodGet || r.Method == http.MethodPost {
+		httpCode, resp = h.reminderService.Unsubscribe(ctx, r.URL.Query().Get("token"))
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -7,15 +7,23 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/pubsub"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
 	"github.com/richardsahvic/jamtangan/service"
 )
 
 // TransactionHandler defines dependencies for TransactionHandler.
 type TransactionHandler struct {
 	transactionService service.TransactionService
+	idempotencyService service.IdempotencyService
+	orderHub           *pubsub.Hub
+	adminToken         string
+	heartbeat          time.Duration
 }
 
 // NewTransactionhandler returns new instance of TransactionHandler.
@@ -29,11 +37,45 @@ func (h *TransactionHandler) SetTransactionService(service service.TransactionSe
 	return h
 }
 
+// SetIdempotencyService injects idempotency's service for TransactionHandler.
+func (h *TransactionHandler) SetIdempotencyService(service service.IdempotencyService) *TransactionHandler {
+	h.idempotencyService = service
+	return h
+}
+
+// SetOrderHub sets the hub order events are streamed from.
+func (h *TransactionHandler) SetOrderHub(hub *pubsub.Hub) *TransactionHandler {
+	h.orderHub = hub
+	return h
+}
+
+// SetAdminToken sets the token admins stream the events of every order and
+// restore deleted orders with.
+func (h *TransactionHandler) SetAdminToken(token string) *TransactionHandler {
+	h.adminToken = token
+	return h
+}
+
+// SetHeartbeat sets how often a heartbeat comment is written to idle event streams.
+func (h *TransactionHandler) SetHeartbeat(interval time.Duration) *TransactionHandler {
+	h.heartbeat = interval
+	return h
+}
+
 // Validate validates if all dependency for TransactionHandler is complete.
 func (h *TransactionHandler) Validate() *TransactionHandler {
 	if h.transactionService == nil {
 		log.Panic("Transaction handler need transaction service")
 	}
+	if h.idempotencyService == nil {
+		log.Panic("Transaction handler need idempotency service")
+	}
+	if h.orderHub == nil {
+		log.Panic("Transaction handler need order hub")
+	}
+	if h.heartbeat <= 0 {
+		log.Panic("Transaction handler need heartbeat interval")
+	}
 	return h
 }
 
@@ -54,11 +96,100 @@ func (h *TransactionHandler) Transaction(w http.ResponseWriter, r *http.Request)
 		var request model.CreateTransactionRequest
 		json.Unmarshal(body, &request)
 
-		httpCode, resp = h.transactionService.Create(ctx, request)
+		// retried requests with the same key get the response of the first one
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			httpCode, resp = h.idempotencyService.Execute(ctx, key, request, func() (int, *model.BaseResponse) {
+				return h.transactionService.Create(ctx, request)
+			})
+		} else {
+			httpCode, resp = h.transactionService.Create(ctx, request)
+		}
 	} else if r.Method == http.MethodGet {
 		orderID := r.URL.Query().Get("id")
 
 		httpCode, resp = h.transactionService.GetDetail(ctx, orderID)
+	} else if r.Method == http.MethodDelete {
+		orderID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.transactionService.Delete(ctx, orderID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RestoreOrder handles endpoint with prefix /admin/order/restore
+func (h *TransactionHandler) RestoreOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "RestoreOrder")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodPost {
+		orderID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.transactionService.Restore(ctx, orderID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OrderStatus handles endpoint with prefix /order/status
+func (h *TransactionHandler) OrderStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "OrderStatus")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPatch {
+		var request model.UpdateOrderStatusRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.transactionService.UpdateStatus(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CancelOrder handles endpoint with prefix /order/cancel
+func (h *TransactionHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "CancelOrder")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodPost {
+		var request model.CancelOrderRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.transactionService.Cancel(ctx, request)
 	} else {
 		httpCode = http.StatusMethodNotAllowed
 	}
@@ -66,3 +197,130 @@ func (h *TransactionHandler) Transaction(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(httpCode)
 	json.NewEncoder(w).Encode(resp)
 }
+
+// Orders handles endpoint with prefix /orders
+func (h *TransactionHandler) Orders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Orders")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request := model.ListOrderRequest{
+			CreatedFrom: query.Get("created_from"),
+			CreatedTo:   query.Get("created_to"),
+			SKU:         query.Get("sku"),
+			MinTotal:    query.Get("min_total"),
+			MaxTotal:    query.Get("max_total"),
+			Status:      query.Get("status"),
+			Sort:        query.Get("sort"),
+			Limit:       query.Get("limit"),
+			Cursor:      query.Get("cursor"),
+		}
+
+		httpCode, resp = h.transactionService.List(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OrderEvents handles endpoint with prefix /order/events, it streams the events
+// of an order, or of every order for admins, as Server-Sent Events. A client
+// that reconnects with Last-Event-ID receives the events it missed, or a reset
+// event when they are no longer kept.
+func (h *TransactionHandler) OrderEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "OrderEvents")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	httpCode, resp := h.openOrderEvents(r)
+	if httpCode != http.StatusOK {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpCode)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID, _ := strconv.ParseInt(lastEventID(r), 10, 64)
+	subscription := h.orderHub.Subscribe(r.URL.Query().Get("id"), lastEventID)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if subscription.Missed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// the client fell behind, it resumes from the last event it received
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+		}
+		flusher.Flush()
+	}
+}
+
+// openOrderEvents validates a request to stream order events.
+func (h *TransactionHandler) openOrderEvents(r *http.Request) (int, *model.BaseResponse) {
+	if r.Method != http.MethodGet {
+		return http.StatusMethodNotAllowed, nil
+	}
+
+	if id := lastEventID(r); id != "" {
+		_, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return utils.RequestInvalid("Last-Event-ID")
+		}
+	}
+
+	orderID := r.URL.Query().Get("id")
+	if orderID == "" {
+		if !utils.IsAdmin(r, h.adminToken) {
+			return utils.Unauthorized()
+		}
+		return http.StatusOK, nil
+	}
+
+	return h.transactionService.GetDetail(r.Context(), orderID)
+}
+
+// lastEventID returns the ID of the last event a reconnecting client received,
+// clients that can not set headers pass it as a query parameter.
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("last_event_id")
+}
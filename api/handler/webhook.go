@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+// WebhookHandler defines dependencies for webhook handler, every endpoint
+// needs the admin token.
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	adminToken     string
+}
+
+// NewWebhookHandler returns new instance of WebhookHandler.
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{}
+}
+
+// SetWebhookService injects webhook's service for WebhookHandler.
+func (h *WebhookHandler) SetWebhookService(service service.WebhookService) *WebhookHandler {
+	h.webhookService = service
+	return h
+}
+
+// SetAdminToken sets the token admins manage webhooks with.
+func (h *WebhookHandler) SetAdminToken(token string) *WebhookHandler {
+	h.adminToken = token
+	return h
+}
+
+// Validate validates if all dependency for WebhookHandler is complete.
+func (h *WebhookHandler) Validate() *WebhookHandler {
+	if h.webhookService == nil {
+		log.Panic("Webhook handler need webhook service")
+	}
+	return h
+}
+
+// Webhook handles endpoint with prefix /webhook
+func (h *WebhookHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "Webhook")
+
+	body, _ := ioutil.ReadAll(io.LimitReader(r.Body, 5000))
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodPost {
+		var request model.CreateWebhookRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.webhookService.Create(ctx, request)
+	} else if r.Method == http.MethodGet {
+		webhookID := r.URL.Query().Get("id")
+
+		if webhookID == "" {
+			httpCode, resp = h.webhookService.List(ctx)
+		} else {
+			httpCode, resp = h.webhookService.Get(ctx, webhookID)
+		}
+	} else if r.Method == http.MethodPatch {
+		var request model.UpdateWebhookRequest
+		json.Unmarshal(body, &request)
+
+		httpCode, resp = h.webhookService.Update(ctx, request)
+	} else if r.Method == http.MethodDelete {
+		webhookID := r.URL.Query().Get("id")
+
+		httpCode, resp = h.webhookService.Delete(ctx, webhookID)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// WebhookDeliveries handles endpoint with prefix /webhook/deliveries
+func (h *WebhookHandler) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.GetLoggerContext(ctx, "handler", "WebhookDeliveries")
+
+	log.Info(fmt.Sprintf("%+v", r))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var httpCode int
+	var resp interface{}
+
+	if !utils.IsAdmin(r, h.adminToken) {
+		httpCode, resp = utils.Unauthorized()
+	} else if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request := model.ListWebhookDeliveryRequest{
+			SubscriptionID: query.Get("webhook_id"),
+			EventID:        query.Get("event_id"),
+			Status:         query.Get("status"),
+			Limit:          query.Get("limit"),
+			Cursor:         query.Get("cursor"),
+		}
+
+		httpCode, resp = h.webhookService.ListDeliveries(ctx, request)
+	} else {
+		httpCode = http.StatusMethodNotAllowed
+	}
+
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(resp)
+}
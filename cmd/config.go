@@ -6,4 +6,61 @@ var DefaultConfig = map[string]interface{}{
 	"log_format": "",
 	"mysql_dsn":  "",
 	"port":       "",
+
+	"currency":                     "IDR",
+	"idempotency_key_ttl":          "24h",
+	"idempotency_lock_timeout":     "1m",
+	"idempotency_purge_interval":   "10m",
+	"idempotency_purge_batch_size": 1000,
+	"cart_ttl":                     "72h",
+	"cart_checkout_timeout":        "5m",
+	"low_stock_threshold":          5,
+
+	"outbox_poll_interval": "1s",
+	"outbox_batch_size":    100,
+	"outbox_lease":         "1m",
+	"outbox_max_attempts":  10,
+	"outbox_retry_base":    "10s",
+	"outbox_retry_max":     "1h",
+
+	"webhook_timeout":      "10s",
+	"webhook_max_failures": 10,
+
+	"smtp_host":          "localhost",
+	"smtp_port":          "25",
+	"smtp_username":      "",
+	"smtp_password":      "",
+	"smtp_from":          "",
+	"smtp_timeout":       "10s",
+	"email_template_dir": "template/email",
+	"default_locale":     "en",
+
+	"admin_token":            "",
+	"sse_heartbeat_interval": "15s",
+	"order_event_history":    1000,
+
+	"back_in_stock_per_unit": 1,
+	"price_watch_batch_size": 100,
+
+	"reminder_poll_interval":   "1m",
+	"reminder_batch_size":      100,
+	"cart_reminder_schedule":   []string{"1h", "24h", "72h"},
+	"order_reminder_schedule":  []string{"1h", "24h", "72h"},
+	"reminder_unsubscribe_url": "http://localhost:8001/reminder/unsubscribe",
+
+	"sms_gateway_url":       "",
+	"sms_api_key":           "",
+	"sms_sender":            "",
+	"sms_timeout":           "10s",
+	"sms_rate_limit":        10,
+	"sms_rate_burst":        20,
+	"sms_breaker_failures":  5,
+	"sms_breaker_cooldown":  "1m",
+	"push_url":              "",
+	"push_server_key":       "",
+	"push_timeout":          "10s",
+	"push_rate_limit":       50,
+	"push_rate_burst":       100,
+	"push_breaker_failures": 5,
+	"push_breaker_cooldown": "1m",
 }
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/service"
+)
+
+const deadLetterUsage = `Usage: %s dead-letter <command> [flags]
+
+Commands:
+  list   [-event-type TYPE] [-status dead|retried] [-limit N] [-cursor CURSOR]
+  show   ID
+  retry  [-payload JSON | -payload-file FILE] ID...
+  purge  [-event-type TYPE] [-status dead|retried] [-before RFC3339] [ID...]
+
+Retry with a payload edits a single dead letter before it is retried, a payload
+file of "-" is read from the standard input.
+`
+
+// RunDeadLetter runs the dead letter subcommand with its arguments and returns
+// the exit code. Responses are written to the standard output as JSON.
+func RunDeadLetter(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, deadLetterUsage, os.Args[0])
+		return 2
+	}
+
+	ctx := context.Background()
+	initialize(ctx)
+
+	deadLetterService := service.NewDeadLetterService().
+		SetDeadLetterRepo(repository.NewDeadLetterRepository()).
+		SetOutboxRepo(repository.NewOutboxRepository()).
+		SetTxRepo(repository.NewTxRepository()).
+		Validate()
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, deadLetterUsage, os.Args[0])
+	}
+
+	var httpCode int
+	var resp *model.BaseResponse
+
+	switch command {
+	case "list":
+		eventType := flags.String("event-type", "", "event type of the dead letters")
+		status := flags.String("status", "", "status of the dead letters")
+		limit := flags.String("limit", "", "dead letters per page")
+		cursor := flags.String("cursor", "", "cursor of the next page")
+		if flags.Parse(args) != nil {
+			return 2
+		}
+
+		httpCode, resp = deadLetterService.List(ctx, model.ListDeadLetterRequest{
+			EventType: *eventType,
+			Status:    *status,
+			Limit:     *limit,
+			Cursor:    *cursor,
+		})
+	case "show":
+		if flags.Parse(args) != nil || flags.NArg() != 1 {
+			flags.Usage()
+			return 2
+		}
+
+		httpCode, resp = deadLetterService.Get(ctx, flags.Arg(0))
+	case "retry":
+		payload := flags.String("payload", "", "payload replacing the payload of the dead letter")
+		payloadFile := flags.String("payload-file", "", "file of the payload replacing the payload of the dead letter")
+		if flags.Parse(args) != nil {
+			return 2
+		}
+
+		ids, err := parseIDs(flags.Args())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
+		request := model.RetryDeadLetterRequest{IDs: ids}
+		if *payload != "" {
+			request.Payload = json.RawMessage(*payload)
+		} else if *payloadFile != "" {
+			request.Payload, err = readPayload(*payloadFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+
+		httpCode, resp = deadLetterService.Retry(ctx, request)
+	case "purge":
+		eventType := flags.String("event-type", "", "event type of the dead letters")
+		status := flags.String("status", "", "status of the dead letters")
+		before := flags.String("before", "", "purge dead letters created before this RFC 3339 time")
+		if flags.Parse(args) != nil {
+			return 2
+		}
+
+		ids, err := parseIDs(flags.Args())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
+		httpCode, resp = deadLetterService.Purge(ctx, model.PurgeDeadLetterRequest{
+			IDs:       ids,
+			EventType: *eventType,
+			Status:    *status,
+			Before:    *before,
+		})
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	output, _ := json.MarshalIndent(resp, "", "  ")
+	fmt.Println(string(output))
+
+	if httpCode < 200 || httpCode > 299 {
+		return 1
+	}
+	return 0
+}
+
+// parseIDs returns the dead letter IDs of the command arguments.
+func parseIDs(args []string) ([]int64, error) {
+	ids := make([]int64, len(args))
+	for index, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dead letter ID %q", arg)
+		}
+		ids[index] = id
+	}
+	return ids, nil
+}
+
+// readPayload returns the content of a payload file, "-" is the standard input.
+func readPayload(path string) (json.RawMessage, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	return ioutil.ReadAll(reader)
+}
@@ -7,53 +7,308 @@ import (
 	"net/http"
 
 	"github.com/richardsahvic/jamtangan/api/handler"
+	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/domain/repository"
 	"github.com/richardsahvic/jamtangan/pkg/config"
 	"github.com/richardsahvic/jamtangan/pkg/constant"
 	"github.com/richardsahvic/jamtangan/pkg/database"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/pkg/pubsub"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
 	"github.com/richardsahvic/jamtangan/service"
 )
 
-// StartServer starts the server.
-func StartServer() {
-	ctx := context.Background()
-
+// initialize loads the configuration, the logger and the database.
+func initialize(ctx context.Context) {
 	if err := config.Load(DefaultConfig, constant.ConfigURL); err != nil {
 		log.Fatal(err)
 	}
 
 	logger.Configure()
 	database.InitMySql(ctx)
+}
+
+// StartServer starts the server.
+func StartServer() {
+	ctx := context.Background()
+	initialize(ctx)
 
 	// REPOSITORIES
 	brandRepo := repository.NewBrandRepository()
 	productRepo := repository.NewProductRepository()
 	transactionRepo := repository.NewTransactionRepository()
+	orderRepo := repository.NewOrderRepository()
+	txRepo := repository.NewTxRepository()
+	idempotencyRepo := repository.NewIdempotencyRepository()
+	promotionRepo := repository.NewPromotionRepository()
+	cartRepo := repository.NewCartRepository()
+	outboxRepo := repository.NewOutboxRepository()
+	webhookRepo := repository.NewWebhookRepository()
+	customerRepo := repository.NewCustomerRepository()
+	preferenceRepo := repository.NewNotificationPreferenceRepository()
+	notificationRepo := repository.NewNotificationRepository()
+	backInStockRepo := repository.NewBackInStockRepository()
+	priceWatchRepo := repository.NewPriceWatchRepository()
+	reminderRepo := repository.NewReminderRepository()
+	deadLetterRepo := repository.NewDeadLetterRepository()
+
+	// order changes are published to the event streams once committed
+	orderHub := pubsub.NewHub(config.GetInt("order_event_history"))
 
 	brandService := service.NewBrandService().
 		SetBrandRepo(brandRepo).
+		SetProductRepo(productRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
 		Validate()
 
 	productService := service.NewProductService().
 		SetProductRepo(productRepo).
 		SetBrandRepo(brandRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
 		Validate()
 
 	transactionService := service.NewTransactionService().
 		SetTransactionRepo(transactionRepo).
+		SetProductRepo(productRepo).
+		SetOrderRepo(orderRepo).
+		SetPromotionRepo(promotionRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
+		SetOrderHub(orderHub).
+		SetCurrency(config.GetString("currency")).
+		SetLowStockThreshold(int64(config.GetInt("low_stock_threshold"))).
+		Validate()
+
+	cartService := service.NewCartService().
+		SetCartRepo(cartRepo).
+		SetProductRepo(productRepo).
+		SetTransactionService(transactionService).
+		SetCartTTL(config.GetDuration("cart_ttl")).
+		SetCheckoutTimeout(config.GetDuration("cart_checkout_timeout")).
+		Validate()
+
+	promotionService := service.NewPromotionService().
+		SetPromotionRepo(promotionRepo).
+		Validate()
+
+	idempotencyService := service.NewIdempotencyService().
+		SetIdempotencyRepo(idempotencyRepo).
+		SetKeyTTL(config.GetDuration("idempotency_key_ttl")).
+		SetLockTimeout(config.GetDuration("idempotency_lock_timeout")).
+		SetPurge(config.GetDuration("idempotency_purge_interval"), config.GetInt("idempotency_purge_batch_size")).
+		Validate()
+
+	webhookService := service.NewWebhookService().
+		SetWebhookRepo(webhookRepo).
+		Validate()
+
+	webhookChannel := service.NewWebhookChannel().
+		SetWebhookRepo(webhookRepo).
+		SetSender(notifier.NewWebhookSender(config.GetDuration("webhook_timeout"))).
+		SetMaxFailures(int64(config.GetInt("webhook_max_failures"))).
 		Validate()
 
+	customerService := service.NewCustomerService().
+		SetCustomerRepo(customerRepo).
+		SetPreferenceRepo(preferenceRepo).
+		SetTxRepo(txRepo).
+		SetDefaultLocale(config.GetString("default_locale")).
+		Validate()
+
+	emailTemplates, err := notifier.LoadEmailTemplates(config.GetString("email_template_dir"), config.GetString("default_locale"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	emailChannel := service.NewEmailChannel().
+		SetCustomerRepo(customerRepo).
+		SetTransactionService(transactionService).
+		SetMailer(notifier.NewSMTPMailer(
+			config.GetString("smtp_host"),
+			config.GetString("smtp_port"),
+			config.GetString("smtp_username"),
+			config.GetString("smtp_password"),
+			config.GetString("smtp_from"),
+			config.GetDuration("smtp_timeout"),
+		)).
+		SetTemplates(emailTemplates).
+		Validate()
+
+	notificationService := service.NewNotificationService().
+		SetNotificationRepo(notificationRepo).
+		Validate()
+
+	inboxChannel := service.NewInboxChannel().
+		SetNotificationRepo(notificationRepo).
+		Validate()
+
+	backInStockService := service.NewBackInStockService().
+		SetBackInStockRepo(backInStockRepo).
+		SetProductRepo(productRepo).
+		Validate()
+
+	backInStockChannel := service.NewBackInStockChannel().
+		SetBackInStockRepo(backInStockRepo).
+		SetProductRepo(productRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
+		SetPerUnit(int64(config.GetInt("back_in_stock_per_unit"))).
+		Validate()
+
+	priceWatchService := service.NewPriceWatchService().
+		SetPriceWatchRepo(priceWatchRepo).
+		SetProductRepo(productRepo).
+		Validate()
+
+	priceWatchChannel := service.NewPriceWatchChannel().
+		SetPriceWatchRepo(priceWatchRepo).
+		SetProductRepo(productRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
+		SetBatchSize(config.GetInt("price_watch_batch_size")).
+		Validate()
+
+	cartReminderSchedule, err := utils.ParseDurations(config.GetStringSlice("cart_reminder_schedule"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	orderReminderSchedule, err := utils.ParseDurations(config.GetStringSlice("order_reminder_schedule"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reminderService := service.NewReminderService().
+		SetReminderRepo(reminderRepo).
+		SetCartRepo(cartRepo).
+		SetOrderRepo(orderRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
+		SetSchedule(model.ReminderKindCart, cartReminderSchedule).
+		SetSchedule(model.ReminderKindOrder, orderReminderSchedule).
+		SetPollInterval(config.GetDuration("reminder_poll_interval")).
+		SetBatchSize(config.GetInt("reminder_batch_size")).
+		SetUnsubscribeURL(config.GetString("reminder_unsubscribe_url")).
+		Validate()
+
+	dispatcherService := service.NewDispatcherService().
+		SetOutboxRepo(outboxRepo).
+		SetCustomerRepo(customerRepo).
+		SetPreferenceRepo(preferenceRepo).
+		SetDeadLetterRepo(deadLetterRepo).
+		SetTxRepo(txRepo).
+		AddChannel(notifier.NewLogChannel()).
+		AddChannel(webhookChannel).
+		AddChannel(emailChannel).
+		AddChannel(inboxChannel).
+		AddChannel(backInStockChannel).
+		AddChannel(priceWatchChannel).
+		SetPollInterval(config.GetDuration("outbox_poll_interval")).
+		SetBatchSize(config.GetInt("outbox_batch_size"), config.GetDuration("outbox_lease")).
+		SetRetry(int64(config.GetInt("outbox_max_attempts")), config.GetDuration("outbox_retry_base"), config.GetDuration("outbox_retry_max")).
+		Validate()
+
+	// SMS and push are only sent once their provider is configured
+	if config.GetString("sms_gateway_url") != "" {
+		dispatcherService.AddChannel(service.NewSMSChannel().
+			SetCustomerRepo(customerRepo).
+			SetGateway(notifier.NewSMSGateway(
+				config.GetString("sms_gateway_url"),
+				config.GetString("sms_api_key"),
+				config.GetString("sms_sender"),
+				config.GetDuration("sms_timeout"),
+			)).
+			SetGuard(notifier.NewGuard(
+				"sms",
+				config.GetFloat64("sms_rate_limit"),
+				config.GetInt("sms_rate_burst"),
+				config.GetInt("sms_breaker_failures"),
+				config.GetDuration("sms_breaker_cooldown"),
+			)).
+			Validate())
+	}
+
+	if config.GetString("push_url") != "" {
+		dispatcherService.AddChannel(service.NewPushChannel().
+			SetCustomerRepo(customerRepo).
+			SetSender(notifier.NewPushSender(
+				config.GetString("push_url"),
+				config.GetString("push_server_key"),
+				config.GetDuration("push_timeout"),
+			)).
+			SetGuard(notifier.NewGuard(
+				"push",
+				config.GetFloat64("push_rate_limit"),
+				config.GetInt("push_rate_burst"),
+				config.GetInt("push_breaker_failures"),
+				config.GetDuration("push_breaker_cooldown"),
+			)).
+			Validate())
+	}
+
 	brandHandler := handler.NewBrandHandler().
 		SetBrandService(brandService).
+		SetAdminToken(config.GetString("admin_token")).
 		Validate()
 
 	productHandler := handler.NewProductHandler().
 		SetProductService(productService).
+		SetAdminToken(config.GetString("admin_token")).
 		Validate()
 
 	transactionHandler := handler.NewTransactionhandler().
 		SetTransactionService(transactionService).
+		SetIdempotencyService(idempotencyService).
+		SetOrderHub(orderHub).
+		SetAdminToken(config.GetString("admin_token")).
+		SetHeartbeat(config.GetDuration("sse_heartbeat_interval")).
+		Validate()
+
+	promotionHandler := handler.NewPromotionHandler().
+		SetPromotionService(promotionService).
+		Validate()
+
+	cartHandler := handler.NewCartHandler().
+		SetCartService(cartService).
+		Validate()
+
+	webhookHandler := handler.NewWebhookHandler().
+		SetWebhookService(webhookService).
+		SetAdminToken(config.GetString("admin_token")).
+		Validate()
+
+	customerHandler := handler.NewCustomerHandler().
+		SetCustomerService(customerService).
+		Validate()
+
+	notificationHandler := handler.NewNotificationHandler().
+		SetNotificationService(notificationService).
+		Validate()
+
+	backInStockHandler := handler.NewBackInStockHandler().
+		SetBackInStockService(backInStockService).
+		Validate()
+
+	priceWatchHandler := handler.NewPriceWatchHandler().
+		SetPriceWatchService(priceWatchService).
+		Validate()
+
+	reminderHandler := handler.NewReminderHandler().
+		SetReminderService(reminderService).
+		Validate()
+
+	deadLetterService := service.NewDeadLetterService().
+		SetDeadLetterRepo(deadLetterRepo).
+		SetOutboxRepo(outboxRepo).
+		SetTxRepo(txRepo).
+		Validate()
+
+	deadLetterHandler := handler.NewDeadLetterHandler().
+		SetDeadLetterService(deadLetterService).
+		SetAdminToken(config.GetString("admin_token")).
 		Validate()
 
 	route := http.NewServeMux()
@@ -64,9 +319,62 @@ func StartServer() {
 	// Product API
 	route.HandleFunc("/product", productHandler.Product)
 	route.HandleFunc("/product/brand", productHandler.ProductByBrand)
+	route.HandleFunc("/products", productHandler.Products)
+	route.HandleFunc("/product/restock", productHandler.Restock)
+	route.HandleFunc("/product/subscription", backInStockHandler.Subscription)
+	route.HandleFunc("/product/price", productHandler.UpdatePrice)
+	route.HandleFunc("/product/watch", priceWatchHandler.Watch)
 
 	// Transaction API
 	route.HandleFunc("/order", transactionHandler.Transaction)
+	route.HandleFunc("/order/status", transactionHandler.OrderStatus)
+	route.HandleFunc("/order/cancel", transactionHandler.CancelOrder)
+	route.HandleFunc("/order/events", transactionHandler.OrderEvents)
+	route.HandleFunc("/orders", transactionHandler.Orders)
+
+	// Cart API
+	route.HandleFunc("/cart", cartHandler.Cart)
+	route.HandleFunc("/cart/item", cartHandler.CartItem)
+	route.HandleFunc("/cart/checkout", cartHandler.Checkout)
+
+	// Promotion API
+	route.HandleFunc("/promotion", promotionHandler.Promotion)
+
+	// Webhook API
+	route.HandleFunc("/webhook", webhookHandler.Webhook)
+	route.HandleFunc("/webhook/deliveries", webhookHandler.WebhookDeliveries)
+
+	// Customer API
+	route.HandleFunc("/customer", customerHandler.Customer)
+	route.HandleFunc("/customer/preferences", customerHandler.Preferences)
+
+	// Notification API
+	route.HandleFunc("/notification", notificationHandler.Notification)
+	route.HandleFunc("/notifications", notificationHandler.Notifications)
+	route.HandleFunc("/notifications/unread", notificationHandler.UnreadCount)
+	route.HandleFunc("/notifications/read", notificationHandler.MarkRead)
+
+	// Dead letter API
+	route.HandleFunc("/admin/dead-letters", deadLetterHandler.DeadLetters)
+	route.HandleFunc("/admin/dead-letter", deadLetterHandler.DeadLetter)
+	route.HandleFunc("/admin/dead-letter/retry", deadLetterHandler.Retry)
+
+	// Restore API
+	route.HandleFunc("/admin/brand/restore", brandHandler.Restore)
+	route.HandleFunc("/admin/product/restore", productHandler.Restore)
+	route.HandleFunc("/admin/order/restore", transactionHandler.RestoreOrder)
+
+	// Reminder API
+	route.HandleFunc("/reminder/unsubscribe", reminderHandler.Unsubscribe)
+
+	// deliver outbox events in the background
+	go dispatcherService.Run(ctx)
+
+	// send cart and order reminders in the background
+	go reminderService.Run(ctx)
+
+	// delete expired idempotency keys in the background
+	go idempotencyService.Run(ctx)
 
 	log.Println("SERVER STARTED")
 
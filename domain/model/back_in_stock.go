@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BackInStockStatus defines the state of a back-in-stock subscription.
+type BackInStockStatus string
+
+// List of back-in-stock subscription status.
+const (
+	BackInStockPending   BackInStockStatus = "pending"
+	BackInStockNotified  BackInStockStatus = "notified"
+	BackInStockCancelled BackInStockStatus = "cancelled"
+)
+
+// BackInStockSubscription is a customer waiting for a sold-out SKU. Pending
+// subscriptions of a SKU are notified oldest first.
+type BackInStockSubscription struct {
+	ID          int64             `json:"id" db:"id"`
+	CustomerID  int64             `json:"customer_id" db:"customer_id"`
+	ProductID   int64             `json:"product_id" db:"product_id"`
+	SKU         string            `json:"sku" db:"sku"`
+	Status      BackInStockStatus `json:"status" db:"status"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	NotifiedAt  sql.NullTime      `json:"-" db:"notified_at"`
+	CancelledAt sql.NullTime      `json:"-" db:"cancelled_at"`
+}
+
+// BackInStockAlertEvent is the payload of notification.back_in_stock, sent to a
+// customer subscribed to a SKU that is available again.
+type BackInStockAlertEvent struct {
+	SubscriptionID int64  `json:"subscription_id"`
+	CustomerID     int64  `json:"customer_id"`
+	ProductID      int64  `json:"product_id"`
+	SKU            string `json:"sku"`
+	Stock          int64  `json:"stock"`
+	Price          Money  `json:"price"`
+}
@@ -13,3 +13,23 @@ type Brand struct {
 	UpdatedAt sql.NullTime `db:"updated_at"`
 	DeletedAt sql.NullTime `db:"deleted_at"`
 }
+
+// BrandSummary contains details of a brand with the count and total stock of
+// its active products.
+type BrandSummary struct {
+	ID           int64        `db:"id"`
+	Name         string       `db:"name"`
+	ProductCount int64        `db:"product_count"`
+	TotalStock   int64        `db:"total_stock"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    sql.NullTime `db:"updated_at"`
+}
+
+// BrandFilter defines the name search and page of a brand listing, brands are
+// ordered by name.
+type BrandFilter struct {
+	Name      string
+	AfterName string
+	AfterID   int64
+	Limit     int
+}
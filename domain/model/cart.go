@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CartStatus defines the state of a cart.
+type CartStatus string
+
+// List of cart status.
+const (
+	CartStatusActive CartStatus = "active"
+	// CartStatusCheckingOut is held while the order of a cart is being placed,
+	// so the same cart can not be checked out twice at once.
+	CartStatusCheckingOut CartStatus = "checking_out"
+	CartStatusCheckedOut  CartStatus = "checked_out"
+	// CartStatusExpired is not stored, it is shown for an active cart past its expiry.
+	CartStatusExpired CartStatus = "expired"
+)
+
+// Cart contains details of a shopping cart.
+type Cart struct {
+	ID         int64          `json:"id" db:"id"`
+	CartID     string         `json:"cart_id" db:"cart_id"`
+	CustomerID sql.NullInt64  `json:"customer_id" db:"customer_id"`
+	Status     CartStatus     `json:"status" db:"status"`
+	OrderID    sql.NullString `json:"order_id" db:"order_id"`
+	ExpiresAt  time.Time      `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  sql.NullTime   `json:"updated_at" db:"updated_at"`
+	DeletedAt  sql.NullTime   `json:"deleted_at" db:"deleted_at"`
+}
+
+// CartItem contains an SKU in a cart, Price is the product price when the item
+// was last added or updated.
+type CartItem struct {
+	ID        int64        `json:"id" db:"id"`
+	CartID    string       `json:"cart_id" db:"cart_id"`
+	SKU       string       `json:"sku" db:"sku"`
+	Quantity  int64        `json:"quantity" db:"quantity"`
+	Price     Money        `json:"price" db:"price"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// NotificationChannel is a channel a customer can be notified through.
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelSMS     NotificationChannel = "sms"
+	NotificationChannelPush    NotificationChannel = "push"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+// NotificationChannels lists every channel a customer has preferences for.
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelPush,
+	NotificationChannelWebhook,
+}
+
+// NotificationCategory groups notifications a customer can opt in or out of.
+type NotificationCategory string
+
+const (
+	NotificationCategoryTransactional NotificationCategory = "transactional"
+	NotificationCategoryMarketing     NotificationCategory = "marketing"
+)
+
+// NotificationCategories lists every notification category.
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryTransactional,
+	NotificationCategoryMarketing,
+}
+
+// Customer contains the contact details of a customer, ID is the customer_id of orders.
+// Phone is in E.164 format and PushToken is the registration token of the
+// customer's app.
+// Quiet hours are "HH:MM" in the customer's timezone, the window wraps past
+// midnight when it ends before it starts.
+type Customer struct {
+	ID              int64          `json:"id" db:"id"`
+	Email           sql.NullString `json:"-" db:"email"`
+	Phone           sql.NullString `json:"-" db:"phone"`
+	PushToken       sql.NullString `json:"-" db:"push_token"`
+	Locale          string         `json:"locale" db:"locale"`
+	Timezone        string         `json:"timezone" db:"timezone"`
+	QuietHoursStart sql.NullString `json:"-" db:"quiet_hours_start"`
+	QuietHoursEnd   sql.NullString `json:"-" db:"quiet_hours_end"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"-" db:"updated_at"`
+}
+
+// NotificationPreference tells whether a customer receives a category of
+// notifications through a channel.
+type NotificationPreference struct {
+	CustomerID int64                `json:"customer_id" db:"customer_id"`
+	Channel    NotificationChannel  `json:"channel" db:"channel"`
+	Category   NotificationCategory `json:"category" db:"category"`
+	Enabled    bool                 `json:"enabled" db:"enabled"`
+	UpdatedAt  time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultNotificationEnabled returns whether a category is received when the
+// customer has not chosen, transactional notifications are opt-out and
+// marketing notifications are opt-in.
+func DefaultNotificationEnabled(category NotificationCategory) bool {
+	return category == NotificationCategoryTransactional
+}
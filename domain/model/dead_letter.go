@@ -0,0 +1,55 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// DeadLetterStatus defines the state of a dead letter.
+type DeadLetterStatus string
+
+// List of dead letter status.
+const (
+	DeadLetterStatusDead DeadLetterStatus = "dead"
+	// DeadLetterStatusRetried is a dead letter put back into the outbox as a new event.
+	DeadLetterStatusRetried DeadLetterStatus = "retried"
+)
+
+// DeadLetter is an outbox event that failed every delivery attempt, moved out of
+// the outbox with its last error so it can be inspected, fixed and retried.
+type DeadLetter struct {
+	ID                int64            `json:"id" db:"id"`
+	EventID           int64            `json:"event_id" db:"event_id"`
+	OriginEventID     sql.NullInt64    `json:"-" db:"origin_event_id"`
+	EventType         OutboxEventType  `json:"event_type" db:"event_type"`
+	AggregateID       string           `json:"aggregate_id" db:"aggregate_id"`
+	Payload           json.RawMessage  `json:"payload" db:"payload"`
+	Attempts          int64            `json:"attempts" db:"attempts"`
+	DeliveredChannels string           `json:"delivered_channels" db:"delivered_channels"`
+	LastError         sql.NullString   `json:"-" db:"last_error"`
+	Status            DeadLetterStatus `json:"status" db:"status"`
+	RetriedEventID    sql.NullInt64    `json:"-" db:"retried_event_id"`
+	EventCreatedAt    time.Time        `json:"event_created_at" db:"event_created_at"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	RetriedAt         sql.NullTime     `json:"-" db:"retried_at"`
+	UpdatedAt         sql.NullTime     `json:"-" db:"updated_at"`
+}
+
+// DeadLetterFilter defines the filters and page of dead letter listing, dead
+// letters are listed newest first.
+type DeadLetterFilter struct {
+	EventType OutboxEventType
+	Status    DeadLetterStatus
+	BeforeID  int64
+	Limit     int
+}
+
+// DeadLetterPurge defines the dead letters removed at once, every set field
+// must match.
+type DeadLetterPurge struct {
+	IDs       []int64
+	EventType OutboxEventType
+	Status    DeadLetterStatus
+	Before    *time.Time
+}
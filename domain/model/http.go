@@ -1,16 +1,27 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // CreateBrandRequest defines request to create brand.
 type CreateBrandRequest struct {
 	Name string `json:"name"`
 }
 
-// CreateProductRequest defines request to create product.
+// CreateProductRequest defines request to create product. The slug is made from
+// the name when it is not given, and the product is active unless told otherwise.
 type CreateProductRequest struct {
-	BrandID int64   `json:"brand_id"`
-	SKU     string  `json:"sku"`
-	Stock   int64   `json:"stock"`
-	Price   float64 `json:"price"`
+	BrandID     int64             `json:"brand_id"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Description string            `json:"description"`
+	Attributes  ProductAttributes `json:"attributes"`
+	Stock       int64             `json:"stock"`
+	Price       Money             `json:"price"`
+	Status      ProductStatus     `json:"status"`
 }
 
 // BaseResponse defines the base response of the system.
@@ -24,6 +35,51 @@ type CreateBrandResponse struct {
 	ID int64 `json:"id"`
 }
 
+// UpdateBrandRequest defines request to rename a brand.
+type UpdateBrandRequest struct {
+	ID   string `json:"-"`
+	Name string `json:"name"`
+}
+
+// ListBrandRequest defines request to list brands, every field is taken from the query string.
+type ListBrandRequest struct {
+	Name   string
+	Limit  string
+	Cursor string
+}
+
+// GetBrandResponse defines response to get brand.
+type GetBrandResponse struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	ProductCount int64      `json:"product_count"`
+	TotalStock   int64      `json:"total_stock"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// ListBrandResponse defines response to list brands.
+type ListBrandResponse struct {
+	Brands     []GetBrandResponse `json:"brands"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// DeleteBrandRequest defines request to delete or restore a brand. A brand with
+// products can only be deleted with Cascade, which deletes its products as well,
+// and restoring with Cascade brings back the products deleted along with it.
+type DeleteBrandRequest struct {
+	ID      string `json:"-"`
+	Cascade bool   `json:"-"`
+}
+
+// DeleteBrandResponse defines response of deleting or restoring a brand.
+type DeleteBrandResponse struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+	ProductCount int64      `json:"product_count"`
+}
+
 // CreateProductResponse defines response to create product.
 type CreateProductResponse struct {
 	ID int64 `json:"id"`
@@ -31,39 +87,602 @@ type CreateProductResponse struct {
 
 // GetProductResponse defines response to get product.
 type GetProductResponse struct {
-	ID      int64   `json:"id"`
-	BrandID int64   `json:"brand_id"`
-	SKU     string  `json:"sku"`
-	Stock   int64   `json:"stock"`
-	Price   float64 `json:"price"`
+	ID          int64             `json:"id"`
+	BrandID     int64             `json:"brand_id"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Description string            `json:"description,omitempty"`
+	Attributes  ProductAttributes `json:"attributes,omitempty"`
+	Stock       int64             `json:"stock"`
+	Price       Money             `json:"price"`
+	Status      ProductStatus     `json:"status"`
+	Version     int64             `json:"version"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// UpdateProductRequest defines request to update a product. A full update
+// replaces brand, stock and price, a partial update only the given ones, the
+// content and status are only changed when given. The product must still be at
+// Version, which can also be given as an If-Match ETag.
+type UpdateProductRequest struct {
+	ID          string            `json:"-"`
+	BrandID     *int64            `json:"brand_id"`
+	Stock       *int64            `json:"stock"`
+	Price       *Money            `json:"price"`
+	Name        *string           `json:"name"`
+	Slug        *string           `json:"slug"`
+	Description *string           `json:"description"`
+	Attributes  ProductAttributes `json:"attributes"`
+	Status      *ProductStatus    `json:"status"`
+	Version     *int64            `json:"version"`
+	IfMatch     string            `json:"-"`
+	Partial     bool              `json:"-"`
+}
+
+// ListProductRequest defines request to list products, every field is taken from the query string.
+type ListProductRequest struct {
+	BrandID     string
+	MinPrice    string
+	MaxPrice    string
+	InStock     string
+	SKUPrefix   string
+	CreatedFrom string
+	CreatedTo   string
+	Sort        string
+	Limit       string
+	Cursor      string
+}
+
+// ListProductResponse defines response to list products.
+type ListProductResponse struct {
+	Products   []GetProductResponse `json:"products"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 }
 
 // GetProductByBrandIDResponse defines response to get product by brand.
 type GetProductByBrandIDResponse struct {
-	Products []*Product `json:"products"`
+	Products []GetProductResponse `json:"products"`
 }
 
 // TransactionItem defines the items in transactions.
 type TransactionItem struct {
-	SKU      string  `json:"sku"`
-	Quantity int64   `json:"quantity"`
-	Subtotal float64 `json:"subtotal"`
+	SKU      string          `json:"sku"`
+	Quantity int64           `json:"quantity"`
+	Subtotal Money           `json:"subtotal"`
+	Type     TransactionType `json:"type,omitempty"`
 }
 
-// CreateTransactionRequest defines request to create transaction.
+// CreateTransactionRequest defines request to create transaction. ExpectedPrices is
+// the unit price per SKU the customer agreed to, the order is rejected when the
+// price of one of these SKUs is different.
 type CreateTransactionRequest struct {
-	Items []TransactionItem `json:"items"`
+	CustomerID     int64             `json:"customer_id"`
+	Items          []TransactionItem `json:"items"`
+	CouponCodes    []string          `json:"coupon_codes"`
+	ExpectedPrices map[string]Money  `json:"expected_prices,omitempty"`
+}
+
+// ListOrderRequest defines request to list orders, every field is taken from the query string.
+type ListOrderRequest struct {
+	CreatedFrom string
+	CreatedTo   string
+	SKU         string
+	MinTotal    string
+	MaxTotal    string
+	Status      string
+	Sort        string
+	Limit       string
+	Cursor      string
+}
+
+// UpdateOrderStatusRequest defines request to update order's status.
+type UpdateOrderStatusRequest struct {
+	OrderID string      `json:"order_id"`
+	Status  OrderStatus `json:"status"`
+}
+
+// TransactionItemPrice defines the price breakdown of an item in transactions,
+// the subtotal is the amount after discount.
+type TransactionItemPrice struct {
+	SKU      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+	Price    Money  `json:"price"`
+	Discount Money  `json:"discount"`
+	Subtotal Money  `json:"subtotal"`
+}
+
+// AppliedDiscount defines a promotion applied to a transaction.
+type AppliedDiscount struct {
+	PromotionID int64         `json:"promotion_id"`
+	Code        string        `json:"code,omitempty"`
+	Name        string        `json:"name"`
+	Type        PromotionType `json:"type"`
+	Amount      Money         `json:"amount"`
 }
 
 // CreateTransactionResponse defines response to create transaction.
 type CreateTransactionResponse struct {
-	OrderID    string  `json:"order_id"`
-	TotalPrice float64 `json:"total_price"`
+	OrderID        string                 `json:"order_id"`
+	Items          []TransactionItemPrice `json:"items"`
+	SubtotalPrice  Money                  `json:"subtotal_price"`
+	Discounts      []AppliedDiscount      `json:"discounts"`
+	DiscountAmount Money                  `json:"discount_amount"`
+	TotalPrice     Money                  `json:"total_price"`
+}
+
+// CreatePromotionRequest defines request to create promotion. A promotion
+// without code is applied automatically to every eligible order.
+type CreatePromotionRequest struct {
+	Code             string         `json:"code"`
+	Name             string         `json:"name"`
+	Type             PromotionType  `json:"type"`
+	Value            Money          `json:"value"`
+	BuyQuantity      int64          `json:"buy_quantity"`
+	GetQuantity      int64          `json:"get_quantity"`
+	Scope            PromotionScope `json:"scope"`
+	BrandID          int64          `json:"brand_id"`
+	SKU              string         `json:"sku"`
+	Stackable        bool           `json:"stackable"`
+	Priority         int64          `json:"priority"`
+	StartsAt         *time.Time     `json:"starts_at"`
+	EndsAt           *time.Time     `json:"ends_at"`
+	UsageLimit       int64          `json:"usage_limit"`
+	PerCustomerLimit int64          `json:"per_customer_limit"`
+}
+
+// CreatePromotionResponse defines response to create promotion.
+type CreatePromotionResponse struct {
+	ID int64 `json:"id"`
+}
+
+// GetPromotionResponse defines response to get promotion.
+type GetPromotionResponse struct {
+	ID               int64          `json:"id"`
+	Code             string         `json:"code,omitempty"`
+	Name             string         `json:"name"`
+	Type             PromotionType  `json:"type"`
+	Value            Money          `json:"value"`
+	BuyQuantity      int64          `json:"buy_quantity,omitempty"`
+	GetQuantity      int64          `json:"get_quantity,omitempty"`
+	Scope            PromotionScope `json:"scope"`
+	BrandID          int64          `json:"brand_id,omitempty"`
+	SKU              string         `json:"sku,omitempty"`
+	Stackable        bool           `json:"stackable"`
+	Priority         int64          `json:"priority"`
+	StartsAt         *time.Time     `json:"starts_at,omitempty"`
+	EndsAt           *time.Time     `json:"ends_at,omitempty"`
+	UsageLimit       int64          `json:"usage_limit,omitempty"`
+	PerCustomerLimit int64          `json:"per_customer_limit,omitempty"`
+	UsageCount       int64          `json:"usage_count"`
+}
+
+// InsufficientStockItem defines an ordered SKU that has not enough stock.
+type InsufficientStockItem struct {
+	SKU       string `json:"sku"`
+	Requested int64  `json:"requested"`
+	Available int64  `json:"available"`
+}
+
+// InsufficientStockResponse defines response when ordered SKUs have not enough stock.
+type InsufficientStockResponse struct {
+	Items []InsufficientStockItem `json:"items"`
+}
+
+// PriceChangedItem defines an ordered SKU whose price is not the expected one.
+type PriceChangedItem struct {
+	SKU           string `json:"sku"`
+	ExpectedPrice Money  `json:"expected_price"`
+	Price         Money  `json:"price"`
+}
+
+// PriceChangedResponse defines response when ordered SKUs have changed price.
+type PriceChangedResponse struct {
+	Items []PriceChangedItem `json:"items"`
+}
+
+// CancelOrderItem defines an order line to cancel.
+type CancelOrderItem struct {
+	SKU      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+// CancelOrderRequest defines request to cancel an order, the whole order is
+// cancelled when no items are given.
+type CancelOrderRequest struct {
+	OrderID string            `json:"order_id"`
+	Items   []CancelOrderItem `json:"items"`
+}
+
+// CancelOrderResponse defines response to cancel an order.
+type CancelOrderResponse struct {
+	OrderID         string                 `json:"order_id"`
+	Status          OrderStatus            `json:"status"`
+	Type            TransactionType        `json:"type"`
+	Items           []TransactionItemPrice `json:"items"`
+	CancelledAmount Money                  `json:"cancelled_amount"`
+	TotalAmount     Money                  `json:"total_amount"`
+}
+
+// UpdateOrderStatusResponse defines response to update order's status.
+type UpdateOrderStatusResponse struct {
+	OrderID string      `json:"order_id"`
+	Status  OrderStatus `json:"status"`
+}
+
+// ListOrderResponse defines response to list orders.
+type ListOrderResponse struct {
+	Orders     []*OrderSummary `json:"orders"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 // GetTranscationDetailResponse defines response to get transaction detail.
 type GetTranscationDetailResponse struct {
 	OrderID     string            `json:"order_id"`
+	Status      OrderStatus       `json:"status"`
 	Items       []TransactionItem `json:"items"`
-	TotalAmount float64           `json:"total_amount"`
+	TotalAmount Money             `json:"total_amount"`
+	CreatedAt   time.Time         `json:"created_at"`
+	PaidAt      *time.Time        `json:"paid_at,omitempty"`
+	PackedAt    *time.Time        `json:"packed_at,omitempty"`
+	ShippedAt   *time.Time        `json:"shipped_at,omitempty"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+	CancelledAt *time.Time        `json:"cancelled_at,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+}
+
+// CreateCartRequest defines request to create cart.
+type CreateCartRequest struct {
+	CustomerID int64 `json:"customer_id"`
+}
+
+// CartItemRequest defines request to add or update an item of a cart.
+type CartItemRequest struct {
+	CartID   string `json:"cart_id"`
+	SKU      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+// CheckoutCartRequest defines request to place an order from a cart.
+type CheckoutCartRequest struct {
+	CartID      string   `json:"cart_id"`
+	CouponCodes []string `json:"coupon_codes"`
+}
+
+// CartItemResponse defines an item of a cart with the live product price and stock.
+type CartItemResponse struct {
+	SKU          string `json:"sku"`
+	Quantity     int64  `json:"quantity"`
+	Price        Money  `json:"price"`
+	AddedPrice   Money  `json:"added_price"`
+	PriceChanged bool   `json:"price_changed"`
+	Stock        int64  `json:"stock"`
+	Available    bool   `json:"available"`
+	Subtotal     Money  `json:"subtotal"`
+}
+
+// CartResponse defines response of a cart.
+type CartResponse struct {
+	CartID     string             `json:"cart_id"`
+	CustomerID int64              `json:"customer_id,omitempty"`
+	Status     CartStatus         `json:"status"`
+	OrderID    string             `json:"order_id,omitempty"`
+	Items      []CartItemResponse `json:"items"`
+	TotalPrice Money              `json:"total_price"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+}
+
+// CreateWebhookRequest defines request to register a webhook endpoint. A secret
+// is generated when it is empty.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+// CreateWebhookResponse defines response to register a webhook endpoint, the
+// secret is only returned here.
+type CreateWebhookResponse struct {
+	ID     int64  `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookRequest defines request to change a webhook endpoint, fields left
+// out are kept. Enabling a webhook resets its failure count.
+type UpdateWebhookRequest struct {
+	ID         int64    `json:"id"`
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookResponse defines response of a webhook endpoint.
+type WebhookResponse struct {
+	ID                  int64      `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int64      `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// ListWebhookResponse defines response of webhook endpoint listing.
+type ListWebhookResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// ListWebhookDeliveryRequest defines request to list the delivery log of a webhook endpoint.
+type ListWebhookDeliveryRequest struct {
+	SubscriptionID string
+	EventID        string
+	Status         string
+	Limit          string
+	Cursor         string
+}
+
+// WebhookDeliveryResponse defines a webhook delivery attempt in the delivery log.
+type WebhookDeliveryResponse struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventID        int64                 `json:"event_id"`
+	EventType      OutboxEventType       `json:"event_type"`
+	Attempt        int64                 `json:"attempt"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	ResponseCode   int64                 `json:"response_code,omitempty"`
+	ResponseBody   string                `json:"response_body,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	DurationMS     int64                 `json:"duration_ms"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// ListWebhookDeliveryResponse defines response of webhook delivery listing.
+type ListWebhookDeliveryResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+// WebhookPayload defines the body posted to webhook endpoints.
+type WebhookPayload struct {
+	ID        int64           `json:"id"`
+	Type      OutboxEventType `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SaveCustomerRequest defines request to save the contact details of a customer.
+type SaveCustomerRequest struct {
+	CustomerID int64  `json:"customer_id"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	PushToken  string `json:"push_token"`
+	Locale     string `json:"locale"`
+}
+
+// CustomerResponse defines response of a customer.
+type CustomerResponse struct {
+	CustomerID int64  `json:"customer_id"`
+	Email      string `json:"email,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	HasPush    bool   `json:"has_push"`
+	Locale     string `json:"locale"`
+	Timezone   string `json:"timezone,omitempty"`
+}
+
+// QuietHours defines a window, as "HH:MM" in the customer's timezone, in which
+// notifications that are not critical are held back.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// NotificationPreferenceItem defines whether a category is received through a channel.
+type NotificationPreferenceItem struct {
+	Channel  NotificationChannel  `json:"channel"`
+	Category NotificationCategory `json:"category"`
+	Enabled  bool                 `json:"enabled"`
+}
+
+// SaveNotificationPreferencesRequest defines request to change the notification
+// preferences of a customer, only the given fields and preferences are changed.
+// Empty start and end of quiet hours turn quiet hours off.
+type SaveNotificationPreferencesRequest struct {
+	CustomerID  int64                        `json:"customer_id"`
+	Timezone    string                       `json:"timezone"`
+	QuietHours  *QuietHours                  `json:"quiet_hours"`
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+}
+
+// NotificationPreferencesResponse defines response of the notification
+// preferences of a customer, with every channel and category.
+type NotificationPreferencesResponse struct {
+	CustomerID  int64                        `json:"customer_id"`
+	Timezone    string                       `json:"timezone"`
+	QuietHours  *QuietHours                  `json:"quiet_hours"`
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+}
+
+// ListNotificationRequest defines request to list the inbox of a customer.
+type ListNotificationRequest struct {
+	CustomerID string
+	UnreadOnly string
+	Limit      string
+	Cursor     string
+}
+
+// NotificationResponse defines a notification in the inbox.
+type NotificationResponse struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Read      bool            `json:"read"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ListNotificationResponse defines response of notification listing.
+type ListNotificationResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+}
+
+// UnreadCountResponse defines response of the unread notification count.
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// MarkNotificationReadRequest defines request to mark a notification, or every
+// notification of the customer when All is set, as read.
+type MarkNotificationReadRequest struct {
+	CustomerID     int64 `json:"customer_id"`
+	NotificationID int64 `json:"id"`
+	All            bool  `json:"all"`
+}
+
+// MarkNotificationReadResponse defines response of marking notifications as read.
+type MarkNotificationReadResponse struct {
+	Updated     int64 `json:"updated"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// DeleteOrderResponse defines response of deleting or restoring an order.
+type DeleteOrderResponse struct {
+	OrderID   string      `json:"order_id"`
+	Status    OrderStatus `json:"status"`
+	DeletedAt *time.Time  `json:"deleted_at"`
+}
+
+// RestockProductRequest defines request to add units to the stock of a product.
+type RestockProductRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+// RestockProductResponse defines response of restocking a product.
+type RestockProductResponse struct {
+	ID    int64  `json:"id"`
+	SKU   string `json:"sku"`
+	Stock int64  `json:"stock"`
+}
+
+// BackInStockRequest defines request to subscribe to, or unsubscribe from, a sold-out SKU.
+type BackInStockRequest struct {
+	CustomerID int64  `json:"customer_id"`
+	SKU        string `json:"sku"`
+}
+
+// BackInStockResponse defines a back-in-stock subscription.
+type BackInStockResponse struct {
+	ID         int64             `json:"id"`
+	CustomerID int64             `json:"customer_id"`
+	SKU        string            `json:"sku"`
+	Status     BackInStockStatus `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	NotifiedAt *time.Time        `json:"notified_at,omitempty"`
+}
+
+// UpdatePriceRequest defines request to change the price of a product.
+type UpdatePriceRequest struct {
+	SKU   string `json:"sku"`
+	Price Money  `json:"price"`
+}
+
+// UpdatePriceResponse defines response of changing the price of a product.
+type UpdatePriceResponse struct {
+	ID       int64  `json:"id"`
+	SKU      string `json:"sku"`
+	OldPrice Money  `json:"old_price"`
+	Price    Money  `json:"price"`
+}
+
+// PriceWatchRequest defines request to watch the price of a product, either
+// TargetPrice or DropPercent is set.
+type PriceWatchRequest struct {
+	CustomerID  int64  `json:"customer_id"`
+	SKU         string `json:"sku"`
+	TargetPrice *Money `json:"target_price"`
+	DropPercent *Money `json:"drop_percent"`
+}
+
+// PriceWatchResponse defines a price watch.
+type PriceWatchResponse struct {
+	ID                int64            `json:"id"`
+	CustomerID        int64            `json:"customer_id"`
+	SKU               string           `json:"sku"`
+	BasePrice         Money            `json:"base_price"`
+	TargetPrice       Money            `json:"target_price"`
+	DropPercent       *Money           `json:"drop_percent,omitempty"`
+	LastNotifiedPrice *Money           `json:"last_notified_price,omitempty"`
+	Status            PriceWatchStatus `json:"status"`
+	CreatedAt         time.Time        `json:"created_at"`
+	NotifiedAt        *time.Time       `json:"notified_at,omitempty"`
+}
+
+// ListDeadLetterRequest defines request to list dead letters.
+type ListDeadLetterRequest struct {
+	EventType string
+	Status    string
+	Limit     string
+	Cursor    string
+}
+
+// DeadLetterResponse defines a dead letter.
+type DeadLetterResponse struct {
+	ID                int64            `json:"id"`
+	EventID           int64            `json:"event_id"`
+	EventType         OutboxEventType  `json:"event_type"`
+	AggregateID       string           `json:"aggregate_id"`
+	Payload           json.RawMessage  `json:"payload"`
+	Attempts          int64            `json:"attempts"`
+	DeliveredChannels []string         `json:"delivered_channels"`
+	LastError         string           `json:"last_error"`
+	Status            DeadLetterStatus `json:"status"`
+	RetriedEventID    *int64           `json:"retried_event_id,omitempty"`
+	EventCreatedAt    time.Time        `json:"event_created_at"`
+	CreatedAt         time.Time        `json:"created_at"`
+	RetriedAt         *time.Time       `json:"retried_at,omitempty"`
+}
+
+// ListDeadLetterResponse defines a page of dead letters, NextCursor is empty on the last page.
+type ListDeadLetterResponse struct {
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+	NextCursor  string               `json:"next_cursor,omitempty"`
+}
+
+// RetryDeadLetterRequest defines request to put dead letters back into the
+// outbox. Payload replaces the payload of a single dead letter before it is retried.
+type RetryDeadLetterRequest struct {
+	IDs     []int64         `json:"ids"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// RetriedDeadLetter defines a dead letter and the outbox event it was retried as.
+type RetriedDeadLetter struct {
+	ID      int64 `json:"id"`
+	EventID int64 `json:"event_id"`
+}
+
+// RetryDeadLetterResponse defines response of retrying dead letters, Skipped are
+// the dead letters that do not exist or were already retried.
+type RetryDeadLetterResponse struct {
+	Retried []RetriedDeadLetter `json:"retried"`
+	Skipped []int64             `json:"skipped"`
+}
+
+// PurgeDeadLetterRequest defines request to remove dead letters in bulk, Before
+// is an RFC 3339 time.
+type PurgeDeadLetterRequest struct {
+	IDs       []int64 `json:"ids"`
+	EventType string  `json:"event_type"`
+	Status    string  `json:"status"`
+	Before    string  `json:"before"`
+}
+
+// PurgeDeadLetterResponse defines response of removing dead letters.
+type PurgeDeadLetterResponse struct {
+	Purged int64 `json:"purged"`
 }
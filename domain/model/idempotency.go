@@ -0,0 +1,21 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// IdempotencyKey contains a client supplied key and the stored response of
+// the first request made with it. LockedUntil is when the request still running
+// with the key may be taken over by a retry, in case it stopped before storing
+// its response.
+type IdempotencyKey struct {
+	ID           int64          `db:"id"`
+	Key          string         `db:"idempotency_key"`
+	Fingerprint  string         `db:"fingerprint"`
+	ResponseCode sql.NullInt64  `db:"response_code"`
+	ResponseBody sql.NullString `db:"response_body"`
+	LockedUntil  sql.NullTime   `db:"locked_until"`
+	CreatedAt    time.Time      `db:"created_at"`
+	ExpiresAt    time.Time      `db:"expires_at"`
+}
@@ -0,0 +1,305 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// moneyScale is the number of fractional digits kept by Money, matching the decimal(50,3) columns.
+const moneyScale = 3
+
+// moneyUnit is the number of units in one whole amount.
+const moneyUnit = 1000
+
+// currencyScale defines the number of fractional digits of each currency.
+var currencyScale = map[string]int{
+	"IDR": 0,
+	"JPY": 0,
+	"SGD": 2,
+	"MYR": 2,
+	"USD": 2,
+	"EUR": 2,
+}
+
+// ErrInvalidMoney is returned when an amount can not be parsed as Money.
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// ErrMoneyOverflow is returned when the result of a calculation is too large for Money.
+var ErrMoneyOverflow = errors.New("money amount is too large")
+
+// Money is an exact decimal amount stored as thousandths, so adding up
+// subtotals never drifts the way float64 does.
+type Money struct {
+	units int64
+}
+
+// NewMoney returns Money of a whole amount.
+func NewMoney(amount int64) Money {
+	return Money{units: amount * moneyUnit}
+}
+
+// ParseMoney returns Money from a decimal string such as "50000", "-12.5" or "0.125".
+// Amounts with more than three fractional digits are rejected instead of rounded.
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	// at most one sign
+	negative := strings.HasPrefix(value, "-")
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	whole, fraction := value, ""
+	if index := strings.IndexByte(value, '.'); index >= 0 {
+		whole, fraction = value[:index], value[index+1:]
+	}
+
+	if whole == "" && fraction == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	// trailing zeros after the third digit do not change the amount
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > moneyScale || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, ErrInvalidMoney
+	}
+
+	fraction += strings.Repeat("0", moneyScale-len(fraction))
+	units, err := strconv.ParseInt("0"+whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+
+	if negative {
+		units = -units
+	}
+	return Money{units: units}, nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) Money {
+	return Money{units: m.units + other.units}
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) Money {
+	return Money{units: m.units - other.units}
+}
+
+// Mul returns m multiplied by a quantity, or ErrMoneyOverflow when the result
+// is too large.
+func (m Money) Mul(quantity int64) (Money, error) {
+	units, err := mulDivRound(m.units, quantity, 1)
+	return Money{units: units}, err
+}
+
+// MulDiv returns m * numerator / denominator, rounded half away from zero to
+// the smallest unit. It is used to take a share of an amount, such as the
+// subtotal of some units of a line. It returns ErrMoneyOverflow when the result
+// is too large.
+func (m Money) MulDiv(numerator, denominator int64) (Money, error) {
+	units, err := mulDivRound(m.units, numerator, denominator)
+	return Money{units: units}, err
+}
+
+// Share returns m * part / whole, rounded half away from zero to the smallest
+// unit. It is used to split an amount proportionally, such as a discount over lines.
+func (m Money) Share(part, whole Money) Money {
+	if whole.units == 0 {
+		return Money{}
+	}
+
+	// a share of m is never larger than m
+	units, _ := mulDivRound(m.units, part.units, whole.units)
+	return Money{units: units}
+}
+
+// Percent returns percent of m, such as 12.5 percent of a subtotal, rounded half
+// away from zero to the smallest unit.
+func (m Money) Percent(percent Money) Money {
+	// percentages are at most 100, so the result is never larger than m
+	units, _ := mulDivRound(m.units, percent.units, 100*moneyUnit)
+	return Money{units: units}
+}
+
+// Min returns the smaller of m and other.
+func (m Money) Min(other Money) Money {
+	if other.units < m.units {
+		return other
+	}
+	return m
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{units: -m.units}
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.units < other.units:
+		return -1
+	case m.units > other.units:
+		return 1
+	}
+	return 0
+}
+
+// IsZero returns true when the amount is zero.
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsNegative returns true when the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// Round rounds half away from zero to the fractional digits of a currency,
+// unknown currencies keep every digit.
+func (m Money) Round(currency string) Money {
+	scale, ok := currencyScale[strings.ToUpper(currency)]
+	if !ok || scale >= moneyScale {
+		return m
+	}
+
+	step := int64(math.Pow10(moneyScale - scale))
+	return Money{units: divRound(m.units, step) * step}
+}
+
+// mulDivRound returns value * numerator / denominator rounded half away from zero,
+// the product is computed with big integers so only a result too large for int64
+// returns ErrMoneyOverflow.
+func mulDivRound(value, numerator, denominator int64) (int64, error) {
+	product := new(big.Int).Mul(big.NewInt(value), big.NewInt(numerator))
+	if product.IsInt64() {
+		return divRound(product.Int64(), denominator), nil
+	}
+
+	divisor := big.NewInt(denominator)
+	if denominator < 0 {
+		product.Neg(product)
+		divisor.Neg(divisor)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if remainder.Abs(remainder).Lsh(remainder, 1).Cmp(divisor) >= 0 {
+		if product.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrMoneyOverflow
+	}
+	return quotient.Int64(), nil
+}
+
+// divRound divides and rounds half away from zero.
+func divRound(value, divisor int64) int64 {
+	if divisor < 0 {
+		value, divisor = -value, -divisor
+	}
+
+	quotient, remainder := value/divisor, value%divisor
+	if remainder < 0 {
+		remainder = -remainder
+	}
+
+	if remainder*2 >= divisor {
+		if value < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return quotient
+}
+
+// String returns the amount as a decimal string without trailing zeros.
+func (m Money) String() string {
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	whole, fraction := units/moneyUnit, units%moneyUnit
+	if fraction == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%s%d.%03d", sign, whole, fraction), "0")
+}
+
+// MarshalJSON writes the amount as a JSON number with its exact digits.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads the amount from a JSON number or string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	money, err := ParseMoney(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// Scan reads the amount from a DECIMAL column.
+func (m *Money) Scan(src interface{}) error {
+	var money Money
+	var err error
+
+	switch value := src.(type) {
+	case []byte:
+		money, err = ParseMoney(string(value))
+	case string:
+		money, err = ParseMoney(value)
+	case int64:
+		money = NewMoney(value)
+	case float64:
+		money, err = ParseMoney(strconv.FormatFloat(value, 'f', moneyScale, 64))
+	case nil:
+		money = Money{}
+	default:
+		err = fmt.Errorf("can not scan %T into Money", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// Value writes the amount to a DECIMAL column as an exact decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
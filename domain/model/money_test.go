@@ -0,0 +1,118 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	// Case: valid amounts
+	valid := map[string]string{
+		"50000":        "50000",
+		"50000.000":    "50000",
+		"0.1":          "0.1",
+		"-12.50":       "-12.5",
+		"+7":           "7",
+		"1.125":        "1.125",
+		".5":           "0.5",
+		"99999999.999": "99999999.999",
+	}
+	for value, expected := range valid {
+		money, err := model.ParseMoney(value)
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, money.String(), value)
+	}
+
+	// Case: invalid amounts
+	for _, value := range []string{"", "-", "abc", "1.2345", "1e5", "1.2.3", "-+5", "+-5", "--5", "++5"} {
+		_, err := model.ParseMoney(value)
+		assert.Equal(t, model.ErrInvalidMoney, err, value)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	// Case: adding subtotals does not drift
+	var total model.Money
+	price, _ := model.ParseMoney("0.1")
+	for i := 0; i < 10; i++ {
+		total = total.Add(price)
+	}
+	assert.Equal(t, model.NewMoney(1), total)
+
+	// Case: share of an amount is rounded half away from zero
+	share, err := model.NewMoney(10000).MulDiv(1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "3333.333", share.String())
+	share, err = model.NewMoney(-10000).MulDiv(2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "-6666.667", share.String())
+
+	// Case: a large product that still fits is exact
+	share, err = model.NewMoney(9000000000000).MulDiv(1000, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, "9000000000000", share.String())
+
+	// Case: quantity times price
+	subtotal, err := model.NewMoney(10000).Mul(3)
+	assert.Nil(t, err)
+	assert.Equal(t, model.NewMoney(30000), subtotal)
+
+	// Case: results too large are refused instead of wrapping around
+	_, err = model.NewMoney(1000000000000).Mul(10000000)
+	assert.Equal(t, model.ErrMoneyOverflow, err)
+	_, err = model.NewMoney(-1000000000000).Mul(10000000)
+	assert.Equal(t, model.ErrMoneyOverflow, err)
+	_, err = model.NewMoney(1000000000000).MulDiv(10000000, 3)
+	assert.Equal(t, model.ErrMoneyOverflow, err)
+
+	// Case: percentage and proportional share
+	percent, _ := model.ParseMoney("12.5")
+	assert.Equal(t, "1250", model.NewMoney(10000).Percent(percent).String())
+	assert.Equal(t, "500000000000000", model.NewMoney(1000000000000000).Percent(model.NewMoney(50)).String())
+	assert.Equal(t, "2500", model.NewMoney(10000).Share(model.NewMoney(1), model.NewMoney(4)).String())
+	assert.True(t, model.NewMoney(10000).Share(model.NewMoney(1), model.Money{}).IsZero())
+
+	// Case: rounding to the currency
+	amount, _ := model.ParseMoney("3333.5")
+	assert.Equal(t, "3334", amount.Round("IDR").String())
+	assert.Equal(t, "3333.5", amount.Round("USD").String())
+	assert.Equal(t, "-3334", amount.Neg().Round("idr").String())
+	assert.Equal(t, "3333.5", amount.Round("XXX").String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var product model.GetProductResponse
+
+	// Case: number and string are both accepted
+	err := json.Unmarshal([]byte(`{"price": 1234567890123.456}`), &product)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567890123.456", product.Price.String())
+
+	err = json.Unmarshal([]byte(`{"price": "10.5"}`), &product)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.5", product.Price.String())
+
+	// Case: digits are written as they are
+	body, err := json.Marshal(model.GetProductResponse{Price: product.Price})
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `"price":10.5`)
+}
+
+func TestMoneySQL(t *testing.T) {
+	var money model.Money
+
+	// Case: DECIMAL column is read as bytes
+	err := money.Scan([]byte("50000.125"))
+	assert.Nil(t, err)
+	assert.Equal(t, "50000.125", money.String())
+
+	value, err := money.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "50000.125", value)
+
+	err = money.Scan(true)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Notification is a message shown in the in-app inbox of a customer. EventID is
+// the outbox event it was created from, so an event is only stored once.
+type Notification struct {
+	ID         int64           `json:"id" db:"id"`
+	CustomerID int64           `json:"customer_id" db:"customer_id"`
+	EventID    sql.NullInt64   `json:"-" db:"event_id"`
+	Type       string          `json:"type" db:"type"`
+	Title      string          `json:"title" db:"title"`
+	Body       string          `json:"body" db:"body"`
+	Data       json.RawMessage `json:"data" db:"data"`
+	ReadAt     sql.NullTime    `json:"-" db:"read_at"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	DeletedAt  sql.NullTime    `json:"-" db:"deleted_at"`
+}
+
+// NotificationFilter defines the filters and page of notification listing,
+// notifications are listed newest first.
+type NotificationFilter struct {
+	CustomerID int64
+	UnreadOnly bool
+	BeforeID   int64
+	Limit      int
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// OrderStatus defines the lifecycle status of an order.
+type OrderStatus string
+
+// List of order status.
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusPacked    OrderStatus = "packed"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// Order contains header details of an order, the items are stored as transactions.
+type Order struct {
+	ID             int64         `json:"id" db:"id"`
+	OrderID        string        `json:"order_id" db:"order_id"`
+	CustomerID     sql.NullInt64 `json:"customer_id" db:"customer_id"`
+	Status         OrderStatus   `json:"status" db:"status"`
+	TotalAmount    Money         `json:"total_amount" db:"total_amount"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	PaidAt         sql.NullTime  `json:"paid_at" db:"paid_at"`
+	PackedAt       sql.NullTime  `json:"packed_at" db:"packed_at"`
+	ShippedAt      sql.NullTime  `json:"shipped_at" db:"shipped_at"`
+	DeliveredAt    sql.NullTime  `json:"delivered_at" db:"delivered_at"`
+	CancelledAt    sql.NullTime  `json:"cancelled_at" db:"cancelled_at"`
+	UpdatedAt      sql.NullTime  `json:"updated_at" db:"updated_at"`
+	DeletedAt      sql.NullTime  `json:"deleted_at" db:"deleted_at"`
+	DiscountAmount Money         `json:"discount_amount" db:"discount_amount"`
+}
+
+// OrderSummary contains summarized details of an order for listing.
+type OrderSummary struct {
+	ID          int64       `json:"-" db:"id"`
+	OrderID     string      `json:"order_id" db:"order_id"`
+	CustomerID  *int64      `json:"customer_id" db:"customer_id"`
+	Status      OrderStatus `json:"status" db:"status"`
+	TotalAmount Money       `json:"total_amount" db:"total_amount"`
+	ItemCount   int64       `json:"item_count" db:"item_count"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+}
+
+// OrderFilter defines the filters, sorting and page of an order listing.
+type OrderFilter struct {
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SKU         string
+	MinTotal    *Money
+	MaxTotal    *Money
+	Status      OrderStatus
+	SortBy      string
+	SortDesc    bool
+	AfterValue  interface{}
+	AfterID     int64
+	Limit       int
+}
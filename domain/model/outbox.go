@@ -0,0 +1,121 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// OutboxEventType defines the domain event an outbox event carries.
+type OutboxEventType string
+
+// List of outbox event type.
+const (
+	OutboxEventOrderCreated        OutboxEventType = "order.created"
+	OutboxEventOrderCancelled      OutboxEventType = "order.cancelled"
+	OutboxEventOrderStatusChanged  OutboxEventType = "order.status_changed"
+	OutboxEventProductCreated      OutboxEventType = "product.created"
+	OutboxEventProductUpdated      OutboxEventType = "product.updated"
+	OutboxEventProductDeleted      OutboxEventType = "product.deleted"
+	OutboxEventProductRestored     OutboxEventType = "product.restored"
+	OutboxEventProductLowStock     OutboxEventType = "product.low_stock"
+	OutboxEventProductPriceChanged OutboxEventType = "product.price_changed"
+	OutboxEventProductBackInStock  OutboxEventType = "product.back_in_stock"
+	OutboxEventBackInStockAlert    OutboxEventType = "notification.back_in_stock"
+	OutboxEventPriceDropAlert      OutboxEventType = "notification.price_drop"
+	OutboxEventCartReminder        OutboxEventType = "reminder.cart_abandoned"
+	OutboxEventOrderReminder       OutboxEventType = "reminder.order_unpaid"
+	OutboxEventBrandCreated        OutboxEventType = "brand.created"
+	OutboxEventBrandUpdated        OutboxEventType = "brand.updated"
+	OutboxEventBrandDeleted        OutboxEventType = "brand.deleted"
+	OutboxEventBrandRestored       OutboxEventType = "brand.restored"
+)
+
+// OutboxStatus defines the delivery state of an outbox event.
+type OutboxStatus string
+
+// List of outbox status.
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	// OutboxStatusDead is an event that failed every delivery attempt, it is moved
+	// to the dead letters instead of being retried.
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// OutboxEvent contains a domain event written in the same database transaction as
+// the change it describes, waiting to be delivered to the notification channels.
+// DeliveredChannels is the comma separated names of the channels that already
+// received the event, so a retry only goes to the channels that failed.
+// OriginEventID is set on an event put back from the dead letters, it is the ID
+// of the first event so receivers see the retry as the same event.
+type OutboxEvent struct {
+	ID                int64           `json:"id" db:"id"`
+	OriginEventID     sql.NullInt64   `json:"-" db:"origin_event_id"`
+	EventType         OutboxEventType `json:"event_type" db:"event_type"`
+	AggregateID       string          `json:"aggregate_id" db:"aggregate_id"`
+	Payload           json.RawMessage `json:"payload" db:"payload"`
+	Status            OutboxStatus    `json:"status" db:"status"`
+	Attempts          int64           `json:"attempts" db:"attempts"`
+	DeliveredChannels string          `json:"delivered_channels" db:"delivered_channels"`
+	LastError         sql.NullString  `json:"-" db:"last_error"`
+	NextAttemptAt     time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	SentAt            sql.NullTime    `json:"-" db:"sent_at"`
+	UpdatedAt         sql.NullTime    `json:"-" db:"updated_at"`
+}
+
+// OriginID returns the ID receivers know the event by, the ID of the first event
+// when it is a retry of a dead letter.
+func (e *OutboxEvent) OriginID() int64 {
+	if e.OriginEventID.Valid {
+		return e.OriginEventID.Int64
+	}
+	return e.ID
+}
+
+// OrderEvent is the payload of order events.
+type OrderEvent struct {
+	OrderID     string                 `json:"order_id"`
+	CustomerID  int64                  `json:"customer_id,omitempty"`
+	Status      OrderStatus            `json:"status"`
+	TotalAmount Money                  `json:"total_amount"`
+	Items       []TransactionItemPrice `json:"items"`
+}
+
+// ProductEvent is the payload of product events.
+type ProductEvent struct {
+	ID      int64         `json:"id"`
+	BrandID int64         `json:"brand_id"`
+	SKU     string        `json:"sku"`
+	Name    string        `json:"name,omitempty"`
+	Stock   int64         `json:"stock"`
+	Price   Money         `json:"price"`
+	Status  ProductStatus `json:"status,omitempty"`
+	Version int64         `json:"version,omitempty"`
+}
+
+// LowStockEvent is the payload of product.low_stock, sent when the stock of a
+// product falls to the threshold or below.
+type LowStockEvent struct {
+	ProductID int64  `json:"product_id"`
+	SKU       string `json:"sku"`
+	Stock     int64  `json:"stock"`
+	Threshold int64  `json:"threshold"`
+}
+
+// PriceChangedEvent is the payload of product.price_changed.
+type PriceChangedEvent struct {
+	ProductID int64  `json:"product_id"`
+	SKU       string `json:"sku"`
+	OldPrice  Money  `json:"old_price"`
+	NewPrice  Money  `json:"new_price"`
+}
+
+// BrandEvent is the payload of brand events. ProductCount is how many products
+// were deleted or restored along with the brand.
+type BrandEvent struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	ProductCount int64  `json:"product_count,omitempty"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PriceWatchStatus defines the state of a price watch.
+type PriceWatchStatus string
+
+// List of price watch status.
+const (
+	PriceWatchActive    PriceWatchStatus = "active"
+	PriceWatchCancelled PriceWatchStatus = "cancelled"
+)
+
+// PriceWatch is a customer waiting for the price of a product to fall to
+// TargetPrice or below. A watch by percentage keeps the percentage in
+// DropPercent and its target is taken off BasePrice, the price when the watch
+// was saved. LastNotifiedPrice is the price of the last alert, the watch only
+// fires again when the price falls below it.
+type PriceWatch struct {
+	ID                int64            `json:"id" db:"id"`
+	CustomerID        int64            `json:"customer_id" db:"customer_id"`
+	ProductID         int64            `json:"product_id" db:"product_id"`
+	SKU               string           `json:"sku" db:"sku"`
+	BasePrice         Money            `json:"base_price" db:"base_price"`
+	TargetPrice       Money            `json:"target_price" db:"target_price"`
+	DropPercent       *Money           `json:"drop_percent" db:"drop_percent"`
+	LastNotifiedPrice *Money           `json:"last_notified_price" db:"last_notified_price"`
+	Status            PriceWatchStatus `json:"status" db:"status"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         sql.NullTime     `json:"-" db:"updated_at"`
+	NotifiedAt        sql.NullTime     `json:"-" db:"notified_at"`
+	CancelledAt       sql.NullTime     `json:"-" db:"cancelled_at"`
+}
+
+// PriceDropAlertEvent is the payload of notification.price_drop, sent to a
+// customer watching a product whose price fell to the target.
+type PriceDropAlertEvent struct {
+	WatchID     int64  `json:"watch_id"`
+	CustomerID  int64  `json:"customer_id"`
+	ProductID   int64  `json:"product_id"`
+	SKU         string `json:"sku"`
+	OldPrice    Money  `json:"old_price"`
+	NewPrice    Money  `json:"new_price"`
+	TargetPrice Money  `json:"target_price"`
+}
@@ -2,17 +2,82 @@ package model
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
-// Product contains details of product.
+// ProductStatus defines the lifecycle state of a product.
+type ProductStatus string
+
+// List of product status, only active products can be ordered.
+const (
+	ProductStatusDraft        ProductStatus = "draft"
+	ProductStatusActive       ProductStatus = "active"
+	ProductStatusDiscontinued ProductStatus = "discontinued"
+)
+
+// ProductAttributes are the structured attributes of a product, such as its
+// movement or case size, stored as a JSON object.
+type ProductAttributes map[string]string
+
+// Scan reads the attributes from a JSON column.
+func (a *ProductAttributes) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, a)
+	case string:
+		return json.Unmarshal([]byte(value), a)
+	case nil:
+		*a = nil
+		return nil
+	default:
+		return fmt.Errorf("can not scan %T into ProductAttributes", src)
+	}
+}
+
+// Value writes the attributes to a JSON column, no attributes are stored as NULL.
+func (a ProductAttributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(a)
+	return string(raw), err
+}
+
+// Product contains details of product. Version is increased by every update, so
+// a client can tell whether the product changed since it was read.
 type Product struct {
-	ID        int64        `json:"id" db:"id"`
-	SKU       string       `json:"sku" db:"sku"`
-	BrandID   int64        `json:"brand_id" db:"brand_id"`
-	Stock     int64        `json:"stock" db:"stock"`
-	Price     float64      `json:"pric" db:"price"`
-	CreatedAt time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt sql.NullTime `json:"updated_at" db:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID          int64             `json:"id" db:"id"`
+	SKU         string            `json:"sku" db:"sku"`
+	Name        string            `json:"name" db:"name"`
+	Slug        string            `json:"slug" db:"slug"`
+	Description sql.NullString    `json:"description" db:"description"`
+	Attributes  ProductAttributes `json:"attributes" db:"attributes"`
+	BrandID     int64             `json:"brand_id" db:"brand_id"`
+	Stock       int64             `json:"stock" db:"stock"`
+	Price       Money             `json:"pric" db:"price"`
+	Version     int64             `json:"version" db:"version"`
+	Status      ProductStatus     `json:"status" db:"status"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   sql.NullTime      `json:"updated_at" db:"updated_at"`
+	DeletedAt   sql.NullTime      `json:"deleted_at" db:"deleted_at"`
+}
+
+// ProductFilter defines the filters, sorting and page of a product listing.
+type ProductFilter struct {
+	BrandID     int64
+	MinPrice    *Money
+	MaxPrice    *Money
+	InStock     bool
+	SKUPrefix   string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SortBy      string
+	SortDesc    bool
+	AfterValue  interface{}
+	AfterID     int64
+	Limit       int
 }
@@ -0,0 +1,33 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductAttributesSQL(t *testing.T) {
+	var attributes model.ProductAttributes
+
+	// Case: JSON column is read as bytes
+	err := attributes.Scan([]byte(`{"movement":"automatic","case":"42mm"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, model.ProductAttributes{"movement": "automatic", "case": "42mm"}, attributes)
+
+	value, err := attributes.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"case":"42mm","movement":"automatic"}`, value)
+
+	// Case: no attributes are stored as NULL
+	err = attributes.Scan(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, attributes)
+
+	value, err = model.ProductAttributes{}.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	err = attributes.Scan(true)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PromotionType defines how a promotion computes its discount.
+type PromotionType string
+
+// List of promotion type.
+const (
+	// PromotionTypePercentage takes Value percent off the eligible amount.
+	PromotionTypePercentage PromotionType = "percentage"
+	// PromotionTypeFixedAmount takes Value off the eligible amount.
+	PromotionTypeFixedAmount PromotionType = "fixed_amount"
+	// PromotionTypeBuyXGetY gives GetQuantity of every BuyQuantity+GetQuantity eligible units
+	// for free, the cheapest units first.
+	PromotionTypeBuyXGetY PromotionType = "buy_x_get_y"
+)
+
+// PromotionScope defines which order lines a promotion applies to.
+type PromotionScope string
+
+// List of promotion scope.
+const (
+	PromotionScopeOrder PromotionScope = "order"
+	PromotionScopeBrand PromotionScope = "brand"
+	PromotionScopeSKU   PromotionScope = "sku"
+)
+
+// Promotion contains details of promotion. A promotion with a code is a coupon
+// applied on request, one without a code is applied automatically.
+type Promotion struct {
+	ID               int64          `json:"id" db:"id"`
+	Code             sql.NullString `json:"-" db:"code"`
+	Name             string         `json:"name" db:"name"`
+	Type             PromotionType  `json:"type" db:"type"`
+	Value            Money          `json:"value" db:"value"`
+	BuyQuantity      int64          `json:"buy_quantity" db:"buy_quantity"`
+	GetQuantity      int64          `json:"get_quantity" db:"get_quantity"`
+	Scope            PromotionScope `json:"scope" db:"scope"`
+	BrandID          sql.NullInt64  `json:"-" db:"brand_id"`
+	SKU              sql.NullString `json:"-" db:"sku"`
+	Stackable        bool           `json:"stackable" db:"stackable"`
+	Priority         int64          `json:"priority" db:"priority"`
+	StartsAt         sql.NullTime   `json:"-" db:"starts_at"`
+	EndsAt           sql.NullTime   `json:"-" db:"ends_at"`
+	UsageLimit       sql.NullInt64  `json:"-" db:"usage_limit"`
+	PerCustomerLimit sql.NullInt64  `json:"-" db:"per_customer_limit"`
+	UsageCount       int64          `json:"usage_count" db:"usage_count"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"-" db:"updated_at"`
+	DeletedAt        sql.NullTime   `json:"-" db:"deleted_at"`
+}
+
+// PromotionUsage contains a promotion applied to an order.
+type PromotionUsage struct {
+	ID             int64         `db:"id"`
+	PromotionID    int64         `db:"promotion_id"`
+	OrderID        string        `db:"order_id"`
+	CustomerID     sql.NullInt64 `db:"customer_id"`
+	DiscountAmount Money         `db:"discount_amount"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ReminderKind defines what a reminder is about.
+type ReminderKind string
+
+// List of reminder kind.
+const (
+	ReminderKindCart  ReminderKind = "cart"
+	ReminderKindOrder ReminderKind = "order"
+)
+
+// ReminderStatus defines the state of a reminder sequence.
+type ReminderStatus string
+
+// List of reminder status.
+const (
+	ReminderStatusActive ReminderStatus = "active"
+	// ReminderStatusFinished is a sequence that sent every reminder.
+	ReminderStatusFinished ReminderStatus = "finished"
+	// ReminderStatusStopped is a sequence whose cart was checked out, or whose
+	// order is no longer waiting for payment.
+	ReminderStatusStopped      ReminderStatus = "stopped"
+	ReminderStatusUnsubscribed ReminderStatus = "unsubscribed"
+)
+
+// Reminder is the reminder sequence of an idle cart or an unpaid order. Step is
+// the number of reminders sent, the next one is due at NextAt, counted from
+// IdleSince, the last activity on the target.
+type Reminder struct {
+	ID               int64          `json:"id" db:"id"`
+	Kind             ReminderKind   `json:"kind" db:"kind"`
+	TargetID         string         `json:"target_id" db:"target_id"`
+	CustomerID       int64          `json:"customer_id" db:"customer_id"`
+	Step             int            `json:"step" db:"step"`
+	IdleSince        time.Time      `json:"idle_since" db:"idle_since"`
+	NextAt           time.Time      `json:"next_at" db:"next_at"`
+	Status           ReminderStatus `json:"status" db:"status"`
+	UnsubscribeToken string         `json:"-" db:"unsubscribe_token"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"-" db:"updated_at"`
+	LastSentAt       sql.NullTime   `json:"-" db:"last_sent_at"`
+}
+
+// ReminderTarget is an idle cart or unpaid order without a reminder sequence yet.
+type ReminderTarget struct {
+	TargetID       string    `db:"target_id"`
+	CustomerID     int64     `db:"customer_id"`
+	LastActivityAt time.Time `db:"last_activity_at"`
+}
+
+// ReminderEvent is the payload of reminder events.
+type ReminderEvent struct {
+	ReminderID     int64        `json:"reminder_id"`
+	CustomerID     int64        `json:"customer_id"`
+	Kind           ReminderKind `json:"kind"`
+	CartID         string       `json:"cart_id,omitempty"`
+	OrderID        string       `json:"order_id,omitempty"`
+	Step           int          `json:"step"`
+	IdleSince      time.Time    `json:"idle_since"`
+	UnsubscribeURL string       `json:"unsubscribe_url"`
+}
@@ -5,14 +5,26 @@ import (
 	"time"
 )
 
+// TransactionType defines the kind of a transaction row.
+type TransactionType string
+
+// List of transaction type, void and refund rows reverse a sale with negative quantity.
+const (
+	TransactionTypeSale   TransactionType = "sale"
+	TransactionTypeVoid   TransactionType = "void"
+	TransactionTypeRefund TransactionType = "refund"
+)
+
 // Transaction contains detail of transaction.
 type Transaction struct {
-	ID        int64        `json:"id" db:"id"`
-	SKU       string       `json:"sku" db:"sku"`
-	Quantity  int64        `json:"quantity" db:"quantity"`
-	OrderID   string       `json:"order_id" db:"order_id"`
-	Subtotal  float64      `json:"subtotal" db:"subtotal"`
-	CreatedAt time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt sql.NullTime `json:"updated_at" db:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID             int64           `json:"id" db:"id"`
+	SKU            string          `json:"sku" db:"sku"`
+	Quantity       int64           `json:"quantity" db:"quantity"`
+	OrderID        string          `json:"order_id" db:"order_id"`
+	Subtotal       Money           `json:"subtotal" db:"subtotal"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      sql.NullTime    `json:"updated_at" db:"updated_at"`
+	DeletedAt      sql.NullTime    `json:"deleted_at" db:"deleted_at"`
+	Type           TransactionType `json:"type" db:"type"`
+	DiscountAmount Money           `json:"discount_amount" db:"discount_amount"`
 }
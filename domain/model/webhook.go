@@ -0,0 +1,58 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WebhookAllEvents subscribes a webhook to every event type.
+const WebhookAllEvents = "*"
+
+// WebhookDeliveryStatus defines the result of a webhook delivery attempt.
+type WebhookDeliveryStatus string
+
+// List of webhook delivery status.
+const (
+	WebhookDeliverySuccess WebhookDeliveryStatus = "success"
+	WebhookDeliveryFailed  WebhookDeliveryStatus = "failed"
+)
+
+// WebhookSubscription contains an endpoint of a partner notified of events.
+// EventTypes is the comma separated event types it receives.
+type WebhookSubscription struct {
+	ID                  int64        `json:"id" db:"id"`
+	URL                 string       `json:"url" db:"url"`
+	EventTypes          string       `json:"event_types" db:"event_types"`
+	Secret              string       `json:"-" db:"secret"`
+	Active              bool         `json:"active" db:"active"`
+	ConsecutiveFailures int64        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          sql.NullTime `json:"-" db:"disabled_at"`
+	CreatedAt           time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt           sql.NullTime `json:"-" db:"updated_at"`
+	DeletedAt           sql.NullTime `json:"-" db:"deleted_at"`
+}
+
+// WebhookDelivery contains one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id" db:"id"`
+	SubscriptionID int64                 `json:"subscription_id" db:"subscription_id"`
+	EventID        int64                 `json:"event_id" db:"event_id"`
+	EventType      OutboxEventType       `json:"event_type" db:"event_type"`
+	Attempt        int64                 `json:"attempt" db:"attempt"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	ResponseCode   sql.NullInt64         `json:"-" db:"response_code"`
+	ResponseBody   sql.NullString        `json:"-" db:"response_body"`
+	Error          sql.NullString        `json:"-" db:"error"`
+	DurationMS     int64                 `json:"duration_ms" db:"duration_ms"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// WebhookDeliveryFilter defines the filters and page of webhook delivery listing,
+// deliveries are listed newest first.
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	EventID        int64
+	Status         WebhookDeliveryStatus
+	BeforeID       int64
+	Limit          int
+}
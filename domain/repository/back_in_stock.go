@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// BackInStockRepository manages database operations for back_in_stock_subscription.
+type BackInStockRepository interface {
+	Create(subscription *model.BackInStockSubscription) error
+	GetPending(customerID int64, sku string) (*model.BackInStockSubscription, error)
+	ListByCustomerID(customerID int64) ([]*model.BackInStockSubscription, error)
+	Cancel(id int64) error
+	ClaimPendingTx(tx *sqlx.Tx, sku string, limit int64) ([]*model.BackInStockSubscription, error)
+	MarkNotifiedTx(tx *sqlx.Tx, ids []int64) error
+}
+
+type backInStockRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewBackInStockRepository returns new instance of backInStockRepoImpl.
+func NewBackInStockRepository() *backInStockRepoImpl {
+	return &backInStockRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create creates a new pending subscription.
+func (r *backInStockRepoImpl) Create(subscription *model.BackInStockSubscription) error {
+	res, err := r.db.Exec(`
+		INSERT INTO back_in_stock_subscription (customer_id, product_id, sku, status)
+		VALUES (?, ?, ?, ?)`, subscription.CustomerID, subscription.ProductID, subscription.SKU, subscription.Status)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	subscription.ID = id
+
+	return err
+}
+
+// GetPending returns the pending subscription of a customer to a SKU.
+func (r *backInStockRepoImpl) GetPending(customerID int64, sku string) (*model.BackInStockSubscription, error) {
+	res := &model.BackInStockSubscription{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM back_in_stock_subscription
+		WHERE customer_id = ? AND sku = ? AND status = ?
+		LIMIT 1`, customerID, sku, model.BackInStockPending)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// ListByCustomerID returns the subscriptions of a customer that are not cancelled, newest first.
+func (r *backInStockRepoImpl) ListByCustomerID(customerID int64) ([]*model.BackInStockSubscription, error) {
+	res := make([]*model.BackInStockSubscription, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM back_in_stock_subscription
+		WHERE customer_id = ? AND status <> ?
+		ORDER BY id DESC`, customerID, model.BackInStockCancelled)
+	return res, err
+}
+
+// Cancel cancels a pending subscription.
+func (r *backInStockRepoImpl) Cancel(id int64) error {
+	_, err := r.db.Exec(`
+		UPDATE back_in_stock_subscription
+		SET status = ?, cancelled_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?`, model.BackInStockCancelled, id, model.BackInStockPending)
+	return err
+}
+
+// ClaimPendingTx returns the oldest pending subscriptions of a SKU and locks them
+// until the transaction ends, subscriptions locked by another transaction are skipped.
+func (r *backInStockRepoImpl) ClaimPendingTx(tx *sqlx.Tx, sku string, limit int64) ([]*model.BackInStockSubscription, error) {
+	res := make([]*model.BackInStockSubscription, 0)
+	err := tx.Select(&res, `
+		SELECT *
+		FROM back_in_stock_subscription
+		WHERE sku = ? AND status = ?
+		ORDER BY id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, sku, model.BackInStockPending, limit)
+	return res, err
+}
+
+// MarkNotifiedTx marks subscriptions as notified.
+func (r *backInStockRepoImpl) MarkNotifiedTx(tx *sqlx.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	params := []interface{}{model.BackInStockNotified}
+	for _, id := range ids {
+		params = append(params, id)
+	}
+
+	_, err := tx.Exec(`
+		UPDATE back_in_stock_subscription
+		SET status = ?, notified_at = CURRENT_TIMESTAMP
+		WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, params...)
+	return err
+}
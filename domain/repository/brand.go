@@ -2,16 +2,32 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/pkg/database"
 )
 
+// ErrDuplicateBrandName is returned when a brand is saved with the name of
+// another brand, deleted brands included.
+var ErrDuplicateBrandName = errors.New("brand name already exists")
+
 // BrandRepository manages database operations for brand.
 type BrandRepository interface {
 	Create(brand string) (int64, error)
-	GetByID(id int64) (*model.Brand, error)
+	CreateTx(tx *sqlx.Tx, brand string) (int64, error)
+	GetByID(id int64, opts ...Option) (*model.Brand, error)
+	GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...Option) (*model.Brand, error)
+	GetSummaryByID(id int64) (*model.BrandSummary, error)
+	List(filter model.BrandFilter) ([]*model.BrandSummary, error)
+	UpdateTx(tx *sqlx.Tx, id int64, name string) error
+	DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error
+	RestoreTx(tx *sqlx.Tx, id int64) error
 }
 
 type brandRepoImpl struct {
@@ -30,7 +46,24 @@ func (r *brandRepoImpl) Create(brand string) (int64, error) {
 	res, err := r.db.Exec(`
 		INSERT INTO brand (name)
 		VALUES (?)`, brand)
-	if err != nil {
+	if isDuplicateEntry(err) {
+		return 0, ErrDuplicateBrandName
+	} else if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	return id, err
+}
+
+// CreateTx creates a new brand inside the given database transaction.
+func (r *brandRepoImpl) CreateTx(tx *sqlx.Tx, brand string) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO brand (name)
+		VALUES (?)`, brand)
+	if isDuplicateEntry(err) {
+		return 0, ErrDuplicateBrandName
+	} else if err != nil {
 		return 0, err
 	}
 
@@ -39,15 +72,120 @@ func (r *brandRepoImpl) Create(brand string) (int64, error) {
 }
 
 // GetByID returns a brand's details by ID.
-func (r *brandRepoImpl) GetByID(id int64) (*model.Brand, error) {
+func (r *brandRepoImpl) GetByID(id int64, opts ...Option) (*model.Brand, error) {
 	res := &model.Brand{}
 	err := r.db.Get(res, `
 		SELECT *
 		FROM brand
-		WHERE id = ?`, id)
+		WHERE id = ? AND `+notDeleted("deleted_at", opts), id)
 	if err == sql.ErrNoRows {
 		res = nil
 		err = nil
 	}
 	return res, err
 }
+
+// GetByIDForUpdate returns a brand's details by ID and locks the row until the
+// transaction ends.
+func (r *brandRepoImpl) GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...Option) (*model.Brand, error) {
+	res := &model.Brand{}
+	err := tx.Get(res, `
+		SELECT *
+		FROM brand
+		WHERE id = ? AND `+notDeleted("deleted_at", opts)+`
+		FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// brandSummaryQuery selects brands with the count and total stock of their
+// active products.
+const brandSummaryQuery = `
+	SELECT b.id, b.name, b.created_at, b.updated_at,
+		COUNT(p.id) AS product_count,
+		COALESCE(SUM(p.stock), 0) AS total_stock
+	FROM brand b
+	LEFT JOIN product p ON p.brand_id = b.id AND p.deleted_at IS NULL`
+
+// GetSummaryByID returns a brand's details with its active products by ID.
+func (r *brandRepoImpl) GetSummaryByID(id int64) (*model.BrandSummary, error) {
+	res := &model.BrandSummary{}
+	err := r.db.Get(res, brandSummaryQuery+`
+		WHERE b.id = ? AND b.deleted_at IS NULL
+		GROUP BY b.id`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// List returns brands whose name contains the filter's name, ordered by name and
+// ID, starting after the row given by AfterName and AfterID.
+func (r *brandRepoImpl) List(filter model.BrandFilter) ([]*model.BrandSummary, error) {
+	conditions := []string{"b.deleted_at IS NULL"}
+	params := make([]interface{}, 0)
+
+	if filter.Name != "" {
+		conditions = append(conditions, "b.name LIKE ?")
+		params = append(params, "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.AfterID != 0 {
+		conditions = append(conditions, "(b.name > ? OR (b.name = ? AND b.id > ?))")
+		params = append(params, filter.AfterName, filter.AfterName, filter.AfterID)
+	}
+
+	params = append(params, filter.Limit)
+
+	res := make([]*model.BrandSummary, 0)
+	err := r.db.Select(&res, fmt.Sprintf(brandSummaryQuery+`
+		WHERE %s
+		GROUP BY b.id
+		ORDER BY b.name, b.id
+		LIMIT ?`, strings.Join(conditions, " AND ")), params...)
+	return res, err
+}
+
+// UpdateTx renames a brand inside the given database transaction.
+func (r *brandRepoImpl) UpdateTx(tx *sqlx.Tx, id int64, name string) error {
+	_, err := tx.Exec(`
+		UPDATE brand
+		SET name = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, name, id)
+	if isDuplicateEntry(err) {
+		return ErrDuplicateBrandName
+	}
+	return err
+}
+
+// DeleteTx soft-deletes a brand at the given time.
+func (r *brandRepoImpl) DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE brand
+		SET deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL`, at, id)
+	return err
+}
+
+// RestoreTx brings back a soft-deleted brand.
+func (r *brandRepoImpl) RestoreTx(tx *sqlx.Tx, id int64) error {
+	_, err := tx.Exec(`
+		UPDATE brand
+		SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, id)
+	return err
+}
+
+// isDuplicateEntry returns true when err is a unique key violation.
+func isDuplicateEntry(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
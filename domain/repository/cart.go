@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// CartRepository manages database operations for cart.
+type CartRepository interface {
+	Create(cart *model.Cart) error
+	GetByCartID(cartID string) (*model.Cart, error)
+	GetItems(cartID string) ([]*model.CartItem, error)
+	SaveItem(item *model.CartItem) error
+	DeleteItem(cartID string, sku string) error
+	Extend(cartID string, expiresAt time.Time) error
+	UpdateStatus(cartID string, from model.CartStatus, to model.CartStatus, orderID string) (bool, error)
+	ReleaseCheckout(cartID string, claimedBefore time.Time) (bool, error)
+}
+
+type cartRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewCartRepository returns new instance of cartRepoImpl.
+func NewCartRepository() *cartRepoImpl {
+	return &cartRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create creates a new cart and store it into the database.
+func (r *cartRepoImpl) Create(cart *model.Cart) error {
+	res, err := r.db.Exec(`
+		INSERT INTO cart (cart_id, customer_id, status, expires_at)
+		VALUES (?, ?, ?, ?)`, cart.CartID, cart.CustomerID, cart.Status, cart.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	cart.ID = id
+
+	return err
+}
+
+// GetByCartID returns cart's details by the cart ID.
+func (r *cartRepoImpl) GetByCartID(cartID string) (*model.Cart, error) {
+	res := &model.Cart{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM cart
+		WHERE cart_id = ? AND deleted_at IS NULL`, cartID)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetItems returns the items of a cart in the order they were added.
+func (r *cartRepoImpl) GetItems(cartID string) ([]*model.CartItem, error) {
+	res := make([]*model.CartItem, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM cart_item
+		WHERE cart_id = ?
+		ORDER BY id`, cartID)
+	return res, err
+}
+
+// SaveItem adds an SKU to a cart, or replaces its quantity and price when it is already in the cart.
+func (r *cartRepoImpl) SaveItem(item *model.CartItem) error {
+	_, err := r.db.Exec(`
+		INSERT INTO cart_item (cart_id, sku, quantity, price)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			quantity = VALUES(quantity), price = VALUES(price), updated_at = CURRENT_TIMESTAMP`,
+		item.CartID, item.SKU, item.Quantity, item.Price)
+	return err
+}
+
+// DeleteItem removes an SKU from a cart.
+func (r *cartRepoImpl) DeleteItem(cartID string, sku string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM cart_item
+		WHERE cart_id = ? AND sku = ?`, cartID, sku)
+	return err
+}
+
+// Extend moves the expiry of a cart.
+func (r *cartRepoImpl) Extend(cartID string, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE cart
+		SET expires_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE cart_id = ?`, expiresAt, cartID)
+	return err
+}
+
+// UpdateStatus moves a cart to another status only when it is still in the expected
+// status, it returns false when the cart has been moved by someone else. The order
+// ID is kept when it is empty.
+func (r *cartRepoImpl) UpdateStatus(cartID string, from model.CartStatus, to model.CartStatus, orderID string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE cart
+		SET status = ?, order_id = COALESCE(NULLIF(?, ''), order_id), updated_at = CURRENT_TIMESTAMP
+		WHERE cart_id = ? AND status = ? AND deleted_at IS NULL`, to, orderID, cartID, from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// ReleaseCheckout moves a cart claimed for checkout before claimedBefore back to
+// active, it returns false when the cart is not such a claim anymore.
+func (r *cartRepoImpl) ReleaseCheckout(cartID string, claimedBefore time.Time) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE cart
+		SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE cart_id = ? AND status = ? AND updated_at < ? AND deleted_at IS NULL`,
+		model.CartStatusActive, cartID, model.CartStatusCheckingOut, claimedBefore)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
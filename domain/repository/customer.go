@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// CustomerRepository manages database operations for customer.
+type CustomerRepository interface {
+	GetByID(id int64) (*model.Customer, error)
+	Save(customer *model.Customer) error
+	ClearPushToken(id int64, token string) error
+	GetByIDForUpdate(tx *sqlx.Tx, id int64) (*model.Customer, error)
+	UpdateNotificationSettingsTx(tx *sqlx.Tx, customer *model.Customer) error
+}
+
+type customerRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewCustomerRepository returns new instance of customerRepoImpl.
+func NewCustomerRepository() *customerRepoImpl {
+	return &customerRepoImpl{
+		db: database.DB,
+	}
+}
+
+// GetByID returns customer's details by ID.
+func (r *customerRepoImpl) GetByID(id int64) (*model.Customer, error) {
+	res := &model.Customer{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM customer
+		WHERE id = ?`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// Save creates a customer or replaces its contact details.
+func (r *customerRepoImpl) Save(customer *model.Customer) error {
+	_, err := r.db.Exec(`
+		INSERT INTO customer (id, email, phone, push_token, locale)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			email = VALUES(email), phone = VALUES(phone), push_token = VALUES(push_token),
+			locale = VALUES(locale), updated_at = CURRENT_TIMESTAMP`,
+		customer.ID, customer.Email, customer.Phone, customer.PushToken, customer.Locale)
+	return err
+}
+
+// ClearPushToken removes customer's push token when it is still token, so a
+// token registered in the meantime is kept.
+func (r *customerRepoImpl) ClearPushToken(id int64, token string) error {
+	_, err := r.db.Exec(`
+		UPDATE customer
+		SET push_token = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND push_token = ?`, id, token)
+	return err
+}
+
+// GetByIDForUpdate returns customer's details by ID and locks the row until the
+// transaction ends.
+func (r *customerRepoImpl) GetByIDForUpdate(tx *sqlx.Tx, id int64) (*model.Customer, error) {
+	res := &model.Customer{}
+	err := tx.Get(res, `
+		SELECT *
+		FROM customer
+		WHERE id = ?
+		FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// UpdateNotificationSettingsTx updates customer's timezone and quiet hours.
+func (r *customerRepoImpl) UpdateNotificationSettingsTx(tx *sqlx.Tx, customer *model.Customer) error {
+	_, err := tx.Exec(`
+		UPDATE customer
+		SET timezone = ?, quiet_hours_start = ?, quiet_hours_end = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, customer.Timezone, customer.QuietHoursStart, customer.QuietHoursEnd, customer.ID)
+	return err
+}
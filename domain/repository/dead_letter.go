@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// DeadLetterRepository manages database operations for dead_letter.
+type DeadLetterRepository interface {
+	CreateTx(tx *sqlx.Tx, letter *model.DeadLetter) error
+	List(filter model.DeadLetterFilter) ([]*model.DeadLetter, error)
+	GetByID(id int64) (*model.DeadLetter, error)
+	GetByIDForUpdate(tx *sqlx.Tx, id int64) (*model.DeadLetter, error)
+	MarkRetriedTx(tx *sqlx.Tx, letter *model.DeadLetter) error
+	Purge(purge model.DeadLetterPurge) (int64, error)
+}
+
+type deadLetterRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewDeadLetterRepository returns new instance of deadLetterRepoImpl.
+func NewDeadLetterRepository() *deadLetterRepoImpl {
+	return &deadLetterRepoImpl{
+		db: database.DB,
+	}
+}
+
+// CreateTx stores the dead letter of an outbox event, an event that is already a
+// dead letter is left as it is.
+func (r *deadLetterRepoImpl) CreateTx(tx *sqlx.Tx, letter *model.DeadLetter) error {
+	_, err := tx.Exec(`
+		INSERT IGNORE INTO dead_letter (event_id, origin_event_id, event_type, aggregate_id, payload, attempts,
+			delivered_channels, last_error, status, event_created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, letter.EventID, letter.OriginEventID, letter.EventType, letter.AggregateID,
+		string(letter.Payload), letter.Attempts, letter.DeliveredChannels, letter.LastError, letter.Status,
+		letter.EventCreatedAt)
+	return err
+}
+
+// List returns a page of dead letters matching the filter, newest first.
+func (r *deadLetterRepoImpl) List(filter model.DeadLetterFilter) ([]*model.DeadLetter, error) {
+	conditions := []string{"1 = 1"}
+	params := make([]interface{}, 0)
+
+	if filter.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		params = append(params, filter.EventType)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		params = append(params, filter.Status)
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		params = append(params, filter.BeforeID)
+	}
+	params = append(params, filter.Limit)
+
+	res := make([]*model.DeadLetter, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM dead_letter
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY id DESC
+		LIMIT ?`, params...)
+	return res, err
+}
+
+// GetByID returns dead letter's details by ID.
+func (r *deadLetterRepoImpl) GetByID(id int64) (*model.DeadLetter, error) {
+	res := &model.DeadLetter{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM dead_letter
+		WHERE id = ?`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetByIDForUpdate returns dead letter's details by ID and locks it until the transaction ends.
+func (r *deadLetterRepoImpl) GetByIDForUpdate(tx *sqlx.Tx, id int64) (*model.DeadLetter, error) {
+	res := &model.DeadLetter{}
+	err := tx.Get(res, `
+		SELECT *
+		FROM dead_letter
+		WHERE id = ?
+		FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// MarkRetriedTx records the outbox event a dead letter was retried as, with the
+// payload it was retried with.
+func (r *deadLetterRepoImpl) MarkRetriedTx(tx *sqlx.Tx, letter *model.DeadLetter) error {
+	_, err := tx.Exec(`
+		UPDATE dead_letter
+		SET status = ?, payload = ?, retried_event_id = ?, retried_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, model.DeadLetterStatusRetried, string(letter.Payload), letter.RetriedEventID, letter.ID)
+	return err
+}
+
+// Purge removes the dead letters matching every set field and returns how many were removed.
+func (r *deadLetterRepoImpl) Purge(purge model.DeadLetterPurge) (int64, error) {
+	conditions := []string{"1 = 1"}
+	params := make([]interface{}, 0)
+
+	if len(purge.IDs) > 0 {
+		conditions = append(conditions, "id IN (?"+strings.Repeat(", ?", len(purge.IDs)-1)+")")
+		for _, id := range purge.IDs {
+			params = append(params, id)
+		}
+	}
+	if purge.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		params = append(params, purge.EventType)
+	}
+	if purge.Status != "" {
+		conditions = append(conditions, "status = ?")
+		params = append(params, purge.Status)
+	}
+	if purge.Before != nil {
+		conditions = append(conditions, "created_at < ?")
+		params = append(params, *purge.Before)
+	}
+
+	res, err := r.db.Exec(`
+		DELETE FROM dead_letter
+		WHERE `+strings.Join(conditions, " AND "), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
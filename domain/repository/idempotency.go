@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for unique key violation.
+const mysqlDuplicateEntry = 1062
+
+// IdempotencyRepository manages database operations for idempotency key.
+type IdempotencyRepository interface {
+	Create(key *model.IdempotencyKey) (bool, error)
+	GetByKey(key string) (*model.IdempotencyKey, error)
+	TakeOver(key string, fingerprint string, lockedUntil time.Time, now time.Time) (bool, error)
+	SaveResponse(key string, code int, body string) error
+	Delete(key string) error
+	PurgeExpired(now time.Time, limit int) (int64, error)
+}
+
+type idempotencyRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyRepository returns new instance of idempotencyRepoImpl.
+func NewIdempotencyRepository() *idempotencyRepoImpl {
+	return &idempotencyRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create stores a new idempotency key, it returns false when the key already exists.
+func (r *idempotencyRepoImpl) Create(key *model.IdempotencyKey) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO idempotency_key (idempotency_key, fingerprint, locked_until, expires_at)
+		VALUES (?, ?, ?, ?)`, key.Key, key.Fingerprint, key.LockedUntil, key.ExpiresAt)
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == mysqlDuplicateEntry {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	id, err := res.LastInsertId()
+	key.ID = id
+
+	return true, err
+}
+
+// GetByKey returns idempotency key's details by the key.
+func (r *idempotencyRepoImpl) GetByKey(key string) (*model.IdempotencyKey, error) {
+	res := &model.IdempotencyKey{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM idempotency_key
+		WHERE idempotency_key = ?`, key)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// TakeOver locks a key whose request stopped without storing a response, when
+// its lock is over by now. It returns false when the key has a response, is locked
+// by another request or was made for a different request.
+func (r *idempotencyRepoImpl) TakeOver(key string, fingerprint string, lockedUntil time.Time, now time.Time) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE idempotency_key
+		SET locked_until = ?
+		WHERE idempotency_key = ? AND fingerprint = ? AND response_code IS NULL
+			AND (locked_until IS NULL OR locked_until <= ?) AND expires_at > ?`,
+		lockedUntil, key, fingerprint, now, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// SaveResponse stores the response of the request made with the key and releases its lock.
+func (r *idempotencyRepoImpl) SaveResponse(key string, code int, body string) error {
+	_, err := r.db.Exec(`
+		UPDATE idempotency_key
+		SET response_code = ?, response_body = ?, locked_until = NULL
+		WHERE idempotency_key = ?`, code, body, key)
+	return err
+}
+
+// Delete deletes an idempotency key.
+func (r *idempotencyRepoImpl) Delete(key string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM idempotency_key
+		WHERE idempotency_key = ?`, key)
+	return err
+}
+
+// PurgeExpired deletes at most limit keys expired by now and returns how many were deleted.
+func (r *idempotencyRepoImpl) PurgeExpired(now time.Time, limit int) (int64, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM idempotency_key
+		WHERE expires_at <= ?
+		ORDER BY expires_at
+		LIMIT ?`, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,126 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+
+	sqlx "github.com/jmoiron/sqlx"
+)
+
+// BackInStockRepository is an autogenerated mock type for the BackInStockRepository type
+type BackInStockRepository struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: id
+func (_m *BackInStockRepository) Cancel(id int64) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimPendingTx provides a mock function with given fields: tx, sku, limit
+func (_m *BackInStockRepository) ClaimPendingTx(tx *sqlx.Tx, sku string, limit int64) ([]*model.BackInStockSubscription, error) {
+	ret := _m.Called(tx, sku, limit)
+
+	var r0 []*model.BackInStockSubscription
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, string, int64) []*model.BackInStockSubscription); ok {
+		r0 = rf(tx, sku, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BackInStockSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, string, int64) error); ok {
+		r1 = rf(tx, sku, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: subscription
+func (_m *BackInStockRepository) Create(subscription *model.BackInStockSubscription) error {
+	ret := _m.Called(subscription)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.BackInStockSubscription) error); ok {
+		r0 = rf(subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPending provides a mock function with given fields: customerID, sku
+func (_m *BackInStockRepository) GetPending(customerID int64, sku string) (*model.BackInStockSubscription, error) {
+	ret := _m.Called(customerID, sku)
+
+	var r0 *model.BackInStockSubscription
+	if rf, ok := ret.Get(0).(func(int64, string) *model.BackInStockSubscription); ok {
+		r0 = rf(customerID, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BackInStockSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(customerID, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByCustomerID provides a mock function with given fields: customerID
+func (_m *BackInStockRepository) ListByCustomerID(customerID int64) ([]*model.BackInStockSubscription, error) {
+	ret := _m.Called(customerID)
+
+	var r0 []*model.BackInStockSubscription
+	if rf, ok := ret.Get(0).(func(int64) []*model.BackInStockSubscription); ok {
+		r0 = rf(customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BackInStockSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkNotifiedTx provides a mock function with given fields: tx, ids
+func (_m *BackInStockRepository) MarkNotifiedTx(tx *sqlx.Tx, ids []int64) error {
+	ret := _m.Called(tx, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, []int64) error); ok {
+		r0 = rf(tx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
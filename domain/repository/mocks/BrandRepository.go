@@ -3,7 +3,11 @@
 package mocks
 
 import (
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
 	model "github.com/richardsahvic/jamtangan/domain/model"
+	repository "github.com/richardsahvic/jamtangan/domain/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -33,19 +37,114 @@ func (_m *BrandRepository) Create(brand string) (int64, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *BrandRepository) GetByID(id int64) (*model.Brand, error) {
-	ret := _m.Called(id)
+// CreateTx provides a mock function with given fields: tx, brand
+func (_m *BrandRepository) CreateTx(tx *sqlx.Tx, brand string) (int64, error) {
+	ret := _m.Called(tx, brand)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, string) int64); ok {
+		r0 = rf(tx, brand)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, string) error); ok {
+		r1 = rf(tx, brand)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTx provides a mock function with given fields: tx, id, at
+func (_m *BrandRepository) DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error {
+	ret := _m.Called(tx, id, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, time.Time) error); ok {
+		r0 = rf(tx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: id, opts
+func (_m *BrandRepository) GetByID(id int64, opts ...repository.Option) (*model.Brand, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 *model.Brand
-	if rf, ok := ret.Get(0).(func(int64) *model.Brand); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, ...repository.Option) *model.Brand); ok {
+		r0 = rf(id, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Brand)
 		}
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, ...repository.Option) error); ok {
+		r1 = rf(id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDForUpdate provides a mock function with given fields: tx, id, opts
+func (_m *BrandRepository) GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...repository.Option) (*model.Brand, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, tx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *model.Brand
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, ...repository.Option) *model.Brand); ok {
+		r0 = rf(tx, id, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Brand)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64, ...repository.Option) error); ok {
+		r1 = rf(tx, id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSummaryByID provides a mock function with given fields: id
+func (_m *BrandRepository) GetSummaryByID(id int64) (*model.BrandSummary, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.BrandSummary
+	if rf, ok := ret.Get(0).(func(int64) *model.BrandSummary); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BrandSummary)
+		}
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(int64) error); ok {
 		r1 = rf(id)
@@ -55,3 +154,54 @@ func (_m *BrandRepository) GetByID(id int64) (*model.Brand, error) {
 
 	return r0, r1
 }
+
+// List provides a mock function with given fields: filter
+func (_m *BrandRepository) List(filter model.BrandFilter) ([]*model.BrandSummary, error) {
+	ret := _m.Called(filter)
+
+	var r0 []*model.BrandSummary
+	if rf, ok := ret.Get(0).(func(model.BrandFilter) []*model.BrandSummary); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BrandSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.BrandFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTx provides a mock function with given fields: tx, id
+func (_m *BrandRepository) RestoreTx(tx *sqlx.Tx, id int64) error {
+	ret := _m.Called(tx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64) error); ok {
+		r0 = rf(tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTx provides a mock function with given fields: tx, id, name
+func (_m *BrandRepository) UpdateTx(tx *sqlx.Tx, id int64, name string) error {
+	ret := _m.Called(tx, id, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, string) error); ok {
+		r0 = rf(tx, id, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
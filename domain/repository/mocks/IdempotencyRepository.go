@@ -0,0 +1,129 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: key
+func (_m *IdempotencyRepository) Create(key *model.IdempotencyKey) (bool, error) {
+	ret := _m.Called(key)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*model.IdempotencyKey) bool); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.IdempotencyKey) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: key
+func (_m *IdempotencyRepository) Delete(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByKey provides a mock function with given fields: key
+func (_m *IdempotencyRepository) GetByKey(key string) (*model.IdempotencyKey, error) {
+	ret := _m.Called(key)
+
+	var r0 *model.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(string) *model.IdempotencyKey); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IdempotencyKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpired provides a mock function with given fields: now, limit
+func (_m *IdempotencyRepository) PurgeExpired(now time.Time, limit int) (int64, error) {
+	ret := _m.Called(now, limit)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(time.Time, int) int64); ok {
+		r0 = rf(now, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveResponse provides a mock function with given fields: key, code, body
+func (_m *IdempotencyRepository) SaveResponse(key string, code int, body string) error {
+	ret := _m.Called(key, code, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string) error); ok {
+		r0 = rf(key, code, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TakeOver provides a mock function with given fields: key, fingerprint, lockedUntil, now
+func (_m *IdempotencyRepository) TakeOver(key string, fingerprint string, lockedUntil time.Time, now time.Time) (bool, error) {
+	ret := _m.Called(key, fingerprint, lockedUntil, now)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(key, fingerprint, lockedUntil, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time, time.Time) error); ok {
+		r1 = rf(key, fingerprint, lockedUntil, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
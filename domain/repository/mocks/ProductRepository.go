@@ -3,7 +3,11 @@
 package mocks
 
 import (
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
 	model "github.com/richardsahvic/jamtangan/domain/model"
+	repository "github.com/richardsahvic/jamtangan/domain/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -12,6 +16,27 @@ type ProductRepository struct {
 	mock.Mock
 }
 
+// CountByBrandIDTx provides a mock function with given fields: tx, brandID
+func (_m *ProductRepository) CountByBrandIDTx(tx *sqlx.Tx, brandID int64) (int64, error) {
+	ret := _m.Called(tx, brandID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64) int64); ok {
+		r0 = rf(tx, brandID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64) error); ok {
+		r1 = rf(tx, brandID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: product
 func (_m *ProductRepository) Create(product *model.Product) error {
 	ret := _m.Called(product)
@@ -26,13 +51,69 @@ func (_m *ProductRepository) Create(product *model.Product) error {
 	return r0
 }
 
-// GetByBrandID provides a mock function with given fields: brandID
-func (_m *ProductRepository) GetByBrandID(brandID int64) ([]*model.Product, error) {
-	ret := _m.Called(brandID)
+// CreateTx provides a mock function with given fields: tx, product
+func (_m *ProductRepository) CreateTx(tx *sqlx.Tx, product *model.Product) error {
+	ret := _m.Called(tx, product)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, *model.Product) error); ok {
+		r0 = rf(tx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByBrandIDTx provides a mock function with given fields: tx, brandID, at
+func (_m *ProductRepository) DeleteByBrandIDTx(tx *sqlx.Tx, brandID int64, at time.Time) (int64, error) {
+	ret := _m.Called(tx, brandID, at)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, time.Time) int64); ok {
+		r0 = rf(tx, brandID, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64, time.Time) error); ok {
+		r1 = rf(tx, brandID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTx provides a mock function with given fields: tx, id, at
+func (_m *ProductRepository) DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error {
+	ret := _m.Called(tx, id, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, time.Time) error); ok {
+		r0 = rf(tx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByBrandID provides a mock function with given fields: brandID, opts
+func (_m *ProductRepository) GetByBrandID(brandID int64, opts ...repository.Option) ([]*model.Product, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, brandID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*model.Product
-	if rf, ok := ret.Get(0).(func(int64) []*model.Product); ok {
-		r0 = rf(brandID)
+	if rf, ok := ret.Get(0).(func(int64, ...repository.Option) []*model.Product); ok {
+		r0 = rf(brandID, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Product)
@@ -40,8 +121,38 @@ func (_m *ProductRepository) GetByBrandID(brandID int64) ([]*model.Product, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(brandID)
+	if rf, ok := ret.Get(1).(func(int64, ...repository.Option) error); ok {
+		r1 = rf(brandID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: id, opts
+func (_m *ProductRepository) GetByID(id int64, opts ...repository.Option) (*model.Product, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *model.Product
+	if rf, ok := ret.Get(0).(func(int64, ...repository.Option) *model.Product); ok {
+		r0 = rf(id, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, ...repository.Option) error); ok {
+		r1 = rf(id, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,13 +160,20 @@ func (_m *ProductRepository) GetByBrandID(brandID int64) ([]*model.Product, erro
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *ProductRepository) GetByID(id int64) (*model.Product, error) {
-	ret := _m.Called(id)
+// GetByIDForUpdate provides a mock function with given fields: tx, id, opts
+func (_m *ProductRepository) GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...repository.Option) (*model.Product, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, tx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 *model.Product
-	if rf, ok := ret.Get(0).(func(int64) *model.Product); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, ...repository.Option) *model.Product); ok {
+		r0 = rf(tx, id, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Product)
@@ -63,8 +181,8 @@ func (_m *ProductRepository) GetByID(id int64) (*model.Product, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64, ...repository.Option) error); ok {
+		r1 = rf(tx, id, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,13 +190,20 @@ func (_m *ProductRepository) GetByID(id int64) (*model.Product, error) {
 	return r0, r1
 }
 
-// GetBySKU provides a mock function with given fields: sku
-func (_m *ProductRepository) GetBySKU(sku string) (*model.Product, error) {
-	ret := _m.Called(sku)
+// GetBySKU provides a mock function with given fields: sku, opts
+func (_m *ProductRepository) GetBySKU(sku string, opts ...repository.Option) (*model.Product, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, sku)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 *model.Product
-	if rf, ok := ret.Get(0).(func(string) *model.Product); ok {
-		r0 = rf(sku)
+	if rf, ok := ret.Get(0).(func(string, ...repository.Option) *model.Product); ok {
+		r0 = rf(sku, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Product)
@@ -86,11 +211,164 @@ func (_m *ProductRepository) GetBySKU(sku string) (*model.Product, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(sku)
+	if rf, ok := ret.Get(1).(func(string, ...repository.Option) error); ok {
+		r1 = rf(sku, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySKUs provides a mock function with given fields: skus
+func (_m *ProductRepository) GetBySKUs(skus []string) ([]*model.Product, error) {
+	ret := _m.Called(skus)
+
+	var r0 []*model.Product
+	if rf, ok := ret.Get(0).(func([]string) []*model.Product); ok {
+		r0 = rf(skus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(skus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySKUsForUpdate provides a mock function with given fields: tx, skus, opts
+func (_m *ProductRepository) GetBySKUsForUpdate(tx *sqlx.Tx, skus []string, opts ...repository.Option) ([]*model.Product, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, tx, skus)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*model.Product
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, []string, ...repository.Option) []*model.Product); ok {
+		r0 = rf(tx, skus, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, []string, ...repository.Option) error); ok {
+		r1 = rf(tx, skus, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: filter
+func (_m *ProductRepository) List(filter model.ProductFilter) ([]*model.Product, error) {
+	ret := _m.Called(filter)
+
+	var r0 []*model.Product
+	if rf, ok := ret.Get(0).(func(model.ProductFilter) []*model.Product); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.ProductFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreByBrandIDTx provides a mock function with given fields: tx, brandID, deletedAt
+func (_m *ProductRepository) RestoreByBrandIDTx(tx *sqlx.Tx, brandID int64, deletedAt time.Time) (int64, error) {
+	ret := _m.Called(tx, brandID, deletedAt)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, time.Time) int64); ok {
+		r0 = rf(tx, brandID, deletedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64, time.Time) error); ok {
+		r1 = rf(tx, brandID, deletedAt)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
+
+// RestoreTx provides a mock function with given fields: tx, id
+func (_m *ProductRepository) RestoreTx(tx *sqlx.Tx, id int64) error {
+	ret := _m.Called(tx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64) error); ok {
+		r0 = rf(tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePriceTx provides a mock function with given fields: tx, id, price
+func (_m *ProductRepository) UpdatePriceTx(tx *sqlx.Tx, id int64, price model.Money) error {
+	ret := _m.Called(tx, id, price)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, model.Money) error); ok {
+		r0 = rf(tx, id, price)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStockTx provides a mock function with given fields: tx, id, delta
+func (_m *ProductRepository) UpdateStockTx(tx *sqlx.Tx, id int64, delta int64) error {
+	ret := _m.Called(tx, id, delta)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, int64) error); ok {
+		r0 = rf(tx, id, delta)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTx provides a mock function with given fields: tx, product
+func (_m *ProductRepository) UpdateTx(tx *sqlx.Tx, product *model.Product) error {
+	ret := _m.Called(tx, product)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, *model.Product) error); ok {
+		r0 = rf(tx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
+)
+
+// PromotionRepository is an autogenerated mock type for the PromotionRepository type
+type PromotionRepository struct {
+	mock.Mock
+}
+
+// CountCustomerUsageTx provides a mock function with given fields: tx, promotionID, customerID
+func (_m *PromotionRepository) CountCustomerUsageTx(tx *sqlx.Tx, promotionID int64, customerID int64) (int64, error) {
+	ret := _m.Called(tx, promotionID, customerID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64, int64) int64); ok {
+		r0 = rf(tx, promotionID, customerID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, int64, int64) error); ok {
+		r1 = rf(tx, promotionID, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: promotion
+func (_m *PromotionRepository) Create(promotion *model.Promotion) error {
+	ret := _m.Called(promotion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Promotion) error); ok {
+		r0 = rf(promotion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetActiveTx provides a mock function with given fields: tx, codes, now
+func (_m *PromotionRepository) GetActiveTx(tx *sqlx.Tx, codes []string, now time.Time) ([]*model.Promotion, error) {
+	ret := _m.Called(tx, codes, now)
+
+	var r0 []*model.Promotion
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, []string, time.Time) []*model.Promotion); ok {
+		r0 = rf(tx, codes, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Promotion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, []string, time.Time) error); ok {
+		r1 = rf(tx, codes, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByCode provides a mock function with given fields: code
+func (_m *PromotionRepository) GetByCode(code string) (*model.Promotion, error) {
+	ret := _m.Called(code)
+
+	var r0 *model.Promotion
+	if rf, ok := ret.Get(0).(func(string) *model.Promotion); ok {
+		r0 = rf(code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Promotion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: id
+func (_m *PromotionRepository) GetByID(id int64) (*model.Promotion, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Promotion
+	if rf, ok := ret.Get(0).(func(int64) *model.Promotion); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Promotion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockTx provides a mock function with given fields: tx, id
+func (_m *PromotionRepository) LockTx(tx *sqlx.Tx, id int64) error {
+	ret := _m.Called(tx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, int64) error); ok {
+		r0 = rf(tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordUsageTx provides a mock function with given fields: tx, usage
+func (_m *PromotionRepository) RecordUsageTx(tx *sqlx.Tx, usage *model.PromotionUsage) error {
+	ret := _m.Called(tx, usage)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, *model.PromotionUsage) error); ok {
+		r0 = rf(tx, usage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
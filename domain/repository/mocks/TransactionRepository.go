@@ -3,7 +3,11 @@
 package mocks
 
 import (
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
 	model "github.com/richardsahvic/jamtangan/domain/model"
+	repository "github.com/richardsahvic/jamtangan/domain/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -12,13 +16,64 @@ type TransactionRepository struct {
 	mock.Mock
 }
 
-// GetDetail provides a mock function with given fields: orderID
-func (_m *TransactionRepository) GetDetail(orderID string) ([]*model.Transaction, error) {
-	ret := _m.Called(orderID)
+// DeleteByOrderIDTx provides a mock function with given fields: tx, orderID, at
+func (_m *TransactionRepository) DeleteByOrderIDTx(tx *sqlx.Tx, orderID string, at time.Time) error {
+	ret := _m.Called(tx, orderID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, string, time.Time) error); ok {
+		r0 = rf(tx, orderID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDetail provides a mock function with given fields: orderID, opts
+func (_m *TransactionRepository) GetDetail(orderID string, opts ...repository.Option) ([]*model.Transaction, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, orderID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(string, ...repository.Option) []*model.Transaction); ok {
+		r0 = rf(orderID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ...repository.Option) error); ok {
+		r1 = rf(orderID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDetailTx provides a mock function with given fields: tx, orderID, opts
+func (_m *TransactionRepository) GetDetailTx(tx *sqlx.Tx, orderID string, opts ...repository.Option) ([]*model.Transaction, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, tx, orderID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*model.Transaction
-	if rf, ok := ret.Get(0).(func(string) []*model.Transaction); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, string, ...repository.Option) []*model.Transaction); ok {
+		r0 = rf(tx, orderID, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Transaction)
@@ -26,8 +81,8 @@ func (_m *TransactionRepository) GetDetail(orderID string) ([]*model.Transaction
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(*sqlx.Tx, string, ...repository.Option) error); ok {
+		r1 = rf(tx, orderID, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -48,3 +103,31 @@ func (_m *TransactionRepository) InsertList(order []model.Transaction) error {
 
 	return r0
 }
+
+// InsertListTx provides a mock function with given fields: tx, order
+func (_m *TransactionRepository) InsertListTx(tx *sqlx.Tx, order []model.Transaction) error {
+	ret := _m.Called(tx, order)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, []model.Transaction) error); ok {
+		r0 = rf(tx, order)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreByOrderIDTx provides a mock function with given fields: tx, orderID, deletedAt
+func (_m *TransactionRepository) RestoreByOrderIDTx(tx *sqlx.Tx, orderID string, deletedAt time.Time) error {
+	ret := _m.Called(tx, orderID, deletedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sqlx.Tx, string, time.Time) error); ok {
+		r0 = rf(tx, orderID, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,28 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	sqlx "github.com/jmoiron/sqlx"
+)
+
+// TxRepository is an autogenerated mock type for the TxRepository type
+type TxRepository struct {
+	mock.Mock
+}
+
+// WithTx provides a mock function with given fields: fn
+func (_m *TxRepository) WithTx(fn func(*sqlx.Tx) error) error {
+	ret := _m.Called(fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(*sqlx.Tx) error) error); ok {
+		r0 = rf(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// NotificationRepository manages database operations for notification.
+type NotificationRepository interface {
+	Create(notification *model.Notification) error
+	List(filter model.NotificationFilter) ([]*model.Notification, error)
+	CountUnread(customerID int64) (int64, error)
+	MarkRead(customerID int64, id int64) (bool, error)
+	MarkAllRead(customerID int64) (int64, error)
+	Delete(customerID int64, id int64) (bool, error)
+}
+
+type notificationRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository returns new instance of notificationRepoImpl.
+func NewNotificationRepository() *notificationRepoImpl {
+	return &notificationRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create stores a new notification, a notification of an event the customer
+// already has is ignored.
+func (r *notificationRepoImpl) Create(notification *model.Notification) error {
+	res, err := r.db.Exec(`
+		INSERT IGNORE INTO notification (customer_id, event_id, type, title, body, data)
+		VALUES (?, ?, ?, ?, ?, ?)`, notification.CustomerID, notification.EventID, notification.Type,
+		notification.Title, notification.Body, notification.Data)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	notification.ID = id
+
+	return err
+}
+
+// List returns the notifications matching the filter, newest first.
+func (r *notificationRepoImpl) List(filter model.NotificationFilter) ([]*model.Notification, error) {
+	query := `
+		SELECT *
+		FROM notification
+		WHERE customer_id = ? AND deleted_at IS NULL`
+	params := []interface{}{filter.CustomerID}
+
+	if filter.UnreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	if filter.BeforeID != 0 {
+		query += ` AND id < ?`
+		params = append(params, filter.BeforeID)
+	}
+
+	query += `
+		ORDER BY id DESC
+		LIMIT ?`
+	params = append(params, filter.Limit)
+
+	res := make([]*model.Notification, 0)
+	err := r.db.Select(&res, query, params...)
+	return res, err
+}
+
+// CountUnread returns how many notifications of a customer are not read yet.
+func (r *notificationRepoImpl) CountUnread(customerID int64) (int64, error) {
+	var count int64
+	err := r.db.Get(&count, `
+		SELECT COUNT(*)
+		FROM notification
+		WHERE customer_id = ? AND read_at IS NULL AND deleted_at IS NULL`, customerID)
+	return count, err
+}
+
+// MarkRead marks a notification of a customer as read, it returns false when
+// the customer has no such notification.
+func (r *notificationRepoImpl) MarkRead(customerID int64, id int64) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE notification
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = ? AND customer_id = ? AND deleted_at IS NULL`, id, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	// a notification that was already read is not counted as affected
+	affected, err := res.RowsAffected()
+	if err != nil || affected > 0 {
+		return affected > 0, err
+	}
+
+	var count int64
+	err = r.db.Get(&count, `
+		SELECT COUNT(*)
+		FROM notification
+		WHERE id = ? AND customer_id = ? AND deleted_at IS NULL`, id, customerID)
+	return count > 0, err
+}
+
+// MarkAllRead marks every unread notification of a customer as read and returns how many were.
+func (r *notificationRepoImpl) MarkAllRead(customerID int64) (int64, error) {
+	res, err := r.db.Exec(`
+		UPDATE notification
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE customer_id = ? AND read_at IS NULL AND deleted_at IS NULL`, customerID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Delete removes a notification of a customer from the inbox, it returns false
+// when the customer has no such notification.
+func (r *notificationRepoImpl) Delete(customerID int64, id int64) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE notification
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND customer_id = ? AND deleted_at IS NULL`, id, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
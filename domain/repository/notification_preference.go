@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// NotificationPreferenceRepository manages database operations for notification_preference.
+type NotificationPreferenceRepository interface {
+	GetByCustomerID(customerID int64) ([]*model.NotificationPreference, error)
+	SaveTx(tx *sqlx.Tx, preferences []*model.NotificationPreference) error
+}
+
+type notificationPreferenceRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewNotificationPreferenceRepository returns new instance of notificationPreferenceRepoImpl.
+func NewNotificationPreferenceRepository() *notificationPreferenceRepoImpl {
+	return &notificationPreferenceRepoImpl{
+		db: database.DB,
+	}
+}
+
+// GetByCustomerID returns the preferences a customer has chosen.
+func (r *notificationPreferenceRepoImpl) GetByCustomerID(customerID int64) ([]*model.NotificationPreference, error) {
+	res := make([]*model.NotificationPreference, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM notification_preference
+		WHERE customer_id = ?`, customerID)
+	return res, err
+}
+
+// SaveTx creates or replaces preferences inside the given database transaction.
+func (r *notificationPreferenceRepoImpl) SaveTx(tx *sqlx.Tx, preferences []*model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(preferences))
+	params := make([]interface{}, 0, len(preferences)*4)
+	for _, preference := range preferences {
+		values = append(values, "(?, ?, ?, ?)")
+		params = append(params, preference.CustomerID, preference.Channel, preference.Category, preference.Enabled)
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO notification_preference (customer_id, channel, category, enabled)
+		VALUES `+strings.Join(values, ", ")+`
+		ON DUPLICATE KEY UPDATE
+			enabled = VALUES(enabled), updated_at = CURRENT_TIMESTAMP`, params...)
+	return err
+}
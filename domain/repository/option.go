@@ -0,0 +1,21 @@
+package repository
+
+// Option changes which rows a repository query returns.
+type Option int
+
+// List of repository query options.
+const (
+	// IncludeDeleted makes a query return soft-deleted rows as well.
+	IncludeDeleted Option = iota + 1
+)
+
+// notDeleted returns the condition excluding the soft-deleted rows of column,
+// or a condition matching every row when opts include IncludeDeleted.
+func notDeleted(column string, opts []Option) string {
+	for _, opt := range opts {
+		if opt == IncludeDeleted {
+			return "TRUE"
+		}
+	}
+	return column + " IS NULL"
+}
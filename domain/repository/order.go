@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// orderStatusColumn maps an order status to the column storing when it was reached.
+var orderStatusColumn = map[model.OrderStatus]string{
+	model.OrderStatusPaid:      "paid_at",
+	model.OrderStatusPacked:    "packed_at",
+	model.OrderStatusShipped:   "shipped_at",
+	model.OrderStatusDelivered: "delivered_at",
+	model.OrderStatusCancelled: "cancelled_at",
+}
+
+// orderSortColumn maps the sort option of order listing to its column.
+var orderSortColumn = map[string]string{
+	"created_at":   "o.created_at",
+	"total_amount": "o.total_amount",
+}
+
+// OrderRepository manages database operations for order.
+type OrderRepository interface {
+	CreateTx(tx *sqlx.Tx, order *model.Order) error
+	GetByOrderID(orderID string, opts ...Option) (*model.Order, error)
+	GetByOrderIDForUpdate(tx *sqlx.Tx, orderID string, opts ...Option) (*model.Order, error)
+	UpdateStatusTx(tx *sqlx.Tx, orderID string, status model.OrderStatus) error
+	UpdateTotalAmountTx(tx *sqlx.Tx, orderID string, totalAmount model.Money) error
+	List(filter model.OrderFilter) ([]*model.OrderSummary, error)
+	DeleteTx(tx *sqlx.Tx, orderID string, at time.Time) error
+	RestoreTx(tx *sqlx.Tx, orderID string) error
+}
+
+type orderRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewOrderRepository returns new instance of orderRepoImpl.
+func NewOrderRepository() *orderRepoImpl {
+	return &orderRepoImpl{
+		db: database.DB,
+	}
+}
+
+// CreateTx creates a new order inside the given database transaction.
+func (r *orderRepoImpl) CreateTx(tx *sqlx.Tx, order *model.Order) error {
+	res, err := tx.Exec(`
+		INSERT INTO orders (order_id, customer_id, status, total_amount, discount_amount)
+		VALUES (?, ?, ?, ?, ?)`, order.OrderID, order.CustomerID, order.Status, order.TotalAmount, order.DiscountAmount)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	order.ID = id
+
+	return err
+}
+
+// GetByOrderID returns order's details by order ID.
+func (r *orderRepoImpl) GetByOrderID(orderID string, opts ...Option) (*model.Order, error) {
+	res := &model.Order{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM orders
+		WHERE order_id = ? AND `+notDeleted("deleted_at", opts), orderID)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetByOrderIDForUpdate returns order's details by order ID and locks the row
+// until the transaction ends.
+func (r *orderRepoImpl) GetByOrderIDForUpdate(tx *sqlx.Tx, orderID string, opts ...Option) (*model.Order, error) {
+	res := &model.Order{}
+	err := tx.Get(res, `
+		SELECT *
+		FROM orders
+		WHERE order_id = ? AND `+notDeleted("deleted_at", opts)+`
+		FOR UPDATE`, orderID)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// UpdateStatusTx updates order's status and records when the status was reached.
+func (r *orderRepoImpl) UpdateStatusTx(tx *sqlx.Tx, orderID string, status model.OrderStatus) error {
+	timestamp := ""
+	if column, ok := orderStatusColumn[status]; ok {
+		timestamp = fmt.Sprintf(", %s = CURRENT_TIMESTAMP", column)
+	}
+
+	_, err := tx.Exec(fmt.Sprintf(`
+		UPDATE orders
+		SET status = ?, updated_at = CURRENT_TIMESTAMP%s
+		WHERE order_id = ?`, timestamp), status, orderID)
+	return err
+}
+
+// UpdateTotalAmountTx updates order's total amount.
+func (r *orderRepoImpl) UpdateTotalAmountTx(tx *sqlx.Tx, orderID string, totalAmount model.Money) error {
+	_, err := tx.Exec(`
+		UPDATE orders
+		SET total_amount = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = ?`, totalAmount, orderID)
+	return err
+}
+
+// DeleteTx soft-deletes an order at the given time.
+func (r *orderRepoImpl) DeleteTx(tx *sqlx.Tx, orderID string, at time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE orders
+		SET deleted_at = ?
+		WHERE order_id = ? AND deleted_at IS NULL`, at, orderID)
+	return err
+}
+
+// RestoreTx brings back a soft-deleted order.
+func (r *orderRepoImpl) RestoreTx(tx *sqlx.Tx, orderID string) error {
+	_, err := tx.Exec(`
+		UPDATE orders
+		SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = ?`, orderID)
+	return err
+}
+
+// List returns summarized orders matching the filter, ordered by the sort column
+// and ID, starting after the row given by AfterValue and AfterID.
+func (r *orderRepoImpl) List(filter model.OrderFilter) ([]*model.OrderSummary, error) {
+	conditions := []string{"o.deleted_at IS NULL"}
+	params := make([]interface{}, 0)
+
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "o.created_at >= ?")
+		params = append(params, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "o.created_at <= ?")
+		params = append(params, *filter.CreatedTo)
+	}
+	if filter.SKU != "" {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1
+			FROM transaction t
+			WHERE t.order_id = o.order_id AND t.sku = ? AND t.deleted_at IS NULL)`)
+		params = append(params, filter.SKU)
+	}
+	if filter.MinTotal != nil {
+		conditions = append(conditions, "o.total_amount >= ?")
+		params = append(params, *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		conditions = append(conditions, "o.total_amount <= ?")
+		params = append(params, *filter.MaxTotal)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "o.status = ?")
+		params = append(params, filter.Status)
+	}
+
+	column, ok := orderSortColumn[filter.SortBy]
+	if !ok {
+		column = orderSortColumn["created_at"]
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.AfterValue != nil {
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND o.id %[2]s ?))", column, comparison))
+		params = append(params, filter.AfterValue, filter.AfterValue, filter.AfterID)
+	}
+
+	params = append(params, filter.Limit)
+
+	res := make([]*model.OrderSummary, 0)
+	err := r.db.Select(&res, fmt.Sprintf(`
+		SELECT o.id, o.order_id, o.customer_id, o.status, o.total_amount, o.created_at,
+			(SELECT COALESCE(SUM(t.quantity), 0)
+			FROM transaction t
+			WHERE t.order_id = o.order_id AND t.deleted_at IS NULL) AS item_count
+		FROM orders o
+		WHERE %s
+		ORDER BY %s %s, o.id %s
+		LIMIT ?`, strings.Join(conditions, " AND "), column, direction, direction), params...)
+	return res, err
+}
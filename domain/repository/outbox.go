@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// OutboxRepository manages database operations for outbox event.
+type OutboxRepository interface {
+	CreateTx(tx *sqlx.Tx, event *model.OutboxEvent) error
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]*model.OutboxEvent, error)
+	UpdateDelivery(event *model.OutboxEvent) error
+	DeleteTx(tx *sqlx.Tx, id int64) error
+}
+
+type outboxRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewOutboxRepository returns new instance of outboxRepoImpl.
+func NewOutboxRepository() *outboxRepoImpl {
+	return &outboxRepoImpl{
+		db: database.DB,
+	}
+}
+
+// CreateTx stores a new outbox event inside the database transaction of the change it
+// describes. The event is not sent again to the channels it was already delivered to.
+func (r *outboxRepoImpl) CreateTx(tx *sqlx.Tx, event *model.OutboxEvent) error {
+	res, err := tx.Exec(`
+		INSERT INTO outbox_event (origin_event_id, event_type, aggregate_id, payload, status, delivered_channels)
+		VALUES (?, ?, ?, ?, ?, ?)`, event.OriginEventID, event.EventType, event.AggregateID, string(event.Payload),
+		model.OutboxStatusPending, event.DeliveredChannels)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	event.ID = id
+
+	return err
+}
+
+// ClaimDue returns pending events due for delivery and leases them: their next
+// attempt is moved by lease, so other dispatchers skip them while they are being
+// delivered and pick them up again if this dispatcher stops before updating them.
+func (r *outboxRepoImpl) ClaimDue(now time.Time, limit int, lease time.Duration) (events []*model.OutboxEvent, err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	events = make([]*model.OutboxEvent, 0)
+	err = tx.Select(&events, `
+		SELECT *
+		FROM outbox_event
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, model.OutboxStatusPending, now, limit)
+	if err != nil || len(events) == 0 {
+		return events, err
+	}
+
+	params := []interface{}{now.Add(lease)}
+	for _, event := range events {
+		params = append(params, event.ID)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE outbox_event
+		SET next_attempt_at = ?
+		WHERE id IN (?`+strings.Repeat(", ?", len(events)-1)+`)`, params...)
+	return events, err
+}
+
+// UpdateDelivery stores the result of a delivery attempt of an event.
+func (r *outboxRepoImpl) UpdateDelivery(event *model.OutboxEvent) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox_event
+		SET status = ?, attempts = ?, delivered_channels = ?, last_error = ?,
+			next_attempt_at = ?, sent_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, event.Status, event.Attempts, event.DeliveredChannels, event.LastError,
+		event.NextAttemptAt, event.SentAt, event.ID)
+	return err
+}
+
+// DeleteTx removes an event from the outbox.
+func (r *outboxRepoImpl) DeleteTx(tx *sqlx.Tx, id int64) error {
+	_, err := tx.Exec(`
+		DELETE FROM outbox_event
+		WHERE id = ?`, id)
+	return err
+}
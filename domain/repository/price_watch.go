@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// PriceWatchRepository manages database operations for price_watch.
+type PriceWatchRepository interface {
+	Create(watch *model.PriceWatch) error
+	Update(watch *model.PriceWatch) error
+	GetActive(customerID int64, sku string) (*model.PriceWatch, error)
+	ListByCustomerID(customerID int64) ([]*model.PriceWatch, error)
+	Cancel(id int64) error
+	ClaimMatchingTx(tx *sqlx.Tx, productID int64, price model.Money, limit int) ([]*model.PriceWatch, error)
+	MarkNotifiedTx(tx *sqlx.Tx, ids []int64, price model.Money) error
+}
+
+type priceWatchRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewPriceWatchRepository returns new instance of priceWatchRepoImpl.
+func NewPriceWatchRepository() *priceWatchRepoImpl {
+	return &priceWatchRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create creates a new active watch.
+func (r *priceWatchRepoImpl) Create(watch *model.PriceWatch) error {
+	res, err := r.db.Exec(`
+		INSERT INTO price_watch (customer_id, product_id, sku, base_price, target_price, drop_percent, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, watch.CustomerID, watch.ProductID, watch.SKU, watch.BasePrice,
+		watch.TargetPrice, watch.DropPercent, watch.Status)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	watch.ID = id
+
+	return err
+}
+
+// Update replaces the target of an active watch, the watch may fire again at any price.
+func (r *priceWatchRepoImpl) Update(watch *model.PriceWatch) error {
+	_, err := r.db.Exec(`
+		UPDATE price_watch
+		SET base_price = ?, target_price = ?, drop_percent = ?, last_notified_price = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?`, watch.BasePrice, watch.TargetPrice, watch.DropPercent, watch.ID,
+		model.PriceWatchActive)
+	return err
+}
+
+// GetActive returns the active watch of a customer on a SKU.
+func (r *priceWatchRepoImpl) GetActive(customerID int64, sku string) (*model.PriceWatch, error) {
+	res := &model.PriceWatch{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM price_watch
+		WHERE customer_id = ? AND sku = ? AND status = ?
+		LIMIT 1`, customerID, sku, model.PriceWatchActive)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// ListByCustomerID returns the active watches of a customer, newest first.
+func (r *priceWatchRepoImpl) ListByCustomerID(customerID int64) ([]*model.PriceWatch, error) {
+	res := make([]*model.PriceWatch, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM price_watch
+		WHERE customer_id = ? AND status = ?
+		ORDER BY id DESC`, customerID, model.PriceWatchActive)
+	return res, err
+}
+
+// Cancel cancels an active watch.
+func (r *priceWatchRepoImpl) Cancel(id int64) error {
+	_, err := r.db.Exec(`
+		UPDATE price_watch
+		SET status = ?, cancelled_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?`, model.PriceWatchCancelled, id, model.PriceWatchActive)
+	return err
+}
+
+// ClaimMatchingTx returns the active watches of a product whose target is met by
+// price and that were not notified at this price or lower, and locks them until
+// the transaction ends. Watches locked by another transaction are skipped.
+func (r *priceWatchRepoImpl) ClaimMatchingTx(tx *sqlx.Tx, productID int64, price model.Money, limit int) ([]*model.PriceWatch, error) {
+	res := make([]*model.PriceWatch, 0)
+	err := tx.Select(&res, `
+		SELECT *
+		FROM price_watch
+		WHERE product_id = ? AND status = ? AND target_price >= ?
+			AND (last_notified_price IS NULL OR last_notified_price > ?)
+		ORDER BY id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, productID, model.PriceWatchActive, price, price, limit)
+	return res, err
+}
+
+// MarkNotifiedTx records the price watches were notified at.
+func (r *priceWatchRepoImpl) MarkNotifiedTx(tx *sqlx.Tx, ids []int64, price model.Money) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	params := []interface{}{price}
+	for _, id := range ids {
+		params = append(params, id)
+	}
+
+	_, err := tx.Exec(`
+		UPDATE price_watch
+		SET last_notified_price = ?, notified_at = CURRENT_TIMESTAMP
+		WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, params...)
+	return err
+}
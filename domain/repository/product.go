@@ -2,18 +2,46 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/pkg/database"
 )
 
+// productSortColumn maps the sort option of product listing to its column.
+var productSortColumn = map[string]string{
+	"created_at": "created_at",
+	"price":      "price",
+	"stock":      "stock",
+}
+
+// ErrDuplicateProductSlug is returned when a product is saved with the slug of
+// another product, deleted products included.
+var ErrDuplicateProductSlug = errors.New("product slug already exists")
+
 // ProductRepository manages database operations for product.
 type ProductRepository interface {
 	Create(product *model.Product) error
-	GetBySKU(sku string) (*model.Product, error)
-	GetByID(id int64) (*model.Product, error)
-	GetByBrandID(brandID int64) ([]*model.Product, error)
+	CreateTx(tx *sqlx.Tx, product *model.Product) error
+	GetBySKU(sku string, opts ...Option) (*model.Product, error)
+	GetByID(id int64, opts ...Option) (*model.Product, error)
+	GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...Option) (*model.Product, error)
+	GetByBrandID(brandID int64, opts ...Option) ([]*model.Product, error)
+	CountByBrandIDTx(tx *sqlx.Tx, brandID int64) (int64, error)
+	List(filter model.ProductFilter) ([]*model.Product, error)
+	GetBySKUs(skus []string) ([]*model.Product, error)
+	GetBySKUsForUpdate(tx *sqlx.Tx, skus []string, opts ...Option) ([]*model.Product, error)
+	UpdateStockTx(tx *sqlx.Tx, id int64, delta int64) error
+	UpdatePriceTx(tx *sqlx.Tx, id int64, price model.Money) error
+	UpdateTx(tx *sqlx.Tx, product *model.Product) error
+	DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error
+	DeleteByBrandIDTx(tx *sqlx.Tx, brandID int64, at time.Time) (int64, error)
+	RestoreTx(tx *sqlx.Tx, id int64) error
+	RestoreByBrandIDTx(tx *sqlx.Tx, brandID int64, deletedAt time.Time) (int64, error)
 }
 
 type productRepoImpl struct {
@@ -31,8 +59,9 @@ func (r *productRepoImpl) scanRows(rows *sql.Rows) (items []*model.Product, err
 	items = make([]*model.Product, 0)
 	for rows.Next() {
 		res := &model.Product{}
-		err = rows.Scan(&res.ID, &res.SKU, &res.BrandID, &res.Stock, &res.Price, &res.CreatedAt,
-			&res.UpdatedAt, &res.DeletedAt)
+		err = rows.Scan(&res.ID, &res.SKU, &res.Name, &res.Slug, &res.Description, &res.Attributes,
+			&res.BrandID, &res.Stock, &res.Price, &res.Version, &res.Status,
+			&res.CreatedAt, &res.UpdatedAt, &res.DeletedAt)
 		if err != nil {
 			return
 		}
@@ -44,10 +73,30 @@ func (r *productRepoImpl) scanRows(rows *sql.Rows) (items []*model.Product, err
 // Create creates a new product into the database.
 func (r *productRepoImpl) Create(product *model.Product) error {
 	res, err := r.db.Exec(`
-		INSERT INTO product (sku, brand_id, stock, price)
-		VALUES (?, ?, ?, ?)`, product.SKU, product.BrandID, product.Stock, product.Price)
+		INSERT INTO product (sku, name, slug, description, attributes, brand_id, stock, price, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, product.SKU, product.Name, product.Slug, product.Description,
+		product.Attributes, product.BrandID, product.Stock, product.Price, product.Status)
+	if isDuplicateEntry(err) {
+		return ErrDuplicateProductSlug
+	} else if err != nil {
+		return err
+	}
 
-	if err != nil {
+	id, err := res.LastInsertId()
+	product.ID = id
+
+	return err
+}
+
+// CreateTx creates a new product inside the given database transaction.
+func (r *productRepoImpl) CreateTx(tx *sqlx.Tx, product *model.Product) error {
+	res, err := tx.Exec(`
+		INSERT INTO product (sku, name, slug, description, attributes, brand_id, stock, price, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, product.SKU, product.Name, product.Slug, product.Description,
+		product.Attributes, product.BrandID, product.Stock, product.Price, product.Status)
+	if isDuplicateEntry(err) {
+		return ErrDuplicateProductSlug
+	} else if err != nil {
 		return err
 	}
 
@@ -58,12 +107,14 @@ func (r *productRepoImpl) Create(product *model.Product) error {
 }
 
 // GetBySKU returns product's details by SKU.
-func (r *productRepoImpl) GetBySKU(sku string) (*model.Product, error) {
+func (r *productRepoImpl) GetBySKU(sku string, opts ...Option) (*model.Product, error) {
 	res := &model.Product{}
 	err := r.db.Get(res, `
 		SELECT *
 		FROM product
-		WHERE sku = ?`, sku)
+		WHERE sku = ? AND `+notDeleted("deleted_at", opts)+`
+		ORDER BY deleted_at IS NOT NULL, id
+		LIMIT 1`, sku)
 	if err == sql.ErrNoRows {
 		res = nil
 		err = nil
@@ -72,12 +123,28 @@ func (r *productRepoImpl) GetBySKU(sku string) (*model.Product, error) {
 }
 
 // GetByID returns product's details by ID.
-func (r *productRepoImpl) GetByID(id int64) (*model.Product, error) {
+func (r *productRepoImpl) GetByID(id int64, opts ...Option) (*model.Product, error) {
 	res := &model.Product{}
 	err := r.db.Get(res, `
 		SELECT *
 		FROM product
-		WHERE id = ?`, id)
+		WHERE id = ? AND `+notDeleted("deleted_at", opts), id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetByIDForUpdate returns product's details by ID and locks the row until the
+// transaction ends.
+func (r *productRepoImpl) GetByIDForUpdate(tx *sqlx.Tx, id int64, opts ...Option) (*model.Product, error) {
+	res := &model.Product{}
+	err := tx.Get(res, `
+		SELECT *
+		FROM product
+		WHERE id = ? AND `+notDeleted("deleted_at", opts)+`
+		FOR UPDATE`, id)
 	if err == sql.ErrNoRows {
 		res = nil
 		err = nil
@@ -86,15 +153,218 @@ func (r *productRepoImpl) GetByID(id int64) (*model.Product, error) {
 }
 
 // GetByBrandID returns produt's details by brand ID.
-func (r *productRepoImpl) GetByBrandID(brandID int64) ([]*model.Product, error) {
+func (r *productRepoImpl) GetByBrandID(brandID int64, opts ...Option) ([]*model.Product, error) {
 	res, err := r.db.Query(`
 		SELECT *
 		FROM product
-		WHERE brand_id = ?`, brandID)
-	if err == sql.ErrNoRows {
-		return nil, nil
+		WHERE brand_id = ? AND `+notDeleted("deleted_at", opts), brandID)
+	if err != nil {
+		return nil, err
 	}
+	defer res.Close()
 
 	items, err := r.scanRows(res)
 	return items, err
 }
+
+// List returns products matching the filter, ordered by the sort column and ID,
+// starting after the row given by AfterValue and AfterID.
+func (r *productRepoImpl) List(filter model.ProductFilter) ([]*model.Product, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	params := make([]interface{}, 0)
+
+	if filter.BrandID != 0 {
+		conditions = append(conditions, "brand_id = ?")
+		params = append(params, filter.BrandID)
+	}
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "price >= ?")
+		params = append(params, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "price <= ?")
+		params = append(params, *filter.MaxPrice)
+	}
+	if filter.InStock {
+		conditions = append(conditions, "stock > 0")
+	}
+	if filter.SKUPrefix != "" {
+		conditions = append(conditions, "sku LIKE ?")
+		params = append(params, escapeLike(filter.SKUPrefix)+"%")
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		params = append(params, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at <= ?")
+		params = append(params, *filter.CreatedTo)
+	}
+
+	column, ok := productSortColumn[filter.SortBy]
+	if !ok {
+		column = productSortColumn["created_at"]
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.AfterValue != nil {
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparison))
+		params = append(params, filter.AfterValue, filter.AfterValue, filter.AfterID)
+	}
+
+	params = append(params, filter.Limit)
+
+	res := make([]*model.Product, 0)
+	err := r.db.Select(&res, fmt.Sprintf(`
+		SELECT *
+		FROM product
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT ?`, strings.Join(conditions, " AND "), column, direction, direction), params...)
+	return res, err
+}
+
+// CountByBrandIDTx returns how many products of a brand are not deleted.
+func (r *productRepoImpl) CountByBrandIDTx(tx *sqlx.Tx, brandID int64) (int64, error) {
+	var count int64
+	err := tx.Get(&count, `
+		SELECT COUNT(*)
+		FROM product
+		WHERE brand_id = ? AND deleted_at IS NULL`, brandID)
+	return count, err
+}
+
+// GetBySKUs returns product's details by SKUs.
+func (r *productRepoImpl) GetBySKUs(skus []string) ([]*model.Product, error) {
+	if len(skus) == 0 {
+		return []*model.Product{}, nil
+	}
+
+	params := make([]interface{}, len(skus))
+	for index, sku := range skus {
+		params[index] = sku
+	}
+
+	res, err := r.db.Query(`
+		SELECT *
+		FROM product
+		WHERE sku IN (?`+strings.Repeat(", ?", len(skus)-1)+`) AND deleted_at IS NULL
+		ORDER BY id`, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	items, err := r.scanRows(res)
+	return items, err
+}
+
+// GetBySKUsForUpdate returns product's details by SKUs and locks the rows until
+// the transaction ends. Rows are locked in ID order to avoid deadlocks between
+// concurrent orders.
+func (r *productRepoImpl) GetBySKUsForUpdate(tx *sqlx.Tx, skus []string, opts ...Option) ([]*model.Product, error) {
+	if len(skus) == 0 {
+		return []*model.Product{}, nil
+	}
+
+	params := make([]interface{}, len(skus))
+	for index, sku := range skus {
+		params[index] = sku
+	}
+
+	res, err := tx.Query(`
+		SELECT *
+		FROM product
+		WHERE sku IN (?`+strings.Repeat(", ?", len(skus)-1)+`) AND `+notDeleted("deleted_at", opts)+`
+		ORDER BY id
+		FOR UPDATE`, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	items, err := r.scanRows(res)
+	return items, err
+}
+
+// UpdateStockTx adds delta to product's stock, a negative delta decreases it.
+func (r *productRepoImpl) UpdateStockTx(tx *sqlx.Tx, id int64, delta int64) error {
+	_, err := tx.Exec(`
+		UPDATE product
+		SET stock = stock + ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, delta, id)
+	return err
+}
+
+// UpdatePriceTx sets product's price.
+func (r *productRepoImpl) UpdatePriceTx(tx *sqlx.Tx, id int64, price model.Money) error {
+	_, err := tx.Exec(`
+		UPDATE product
+		SET price = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, price, id)
+	return err
+}
+
+// UpdateTx sets product's content, brand, stock, price and status, and moves it
+// to the next version.
+func (r *productRepoImpl) UpdateTx(tx *sqlx.Tx, product *model.Product) error {
+	_, err := tx.Exec(`
+		UPDATE product
+		SET name = ?, slug = ?, description = ?, attributes = ?, brand_id = ?, stock = ?, price = ?, status = ?,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, product.Name, product.Slug, product.Description, product.Attributes,
+		product.BrandID, product.Stock, product.Price, product.Status, product.ID)
+	if isDuplicateEntry(err) {
+		return ErrDuplicateProductSlug
+	}
+	return err
+}
+
+// DeleteTx soft-deletes a product at the given time.
+func (r *productRepoImpl) DeleteTx(tx *sqlx.Tx, id int64, at time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE product
+		SET deleted_at = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NULL`, at, id)
+	return err
+}
+
+// DeleteByBrandIDTx soft-deletes the products of a brand at the given time, and
+// returns how many were deleted.
+func (r *productRepoImpl) DeleteByBrandIDTx(tx *sqlx.Tx, brandID int64, at time.Time) (int64, error) {
+	res, err := tx.Exec(`
+		UPDATE product
+		SET deleted_at = ?, version = version + 1
+		WHERE brand_id = ? AND deleted_at IS NULL`, at, brandID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RestoreTx brings back a soft-deleted product.
+func (r *productRepoImpl) RestoreTx(tx *sqlx.Tx, id int64) error {
+	_, err := tx.Exec(`
+		UPDATE product
+		SET deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, id)
+	return err
+}
+
+// RestoreByBrandIDTx brings back the products of a brand that were deleted at
+// deletedAt, which are the ones deleted along with the brand, and returns how
+// many were restored.
+func (r *productRepoImpl) RestoreByBrandIDTx(tx *sqlx.Tx, brandID int64, deletedAt time.Time) (int64, error) {
+	res, err := tx.Exec(`
+		UPDATE product
+		SET deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE brand_id = ? AND deleted_at = ?`, brandID, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
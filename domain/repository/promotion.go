@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// ErrPromotionUsageLimit is returned when a promotion is used after reaching its usage limit.
+var ErrPromotionUsageLimit = errors.New("promotion has reached its usage limit")
+
+// PromotionRepository manages database operations for promotion.
+type PromotionRepository interface {
+	Create(promotion *model.Promotion) error
+	GetByID(id int64) (*model.Promotion, error)
+	GetByCode(code string) (*model.Promotion, error)
+	GetActiveTx(tx *sqlx.Tx, codes []string, now time.Time) ([]*model.Promotion, error)
+	LockTx(tx *sqlx.Tx, id int64) error
+	CountCustomerUsageTx(tx *sqlx.Tx, promotionID int64, customerID int64) (int64, error)
+	RecordUsageTx(tx *sqlx.Tx, usage *model.PromotionUsage) error
+}
+
+type promotionRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewPromotionRepository returns new instance of promotionRepoImpl.
+func NewPromotionRepository() *promotionRepoImpl {
+	return &promotionRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create creates a new promotion into the database.
+func (r *promotionRepoImpl) Create(promotion *model.Promotion) error {
+	res, err := r.db.Exec(`
+		INSERT INTO promotion (
+			code, name, type, value, buy_quantity, get_quantity, scope, brand_id, sku,
+			stackable, priority, starts_at, ends_at, usage_limit, per_customer_limit
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		promotion.Code, promotion.Name, promotion.Type, promotion.Value, promotion.BuyQuantity,
+		promotion.GetQuantity, promotion.Scope, promotion.BrandID, promotion.SKU, promotion.Stackable,
+		promotion.Priority, promotion.StartsAt, promotion.EndsAt, promotion.UsageLimit, promotion.PerCustomerLimit)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	promotion.ID = id
+
+	return err
+}
+
+// GetByID returns promotion's details by ID.
+func (r *promotionRepoImpl) GetByID(id int64) (*model.Promotion, error) {
+	res := &model.Promotion{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM promotion
+		WHERE id = ? AND deleted_at IS NULL`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetByCode returns promotion's details by coupon code.
+func (r *promotionRepoImpl) GetByCode(code string) (*model.Promotion, error) {
+	res := &model.Promotion{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM promotion
+		WHERE code = ? AND deleted_at IS NULL`, code)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// GetActiveTx returns the automatic promotions and the coupons with the given
+// codes that are valid at now. The rows are not locked, so orders running a
+// campaign do not wait on each other; RecordUsageTx enforces the usage limit.
+func (r *promotionRepoImpl) GetActiveTx(tx *sqlx.Tx, codes []string, now time.Time) ([]*model.Promotion, error) {
+	codeCondition := "code IS NULL"
+	params := []interface{}{now, now}
+	if len(codes) > 0 {
+		codeCondition = "(code IS NULL OR code IN (?" + strings.Repeat(", ?", len(codes)-1) + "))"
+		for _, code := range codes {
+			params = append(params, code)
+		}
+	}
+
+	res := make([]*model.Promotion, 0)
+	err := tx.Select(&res, `
+		SELECT *
+		FROM promotion
+		WHERE deleted_at IS NULL
+			AND (starts_at IS NULL OR starts_at <= ?)
+			AND (ends_at IS NULL OR ends_at > ?)
+			AND `+codeCondition+`
+		ORDER BY id`, params...)
+	return res, err
+}
+
+// LockTx locks a promotion until the transaction ends, so the usage of a
+// customer can be counted without a concurrent order using it as well.
+func (r *promotionRepoImpl) LockTx(tx *sqlx.Tx, id int64) error {
+	var locked int64
+	return tx.Get(&locked, `
+		SELECT id
+		FROM promotion
+		WHERE id = ?
+		FOR UPDATE`, id)
+}
+
+// CountCustomerUsageTx returns how many times a customer has used a promotion.
+func (r *promotionRepoImpl) CountCustomerUsageTx(tx *sqlx.Tx, promotionID int64, customerID int64) (int64, error) {
+	var count int64
+	err := tx.Get(&count, `
+		SELECT COUNT(*)
+		FROM promotion_usage
+		WHERE promotion_id = ? AND customer_id = ?`, promotionID, customerID)
+	return count, err
+}
+
+// RecordUsageTx counts a promotion applied to an order toward the usage limit and
+// stores the usage. It returns ErrPromotionUsageLimit when the limit was reached.
+func (r *promotionRepoImpl) RecordUsageTx(tx *sqlx.Tx, usage *model.PromotionUsage) error {
+	res, err := tx.Exec(`
+		UPDATE promotion
+		SET usage_count = usage_count + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND (usage_limit IS NULL OR usage_count < usage_limit)`, usage.PromotionID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPromotionUsageLimit
+	}
+
+	res, err = tx.Exec(`
+		INSERT INTO promotion_usage (promotion_id, order_id, customer_id, discount_amount)
+		VALUES (?, ?, ?, ?)`, usage.PromotionID, usage.OrderID, usage.CustomerID, usage.DiscountAmount)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	usage.ID = id
+
+	return err
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// ReminderRepository manages database operations for reminder and reminder_opt_out.
+type ReminderRepository interface {
+	ListIdleCarts(now time.Time, idleBefore time.Time, limit int) ([]*model.ReminderTarget, error)
+	ListIdleOrders(idleBefore time.Time, limit int) ([]*model.ReminderTarget, error)
+	Create(reminder *model.Reminder) error
+	ClaimDueTx(tx *sqlx.Tx, now time.Time, limit int) ([]*model.Reminder, error)
+	UpdateTx(tx *sqlx.Tx, reminder *model.Reminder) error
+	GetByToken(token string) (*model.Reminder, error)
+	OptOut(customerID int64) error
+}
+
+type reminderRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewReminderRepository returns new instance of reminderRepoImpl.
+func NewReminderRepository() *reminderRepoImpl {
+	return &reminderRepoImpl{
+		db: database.DB,
+	}
+}
+
+// ListIdleCarts returns the active carts of customers with items and no activity
+// since idleBefore, that have no reminder sequence and whose customer did not opt
+// out of reminders. The last activity is the latest change to the cart or its items.
+func (r *reminderRepoImpl) ListIdleCarts(now time.Time, idleBefore time.Time, limit int) ([]*model.ReminderTarget, error) {
+	res := make([]*model.ReminderTarget, 0)
+	err := r.db.Select(&res, `
+		SELECT c.cart_id AS target_id, c.customer_id,
+			GREATEST(COALESCE(c.updated_at, c.created_at), MAX(COALESCE(i.updated_at, i.created_at))) AS last_activity_at
+		FROM cart c
+		JOIN cart_item i ON i.cart_id = c.cart_id
+		WHERE c.status = ? AND c.customer_id IS NOT NULL AND c.deleted_at IS NULL AND c.expires_at > ?
+			AND NOT EXISTS (SELECT 1 FROM reminder r WHERE r.kind = ? AND r.target_id = c.cart_id)
+			AND NOT EXISTS (SELECT 1 FROM reminder_opt_out o WHERE o.customer_id = c.customer_id)
+		GROUP BY c.id, c.cart_id, c.customer_id, c.updated_at, c.created_at
+		HAVING last_activity_at <= ?
+		ORDER BY c.id
+		LIMIT ?`, model.CartStatusActive, now, model.ReminderKindCart, idleBefore, limit)
+	return res, err
+}
+
+// ListIdleOrders returns the orders of customers waiting for payment since
+// idleBefore, that have no reminder sequence and whose customer did not opt out
+// of reminders.
+func (r *reminderRepoImpl) ListIdleOrders(idleBefore time.Time, limit int) ([]*model.ReminderTarget, error) {
+	res := make([]*model.ReminderTarget, 0)
+	err := r.db.Select(&res, `
+		SELECT o.order_id AS target_id, o.customer_id, o.created_at AS last_activity_at
+		FROM orders o
+		WHERE o.status = ? AND o.customer_id IS NOT NULL AND o.deleted_at IS NULL AND o.created_at <= ?
+			AND NOT EXISTS (SELECT 1 FROM reminder r WHERE r.kind = ? AND r.target_id = o.order_id)
+			AND NOT EXISTS (SELECT 1 FROM reminder_opt_out oo WHERE oo.customer_id = o.customer_id)
+		ORDER BY o.id
+		LIMIT ?`, model.OrderStatusPending, idleBefore, model.ReminderKindOrder, limit)
+	return res, err
+}
+
+// Create starts the reminder sequence of a target, a target that already has a
+// sequence, started by another instance, is left as it is.
+func (r *reminderRepoImpl) Create(reminder *model.Reminder) error {
+	_, err := r.db.Exec(`
+		INSERT IGNORE INTO reminder (kind, target_id, customer_id, step, idle_since, next_at, status, unsubscribe_token)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, reminder.Kind, reminder.TargetID, reminder.CustomerID, reminder.Step,
+		reminder.IdleSince, reminder.NextAt, reminder.Status, reminder.UnsubscribeToken)
+	return err
+}
+
+// ClaimDueTx returns the active reminders due at now and locks them until the
+// transaction ends, reminders locked by another instance are skipped.
+func (r *reminderRepoImpl) ClaimDueTx(tx *sqlx.Tx, now time.Time, limit int) ([]*model.Reminder, error) {
+	res := make([]*model.Reminder, 0)
+	err := tx.Select(&res, `
+		SELECT *
+		FROM reminder
+		WHERE status = ? AND next_at <= ?
+		ORDER BY next_at, id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, model.ReminderStatusActive, now, limit)
+	return res, err
+}
+
+// UpdateTx stores the progress of a reminder sequence.
+func (r *reminderRepoImpl) UpdateTx(tx *sqlx.Tx, reminder *model.Reminder) error {
+	_, err := tx.Exec(`
+		UPDATE reminder
+		SET step = ?, idle_since = ?, next_at = ?, status = ?, last_sent_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, reminder.Step, reminder.IdleSince, reminder.NextAt, reminder.Status, reminder.LastSentAt,
+		reminder.ID)
+	return err
+}
+
+// GetByToken returns the reminder of an unsubscribe token.
+func (r *reminderRepoImpl) GetByToken(token string) (*model.Reminder, error) {
+	res := &model.Reminder{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM reminder
+		WHERE unsubscribe_token = ?`, token)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// OptOut opts a customer out of reminders and ends the active sequences of the customer.
+func (r *reminderRepoImpl) OptOut(customerID int64) error {
+	_, err := r.db.Exec(`
+		INSERT IGNORE INTO reminder_opt_out (customer_id)
+		VALUES (?)`, customerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE reminder
+		SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE customer_id = ? AND status = ?`, model.ReminderStatusUnsubscribed, customerID, model.ReminderStatusActive)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
@@ -13,7 +14,11 @@ import (
 // TransactionRepository manages database operations for transaction.
 type TransactionRepository interface {
 	InsertList(order []model.Transaction) error
-	GetDetail(orderID string) ([]*model.Transaction, error)
+	InsertListTx(tx *sqlx.Tx, order []model.Transaction) error
+	GetDetail(orderID string, opts ...Option) ([]*model.Transaction, error)
+	GetDetailTx(tx *sqlx.Tx, orderID string, opts ...Option) ([]*model.Transaction, error)
+	DeleteByOrderIDTx(tx *sqlx.Tx, orderID string, at time.Time) error
+	RestoreByOrderIDTx(tx *sqlx.Tx, orderID string, deletedAt time.Time) error
 }
 
 type transactionRepoImpl struct {
@@ -32,7 +37,7 @@ func (r *transactionRepoImpl) scanRows(rows *sql.Rows) (items []*model.Transacti
 	for rows.Next() {
 		res := &model.Transaction{}
 		err = rows.Scan(&res.ID, &res.SKU, &res.Quantity, &res.OrderID,
-			&res.CreatedAt, &res.UpdatedAt, &res.DeletedAt, &res.Subtotal)
+			&res.CreatedAt, &res.UpdatedAt, &res.DeletedAt, &res.Subtotal, &res.Type, &res.DiscountAmount)
 		if err != nil {
 			return
 		}
@@ -43,36 +48,83 @@ func (r *transactionRepoImpl) scanRows(rows *sql.Rows) (items []*model.Transacti
 
 // InsertList inserts new list of transaction.
 func (r *transactionRepoImpl) InsertList(transaction []model.Transaction) error {
+	query, params := r.insertListQuery(transaction)
+	_, err := r.db.Exec(query, params...)
+	return err
+}
+
+// InsertListTx inserts new list of transaction inside the given database transaction.
+func (r *transactionRepoImpl) InsertListTx(tx *sqlx.Tx, transaction []model.Transaction) error {
+	query, params := r.insertListQuery(transaction)
+	_, err := tx.Exec(query, params...)
+	return err
+}
+
+func (r *transactionRepoImpl) insertListQuery(transaction []model.Transaction) (string, []interface{}) {
 	inserts := make([]string, len(transaction))
-	params := make([]interface{}, 0, 4*len(transaction))
+	params := make([]interface{}, 0, 6*len(transaction))
 
 	for index, item := range transaction {
-		values := make([]string, 0, 4)
+		values := make([]string, 0, 6)
 
-		values = append(values, "?", "?", "?", "?")
-		params = append(params, item.SKU, item.Quantity, item.OrderID, item.Subtotal)
+		values = append(values, "?", "?", "?", "?", "?", "?")
+		params = append(params, item.SKU, item.Quantity, item.OrderID, item.Subtotal, item.Type, item.DiscountAmount)
 
 		inserts[index] = fmt.Sprintf("(%s)", strings.Join(values, ", "))
 	}
 
-	_, err := r.db.Exec(fmt.Sprintf(`
+	return fmt.Sprintf(`
 		INSERT INTO transaction (
-			sku, quantity, order_id, subtotal
+			sku, quantity, order_id, subtotal, type, discount_amount
 		)
-		VALUES %s`, strings.Join(inserts, ", ")), params...)
-	return err
+		VALUES %s`, strings.Join(inserts, ", ")), params
 }
 
 // GetDetail returns transaction's details by order ID.
-func (r *transactionRepoImpl) GetDetail(orderID string) ([]*model.Transaction, error) {
+func (r *transactionRepoImpl) GetDetail(orderID string, opts ...Option) ([]*model.Transaction, error) {
 	res, err := r.db.Query(`
 		SELECT *
 		FROM transaction
-		WHERE order_id = ?`, orderID)
-	if err == sql.ErrNoRows {
-		return nil, nil
+		WHERE order_id = ? AND `+notDeleted("deleted_at", opts), orderID)
+	if err != nil {
+		return nil, err
 	}
+	defer res.Close()
 
 	items, err := r.scanRows(res)
 	return items, err
 }
+
+// GetDetailTx returns transaction's details by order ID inside the given database transaction.
+func (r *transactionRepoImpl) GetDetailTx(tx *sqlx.Tx, orderID string, opts ...Option) ([]*model.Transaction, error) {
+	res, err := tx.Query(`
+		SELECT *
+		FROM transaction
+		WHERE order_id = ? AND `+notDeleted("deleted_at", opts), orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	items, err := r.scanRows(res)
+	return items, err
+}
+
+// DeleteByOrderIDTx soft-deletes the transactions of an order at the given time.
+func (r *transactionRepoImpl) DeleteByOrderIDTx(tx *sqlx.Tx, orderID string, at time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE transaction
+		SET deleted_at = ?
+		WHERE order_id = ? AND deleted_at IS NULL`, at, orderID)
+	return err
+}
+
+// RestoreByOrderIDTx brings back the transactions of an order that were deleted
+// at deletedAt, which are the ones deleted along with the order.
+func (r *transactionRepoImpl) RestoreByOrderIDTx(tx *sqlx.Tx, orderID string, deletedAt time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE transaction
+		SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = ? AND deleted_at = ?`, orderID, deletedAt)
+	return err
+}
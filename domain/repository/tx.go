@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// TxRepository manages database transaction for operations across repositories.
+type TxRepository interface {
+	WithTx(fn func(tx *sqlx.Tx) error) error
+}
+
+type txRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewTxRepository returns new instance of txRepoImpl.
+func NewTxRepository() *txRepoImpl {
+	return &txRepoImpl{
+		db: database.DB,
+	}
+}
+
+// WithTx runs fn inside a database transaction. The transaction is committed
+// when fn returns nil, and rolled back when fn returns an error or panics.
+func (r *txRepoImpl) WithTx(fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	err = fn(tx)
+	return err
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/database"
+)
+
+// WebhookRepository manages database operations for webhook subscription and delivery.
+type WebhookRepository interface {
+	Create(subscription *model.WebhookSubscription) error
+	GetByID(id int64) (*model.WebhookSubscription, error)
+	List() ([]*model.WebhookSubscription, error)
+	GetActiveByEventType(eventType model.OutboxEventType) ([]*model.WebhookSubscription, error)
+	Update(subscription *model.WebhookSubscription) error
+	Delete(id int64) error
+	RecordResult(id int64, success bool, maxFailures int64) error
+	HasDelivered(subscriptionID int64, eventID int64) (bool, error)
+	CreateDelivery(delivery *model.WebhookDelivery) error
+	ListDeliveries(filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+}
+
+type webhookRepoImpl struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository returns new instance of webhookRepoImpl.
+func NewWebhookRepository() *webhookRepoImpl {
+	return &webhookRepoImpl{
+		db: database.DB,
+	}
+}
+
+// Create creates a new webhook subscription and store it into the database.
+func (r *webhookRepoImpl) Create(subscription *model.WebhookSubscription) error {
+	res, err := r.db.Exec(`
+		INSERT INTO webhook_subscription (url, event_types, secret, active)
+		VALUES (?, ?, ?, ?)`, subscription.URL, subscription.EventTypes, subscription.Secret, subscription.Active)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	subscription.ID = id
+
+	return err
+}
+
+// GetByID returns webhook subscription's details by ID.
+func (r *webhookRepoImpl) GetByID(id int64) (*model.WebhookSubscription, error) {
+	res := &model.WebhookSubscription{}
+	err := r.db.Get(res, `
+		SELECT *
+		FROM webhook_subscription
+		WHERE id = ? AND deleted_at IS NULL`, id)
+	if err == sql.ErrNoRows {
+		res = nil
+		err = nil
+	}
+	return res, err
+}
+
+// List returns every webhook subscription.
+func (r *webhookRepoImpl) List() ([]*model.WebhookSubscription, error) {
+	res := make([]*model.WebhookSubscription, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM webhook_subscription
+		WHERE deleted_at IS NULL
+		ORDER BY id`)
+	return res, err
+}
+
+// GetActiveByEventType returns the enabled webhook subscriptions receiving an event type.
+func (r *webhookRepoImpl) GetActiveByEventType(eventType model.OutboxEventType) ([]*model.WebhookSubscription, error) {
+	res := make([]*model.WebhookSubscription, 0)
+	err := r.db.Select(&res, `
+		SELECT *
+		FROM webhook_subscription
+		WHERE active = 1 AND deleted_at IS NULL
+			AND (FIND_IN_SET(?, event_types) > 0 OR FIND_IN_SET(?, event_types) > 0)
+		ORDER BY id`, eventType, model.WebhookAllEvents)
+	return res, err
+}
+
+// Update stores the URL, event types and state of a webhook subscription.
+func (r *webhookRepoImpl) Update(subscription *model.WebhookSubscription) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_subscription
+		SET url = ?, event_types = ?, active = ?, consecutive_failures = ?, disabled_at = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, subscription.URL, subscription.EventTypes, subscription.Active,
+		subscription.ConsecutiveFailures, subscription.DisabledAt, subscription.ID)
+	return err
+}
+
+// Delete soft deletes a webhook subscription.
+func (r *webhookRepoImpl) Delete(id int64) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_subscription
+		SET active = 0, deleted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL`, id)
+	return err
+}
+
+// RecordResult counts the consecutive failed deliveries of a webhook subscription,
+// and disables it once maxFailures deliveries in a row have failed.
+func (r *webhookRepoImpl) RecordResult(id int64, success bool, maxFailures int64) error {
+	if success {
+		_, err := r.db.Exec(`
+			UPDATE webhook_subscription
+			SET consecutive_failures = 0
+			WHERE id = ?`, id)
+		return err
+	}
+
+	// assignments are applied in order, so the checks see the incremented count
+	_, err := r.db.Exec(`
+		UPDATE webhook_subscription
+		SET consecutive_failures = consecutive_failures + 1,
+			disabled_at = IF(active = 1 AND consecutive_failures >= ?, CURRENT_TIMESTAMP, disabled_at),
+			active = IF(consecutive_failures >= ?, 0, active)
+		WHERE id = ?`, maxFailures, maxFailures, id)
+	return err
+}
+
+// HasDelivered returns true when an event has been delivered to a webhook subscription.
+func (r *webhookRepoImpl) HasDelivered(subscriptionID int64, eventID int64) (bool, error) {
+	var count int64
+	err := r.db.Get(&count, `
+		SELECT COUNT(*)
+		FROM webhook_delivery
+		WHERE subscription_id = ? AND event_id = ? AND status = ?`,
+		subscriptionID, eventID, model.WebhookDeliverySuccess)
+	return count > 0, err
+}
+
+// CreateDelivery stores a webhook delivery attempt.
+func (r *webhookRepoImpl) CreateDelivery(delivery *model.WebhookDelivery) error {
+	res, err := r.db.Exec(`
+		INSERT INTO webhook_delivery (subscription_id, event_id, event_type, attempt, status,
+			response_code, response_body, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, delivery.SubscriptionID, delivery.EventID, delivery.EventType,
+		delivery.Attempt, delivery.Status, delivery.ResponseCode, delivery.ResponseBody, delivery.Error,
+		delivery.DurationMS)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	delivery.ID = id
+
+	return err
+}
+
+// ListDeliveries returns webhook deliveries matching the filter, newest first.
+func (r *webhookRepoImpl) ListDeliveries(filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT *
+		FROM webhook_delivery
+		WHERE subscription_id = ?`
+	params := []interface{}{filter.SubscriptionID}
+
+	if filter.EventID != 0 {
+		query += ` AND event_id = ?`
+		params = append(params, filter.EventID)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		params = append(params, filter.Status)
+	}
+	if filter.BeforeID != 0 {
+		query += ` AND id < ?`
+		params = append(params, filter.BeforeID)
+	}
+
+	query += `
+		ORDER BY id DESC
+		LIMIT ?`
+	params = append(params, filter.Limit)
+
+	res := make([]*model.WebhookDelivery, 0)
+	err := r.db.Select(&res, query, params...)
+	return res, err
+}
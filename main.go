@@ -1,7 +1,15 @@
 package main
 
-import "github.com/richardsahvic/jamtangan/cmd"
+import (
+	"os"
+
+	"github.com/richardsahvic/jamtangan/cmd"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "dead-letter" {
+		os.Exit(cmd.RunDeadLetter(os.Args[2:]))
+	}
+
 	cmd.StartServer()
 }
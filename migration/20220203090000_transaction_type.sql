@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `transaction`
+  ADD COLUMN `type` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'sale';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transaction` DROP COLUMN `type`;
+-- +goose StatementEnd
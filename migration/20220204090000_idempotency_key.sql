@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `idempotency_key` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `idempotency_key` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  `fingerprint` char(64) COLLATE utf8mb4_general_ci NOT NULL,
+  `response_code` int NULL DEFAULT NULL,
+  `response_body` mediumtext COLLATE utf8mb4_general_ci NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_at` timestamp NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idempotency_key_UN` (`idempotency_key`),
+  KEY `idempotency_key_expires_at_IDX` (`expires_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `idempotency_key`;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `promotion` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `code` varchar(50) COLLATE utf8mb4_general_ci NULL DEFAULT NULL,
+  `name` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `type` varchar(20) COLLATE utf8mb4_general_ci NOT NULL,
+  `value` decimal(50,3) NOT NULL DEFAULT '0',
+  `buy_quantity` bigint NOT NULL DEFAULT '0',
+  `get_quantity` bigint NOT NULL DEFAULT '0',
+  `scope` varchar(10) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'order',
+  `brand_id` bigint NULL DEFAULT NULL,
+  `sku` varchar(200) COLLATE utf8mb4_general_ci NULL DEFAULT NULL,
+  `stackable` tinyint(1) NOT NULL DEFAULT '0',
+  `priority` int NOT NULL DEFAULT '0',
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
+  `usage_limit` bigint NULL DEFAULT NULL,
+  `per_customer_limit` bigint NULL DEFAULT NULL,
+  `usage_count` bigint NOT NULL DEFAULT '0',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `promotion_code_UN` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `promotion_usage` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `promotion_id` bigint NOT NULL,
+  `order_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `customer_id` bigint NULL DEFAULT NULL,
+  `discount_amount` decimal(50,3) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `promotion_usage_customer_IDX` (`promotion_id`, `customer_id`) USING BTREE,
+  CONSTRAINT `promotion_usage_FK` FOREIGN KEY (`promotion_id`) REFERENCES `promotion` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+ALTER TABLE `orders`
+  ADD COLUMN `discount_amount` decimal(50,3) NOT NULL DEFAULT '0';
+
+ALTER TABLE `transaction`
+  ADD COLUMN `discount_amount` decimal(50,3) NOT NULL DEFAULT '0';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `transaction` DROP COLUMN `discount_amount`;
+ALTER TABLE `orders` DROP COLUMN `discount_amount`;
+DROP TABLE `promotion_usage`;
+DROP TABLE `promotion`;
+-- +goose StatementEnd
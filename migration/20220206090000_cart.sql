@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `cart` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `cart_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `customer_id` bigint NULL DEFAULT NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'active',
+  `order_id` varchar(100) COLLATE utf8mb4_general_ci NULL DEFAULT NULL,
+  `expires_at` timestamp NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cart_cart_id_UN` (`cart_id`),
+  KEY `cart_customer_id_IDX` (`customer_id`) USING BTREE,
+  KEY `cart_expires_at_IDX` (`expires_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `cart_item` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `cart_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `sku` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `quantity` bigint NOT NULL,
+  `price` decimal(50,3) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cart_item_cart_id_sku_UN` (`cart_id`, `sku`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `cart_item`;
+DROP TABLE `cart`;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `outbox_event` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `event_type` varchar(50) COLLATE utf8mb4_general_ci NOT NULL,
+  `aggregate_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `payload` mediumtext COLLATE utf8mb4_general_ci NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'pending',
+  `attempts` int NOT NULL DEFAULT '0',
+  `delivered_channels` varchar(255) COLLATE utf8mb4_general_ci NOT NULL DEFAULT '',
+  `last_error` text COLLATE utf8mb4_general_ci NULL,
+  `next_attempt_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `sent_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `outbox_event_status_next_attempt_at_IDX` (`status`, `next_attempt_at`) USING BTREE,
+  KEY `outbox_event_aggregate_id_IDX` (`aggregate_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `outbox_event`;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `webhook_subscription` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `url` varchar(2048) COLLATE utf8mb4_general_ci NOT NULL,
+  `event_types` varchar(1000) COLLATE utf8mb4_general_ci NOT NULL,
+  `secret` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `consecutive_failures` int NOT NULL DEFAULT '0',
+  `disabled_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `webhook_subscription_active_IDX` (`active`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `webhook_delivery` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `subscription_id` bigint NOT NULL,
+  `event_id` bigint NOT NULL,
+  `event_type` varchar(50) COLLATE utf8mb4_general_ci NOT NULL,
+  `attempt` int NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL,
+  `response_code` int NULL DEFAULT NULL,
+  `response_body` text COLLATE utf8mb4_general_ci NULL,
+  `error` text COLLATE utf8mb4_general_ci NULL,
+  `duration_ms` bigint NOT NULL DEFAULT '0',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `webhook_delivery_subscription_id_IDX` (`subscription_id`, `id`) USING BTREE,
+  KEY `webhook_delivery_event_id_IDX` (`event_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `webhook_delivery`;
+DROP TABLE `webhook_subscription`;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `customer` (
+  `id` bigint NOT NULL,
+  `email` varchar(255) COLLATE utf8mb4_general_ci NULL DEFAULT NULL,
+  `locale` varchar(10) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'en',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `customer`;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `customer`
+  ADD COLUMN `timezone` varchar(64) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'UTC' AFTER `locale`,
+  ADD COLUMN `quiet_hours_start` char(5) COLLATE utf8mb4_general_ci NULL DEFAULT NULL AFTER `timezone`,
+  ADD COLUMN `quiet_hours_end` char(5) COLLATE utf8mb4_general_ci NULL DEFAULT NULL AFTER `quiet_hours_start`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `notification_preference` (
+  `customer_id` bigint NOT NULL,
+  `channel` varchar(20) COLLATE utf8mb4_general_ci NOT NULL,
+  `category` varchar(20) COLLATE utf8mb4_general_ci NOT NULL,
+  `enabled` tinyint(1) NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`customer_id`, `channel`, `category`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `notification_preference`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `customer`
+  DROP COLUMN `quiet_hours_end`,
+  DROP COLUMN `quiet_hours_start`,
+  DROP COLUMN `timezone`;
+-- +goose StatementEnd
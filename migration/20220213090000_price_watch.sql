@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `price_watch` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `customer_id` bigint NOT NULL,
+  `product_id` bigint NOT NULL,
+  `sku` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  `base_price` decimal(50,3) NOT NULL,
+  `target_price` decimal(50,3) NOT NULL,
+  `drop_percent` decimal(50,3) NULL DEFAULT NULL,
+  `last_notified_price` decimal(50,3) NULL DEFAULT NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'active',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `notified_at` timestamp NULL DEFAULT NULL,
+  `cancelled_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `price_watch_product_status` (`product_id`, `status`, `target_price`),
+  KEY `price_watch_customer` (`customer_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `price_watch`;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `reminder` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `kind` varchar(20) COLLATE utf8mb4_general_ci NOT NULL,
+  `target_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `customer_id` bigint NOT NULL,
+  `step` int NOT NULL DEFAULT '0',
+  `idle_since` timestamp NOT NULL,
+  `next_at` timestamp NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'active',
+  `unsubscribe_token` varchar(64) COLLATE utf8mb4_general_ci NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `last_sent_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `reminder_target_UN` (`kind`, `target_id`),
+  UNIQUE KEY `reminder_unsubscribe_token_UN` (`unsubscribe_token`),
+  KEY `reminder_status_next_at` (`status`, `next_at`),
+  KEY `reminder_customer` (`customer_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `reminder_opt_out` (
+  `customer_id` bigint NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`customer_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `reminder_opt_out`;
+DROP TABLE `reminder`;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `dead_letter` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `event_id` bigint NOT NULL,
+  `event_type` varchar(50) COLLATE utf8mb4_general_ci NOT NULL,
+  `aggregate_id` varchar(100) COLLATE utf8mb4_general_ci NOT NULL,
+  `payload` mediumtext COLLATE utf8mb4_general_ci NOT NULL,
+  `attempts` int NOT NULL DEFAULT '0',
+  `delivered_channels` varchar(255) COLLATE utf8mb4_general_ci NOT NULL DEFAULT '',
+  `last_error` text COLLATE utf8mb4_general_ci NULL,
+  `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'dead',
+  `retried_event_id` bigint NULL DEFAULT NULL,
+  `event_created_at` timestamp NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `retried_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `dead_letter_event_id_UN` (`event_id`),
+  KEY `dead_letter_status_event_type` (`status`, `event_type`),
+  KEY `dead_letter_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `dead_letter`;
+-- +goose StatementEnd
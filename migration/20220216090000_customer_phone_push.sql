@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `customer`
+  ADD COLUMN `phone` varchar(20) COLLATE utf8mb4_general_ci NULL DEFAULT NULL AFTER `email`,
+  ADD COLUMN `push_token` varchar(255) COLLATE utf8mb4_general_ci NULL DEFAULT NULL AFTER `phone`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `customer`
+  DROP COLUMN `push_token`,
+  DROP COLUMN `phone`;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `product`
+  ADD COLUMN `version` bigint NOT NULL DEFAULT '1' AFTER `price`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `product`
+  DROP COLUMN `version`;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- keep the oldest brand of each name, later duplicates get their ID appended
+UPDATE brand b
+  JOIN (SELECT name, MIN(id) AS id FROM brand GROUP BY name) k ON b.name = k.name AND b.id <> k.id
+  SET b.name = CONCAT(b.name, ' (', b.id, ')');
+
+ALTER TABLE `brand`
+  ADD UNIQUE KEY `brand_name_UN` (`name`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `brand`
+  DROP INDEX `brand_name_UN`;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `product`
+  ADD KEY `product_created_at` (`created_at`, `id`),
+  ADD KEY `product_price` (`price`, `id`),
+  ADD KEY `product_stock` (`stock`, `id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `product`
+  DROP KEY `product_created_at`,
+  DROP KEY `product_price`,
+  DROP KEY `product_stock`;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `product`
+  ADD COLUMN `name` varchar(200) COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' AFTER `sku`,
+  ADD COLUMN `slug` varchar(200) COLLATE utf8mb4_general_ci DEFAULT NULL AFTER `name`,
+  ADD COLUMN `description` text COLLATE utf8mb4_general_ci AFTER `slug`,
+  ADD COLUMN `attributes` json DEFAULT NULL AFTER `description`,
+  ADD COLUMN `status` varchar(20) COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'active' AFTER `version`;
+
+-- existing products are named after their SKU and stay orderable
+UPDATE product
+  SET name = sku, slug = TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(sku, '[^A-Za-z0-9]+', '-')));
+
+UPDATE product p
+  JOIN (SELECT slug, MIN(id) AS id FROM product GROUP BY slug) k ON p.slug = k.slug AND p.id <> k.id
+  SET p.slug = CONCAT(p.slug, '-', p.id);
+
+ALTER TABLE `product`
+  MODIFY COLUMN `slug` varchar(200) COLLATE utf8mb4_general_ci NOT NULL,
+  ADD UNIQUE KEY `product_slug_UN` (`slug`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `product`
+  DROP INDEX `product_slug_UN`,
+  DROP COLUMN `name`,
+  DROP COLUMN `slug`,
+  DROP COLUMN `description`,
+  DROP COLUMN `attributes`,
+  DROP COLUMN `status`;
+-- +goose StatementEnd
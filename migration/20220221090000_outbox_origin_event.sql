@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `outbox_event`
+  ADD COLUMN `origin_event_id` bigint NULL DEFAULT NULL AFTER `id`;
+
+ALTER TABLE `dead_letter`
+  ADD COLUMN `origin_event_id` bigint NULL DEFAULT NULL AFTER `event_id`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `outbox_event`
+  DROP COLUMN `origin_event_id`;
+
+ALTER TABLE `dead_letter`
+  DROP COLUMN `origin_event_id`;
+-- +goose StatementEnd
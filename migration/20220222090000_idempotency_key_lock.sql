@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `idempotency_key`
+  ADD COLUMN `locked_until` timestamp NULL DEFAULT NULL AFTER `response_body`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `idempotency_key`
+  DROP COLUMN `locked_until`;
+-- +goose StatementEnd
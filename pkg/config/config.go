@@ -3,6 +3,7 @@ package config
 import (
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -74,6 +75,11 @@ func GetFloat64(k string) float64 {
 	return config.GetFloat64(k)
 }
 
+//GetDuration get time.Duration
+func GetDuration(k string) time.Duration {
+	return config.GetDuration(k)
+}
+
 //GetStringSlice get []string
 func GetStringSlice(k string) []string {
 	return config.GetStringSlice(k)
@@ -0,0 +1,290 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Email contains a message with a plain-text and an HTML body.
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// SMTPMailer sends emails through an SMTP server.
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+// NewSMTPMailer returns new instance of SMTPMailer, credentials are only used when username is set.
+// The timeout bounds the whole conversation with the server, from dialing to the end of the message.
+func NewSMTPMailer(host, port, username, password, from string, timeout time.Duration) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		timeout:  timeout,
+	}
+}
+
+// Send sends an email as a multipart/alternative message.
+func (m *SMTPMailer) Send(ctx context.Context, email Email) error {
+	message, err := m.build(email)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	// a cancelled context stops a conversation in progress
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	err = m.send(conn, email.To, message)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// send writes a message over an open connection the way smtp.SendMail does,
+// upgrading to TLS when the server offers it.
+func (m *SMTPMailer) send(conn net.Conn, to string, message []byte) error {
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: m.host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		err = client.Auth(smtp.PlainAuth("", m.username, m.password, m.host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(m.from)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(message)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// build returns the raw message of an email.
+func (m *SMTPMailer) build(email Email) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "8bit")
+
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		w.Write([]byte(strings.ReplaceAll(part.content, "\n", "\r\n")))
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", m.from)
+	fmt.Fprintf(&message, "To: %s\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", email.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// EmailTemplates renders emails from the templates of a directory laid out as
+// <locale>/<name>.txt and <locale>/<name>.html, where name is usually an event
+// type. The subject is the "subject" template defined in the .txt file.
+type EmailTemplates struct {
+	defaultLocale string
+	text          map[string]*texttemplate.Template
+	html          map[string]*htmltemplate.Template
+}
+
+// LoadEmailTemplates parses every template of dir, emails in a locale without
+// its own template use the one of defaultLocale.
+func LoadEmailTemplates(dir string, defaultLocale string) (*EmailTemplates, error) {
+	t := &EmailTemplates{
+		defaultLocale: defaultLocale,
+		text:          make(map[string]*texttemplate.Template),
+		html:          make(map[string]*htmltemplate.Template),
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		locale := filepath.Base(filepath.Dir(path))
+		ext := filepath.Ext(path)
+		name := strings.TrimSuffix(filepath.Base(path), ext)
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch ext {
+		case ".txt":
+			t.text[templateKey(locale, name)], err = texttemplate.New(name).Parse(string(content))
+		case ".html":
+			t.html[templateKey(locale, name)], err = htmltemplate.New(name).Parse(string(content))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	return t, nil
+}
+
+// Has returns true when name has a template in locale or in the default locale.
+func (t *EmailTemplates) Has(locale string, name string) bool {
+	_, ok := t.text[templateKey(t.locale(locale, name), name)]
+	return ok
+}
+
+// Render returns the email rendered from the templates of name in locale.
+func (t *EmailTemplates) Render(locale string, name string, to string, data interface{}) (Email, error) {
+	key := templateKey(t.locale(locale, name), name)
+
+	text, ok := t.text[key]
+	if !ok {
+		return Email{}, fmt.Errorf("no email template %s", key)
+	}
+
+	email := Email{To: to}
+
+	var buf bytes.Buffer
+	err := text.ExecuteTemplate(&buf, "subject", data)
+	if err != nil {
+		return Email{}, err
+	}
+	email.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	err = text.Execute(&buf, data)
+	if err != nil {
+		return Email{}, err
+	}
+	email.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	if html, ok := t.html[key]; ok {
+		buf.Reset()
+		err = html.Execute(&buf, data)
+		if err != nil {
+			return Email{}, err
+		}
+		email.HTML = buf.String()
+	}
+
+	return email, nil
+}
+
+// locale returns locale when it has a template for name, the default locale otherwise.
+func (t *EmailTemplates) locale(locale string, name string) string {
+	if _, ok := t.text[templateKey(locale, name)]; ok {
+		return locale
+	}
+	return t.defaultLocale
+}
+
+func templateKey(locale string, name string) string {
+	return locale + "/" + name
+}
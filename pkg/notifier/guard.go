@@ -0,0 +1,166 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// List of reasons a Guard refuses to send.
+var (
+	ErrRateLimited = errors.New("rate limit exceeded")
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// UnavailableError is returned by a Guard that did not send, RetryAt is the
+// earliest time sending may be allowed again.
+type UnavailableError struct {
+	Name    string
+	Reason  error
+	RetryAt time.Time
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Reason.Error())
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Reason
+}
+
+// permanentError is an error that will happen again if the request is retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as an error of the request rather than of the provider,
+// such as an invalid phone number, so it does not trip the circuit breaker.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent returns true when err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Guard protects a notification provider with a token bucket rate limit and a
+// circuit breaker. Both refuse right away instead of waiting, so a slow or failing
+// provider does not hold up the channels after it.
+//
+// The breaker opens after threshold consecutive failures and refuses every send
+// until cooldown has passed, then lets a single send through: the breaker closes
+// when it succeeds and opens again when it fails.
+type Guard struct {
+	name      string
+	rate      float64
+	burst     float64
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	tokens    float64
+	filledAt  time.Time
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewGuard returns new instance of Guard allowing rate sends per second with
+// bursts of burst sends. A rate of 0 disables the rate limit and a threshold of
+// 0 disables the circuit breaker.
+func NewGuard(name string, rate float64, burst int, threshold int, cooldown time.Duration) *Guard {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Guard{
+		name:      name,
+		rate:      rate,
+		burst:     float64(burst),
+		threshold: threshold,
+		cooldown:  cooldown,
+		tokens:    float64(burst),
+		filledAt:  time.Now(),
+	}
+}
+
+// Do calls send when the rate limit and the circuit breaker allow it, and
+// returns an *UnavailableError when they do not. Errors of send count as
+// failures of the provider unless they are permanent.
+func (g *Guard) Do(send func() error) error {
+	err := g.acquire(time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = send()
+	g.release(time.Now(), err == nil || IsPermanent(err))
+	return err
+}
+
+// acquire takes a token and, when the breaker is half open, the single send
+// allowed through.
+func (g *Guard) acquire(now time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	probe := false
+	if !g.openUntil.IsZero() {
+		if now.Before(g.openUntil) || g.probing {
+			retryAt := g.openUntil
+			if g.probing {
+				retryAt = now.Add(g.cooldown)
+			}
+			return &UnavailableError{Name: g.name, Reason: ErrCircuitOpen, RetryAt: retryAt}
+		}
+		probe = true
+	}
+
+	if g.rate > 0 {
+		g.tokens += now.Sub(g.filledAt).Seconds() * g.rate
+		if g.tokens > g.burst {
+			g.tokens = g.burst
+		}
+		g.filledAt = now
+
+		if g.tokens < 1 {
+			wait := time.Duration((1 - g.tokens) / g.rate * float64(time.Second))
+			return &UnavailableError{Name: g.name, Reason: ErrRateLimited, RetryAt: now.Add(wait)}
+		}
+		g.tokens--
+	}
+
+	g.probing = probe
+	return nil
+}
+
+// release records the result of a send.
+func (g *Guard) release(now time.Time, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	probe := g.probing
+	g.probing = false
+
+	if ok {
+		g.failures = 0
+		g.openUntil = time.Time{}
+		return
+	}
+
+	g.failures++
+	if g.threshold > 0 && (probe || g.failures >= g.threshold) {
+		g.openUntil = now.Add(g.cooldown)
+	}
+}
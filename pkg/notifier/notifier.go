@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+)
+
+// Channel delivers outbox events to one destination, such as email or webhooks.
+// Send may be called more than once for the same event, so receivers should use
+// the event ID to drop duplicates.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// LogChannel writes every event to the application log.
+type LogChannel struct{}
+
+// NewLogChannel returns new instance of LogChannel.
+func NewLogChannel() *LogChannel {
+	return &LogChannel{}
+}
+
+// Name returns the name of the channel.
+func (c *LogChannel) Name() string {
+	return "log"
+}
+
+// Send writes the event to the application log.
+func (c *LogChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	log := logger.GetLoggerContext(ctx, "notifier", "Send")
+	log.Info(fmt.Sprintf("event %d %s %s: %s", event.ID, event.EventType, event.AggregateID, string(event.Payload)))
+	return nil
+}
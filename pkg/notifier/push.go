@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ErrPushTokenInvalid is returned, as a permanent error, when the push API no
+// longer knows the device token, for example because the app was uninstalled.
+var ErrPushTokenInvalid = errors.New("push token is not registered")
+
+// Push contains a notification to the device of a push token. Data values are
+// handed to the app along with the notification.
+type Push struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// pushRequest is the body posted to the push API.
+type pushRequest struct {
+	To           string            `json:"to"`
+	Notification pushNotification  `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type pushNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// pushResponse is the body answered by the push API.
+type pushResponse struct {
+	Success int `json:"success"`
+	Failure int `json:"failure"`
+	Results []struct {
+		MessageID string `json:"message_id"`
+		Error     string `json:"error"`
+	} `json:"results"`
+}
+
+// List of push API result errors telling the token is invalid.
+var invalidPushTokenErrors = map[string]bool{
+	"NotRegistered":       true,
+	"InvalidRegistration": true,
+	"MismatchSenderId":    true,
+}
+
+// PushSender sends notifications through an FCM-style HTTP API, which accepts a
+// JSON body of "to", "notification" and "data" authorized with "key=<server key>"
+// and reports the result of every token in "results".
+type PushSender struct {
+	client    *http.Client
+	url       string
+	serverKey string
+}
+
+// NewPushSender returns new instance of PushSender.
+func NewPushSender(url, serverKey string, timeout time.Duration) *PushSender {
+	return &PushSender{
+		client:    &http.Client{Timeout: timeout},
+		url:       url,
+		serverKey: serverKey,
+	}
+}
+
+// Send posts a notification to the push API. It returns an error when the API
+// can not be reached or the notification was not accepted, which wraps
+// ErrPushTokenInvalid when the token has to be dropped.
+func (s *PushSender) Send(ctx context.Context, push Push) error {
+	body, err := json.Marshal(pushRequest{
+		To:           push.Token,
+		Notification: pushNotification{Title: push.Title, Body: push.Body},
+		Data:         push.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+s.serverKey)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxProviderResponseLength))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err = fmt.Errorf("push API responded with status %d: %s", res.StatusCode, string(respBody))
+		if rejected(res.StatusCode) {
+			return Permanent(err)
+		}
+		return err
+	}
+
+	var resp pushResponse
+	err = json.Unmarshal(respBody, &resp)
+	if err != nil {
+		return fmt.Errorf("unexpected push API response: %s", string(respBody))
+	}
+
+	if resp.Failure == 0 {
+		return nil
+	}
+
+	if len(resp.Results) == 0 {
+		return errors.New("push API failed without a result")
+	}
+
+	result := resp.Results[0].Error
+	if invalidPushTokenErrors[result] {
+		return Permanent(fmt.Errorf("%w: %s", ErrPushTokenInvalid, result))
+	}
+	return fmt.Errorf("push API failed: %s", result)
+}
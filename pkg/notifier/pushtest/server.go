@@ -0,0 +1,128 @@
+// Package pushtest provides an in-process FCM-style push API that keeps the
+// notifications it receives, so push delivery can be tested without a real provider.
+package pushtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Message contains a notification received by the Server.
+type Message struct {
+	ServerKey string
+	Token     string
+	Title     string
+	Body      string
+	Data      map[string]string
+}
+
+// request is the body posted to the Server.
+type request struct {
+	To           string `json:"to"`
+	Notification struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"notification"`
+	Data map[string]string `json:"data"`
+}
+
+// Server is a minimal push API accepting every notification with any server key,
+// except for unregistered tokens or when it is told to answer with an error status.
+type Server struct {
+	server       *httptest.Server
+	mu           sync.Mutex
+	messages     []Message
+	requests     int
+	status       int
+	unregistered map[string]bool
+}
+
+// NewServer starts a Server on a random local port.
+func NewServer() *Server {
+	s := &Server{unregistered: make(map[string]bool)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL returns the URL notifications are posted to.
+func (s *Server) URL() string {
+	return s.server.URL + "/fcm/send"
+}
+
+// Messages returns the notifications accepted so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+	return messages
+}
+
+// Requests returns how many requests the Server received, accepted or not.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// SetStatus makes the Server answer every request with status instead of
+// accepting the notification, 0 accepts notifications again.
+func (s *Server) SetStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Unregister makes the Server answer NotRegistered for token.
+func (s *Server) Unregister(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unregistered[token] = true
+}
+
+// Close stops the Server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+
+	if s.status != 0 {
+		http.Error(w, http.StatusText(s.status), s.status)
+		return
+	}
+
+	var req request
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil || req.To == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.unregistered[req.To] {
+		fmt.Fprint(w, `{"success":0,"failure":1,"results":[{"error":"NotRegistered"}]}`)
+		return
+	}
+
+	s.messages = append(s.messages, Message{
+		ServerKey: strings.TrimPrefix(r.Header.Get("Authorization"), "key="),
+		Token:     req.To,
+		Title:     req.Notification.Title,
+		Body:      req.Notification.Body,
+		Data:      req.Data,
+	})
+	fmt.Fprintf(w, `{"success":1,"failure":0,"results":[{"message_id":"push-%d"}]}`, len(s.messages))
+}
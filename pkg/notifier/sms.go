@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// maxProviderResponseLength is the longest part of a provider response body that is read.
+const maxProviderResponseLength = 2000
+
+// SMS contains a text message to a phone number in E.164 format.
+type SMS struct {
+	To   string
+	Text string
+}
+
+// smsRequest is the body posted to the SMS gateway.
+type smsRequest struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// SMSGateway sends text messages through a generic HTTP gateway, which accepts a
+// JSON body of "from", "to" and "text" authorized with a bearer API key. Most SMS
+// and WhatsApp providers can be reached this way or through a small adapter.
+type SMSGateway struct {
+	client *http.Client
+	url    string
+	apiKey string
+	sender string
+}
+
+// NewSMSGateway returns new instance of SMSGateway.
+func NewSMSGateway(url, apiKey, sender string, timeout time.Duration) *SMSGateway {
+	return &SMSGateway{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		apiKey: apiKey,
+		sender: sender,
+	}
+}
+
+// Send posts a text message to the gateway. It returns an error when the gateway
+// can not be reached or does not answer with a 2xx status, which is permanent
+// when the gateway rejected the message itself.
+func (g *SMSGateway) Send(ctx context.Context, sms SMS) error {
+	body, err := json.Marshal(smsRequest{From: g.sender, To: sms.To, Text: sms.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxProviderResponseLength))
+	err = fmt.Errorf("sms gateway responded with status %d: %s", res.StatusCode, string(respBody))
+	if rejected(res.StatusCode) {
+		return Permanent(err)
+	}
+	return err
+}
+
+// rejected returns true when a provider status tells the request is invalid,
+// rather than that the provider failed, is overloaded or refused our credentials.
+func rejected(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400 && statusCode <= 499
+}
@@ -0,0 +1,99 @@
+// Package smstest provides an in-process SMS gateway that keeps the messages it
+// receives, so SMS delivery can be tested without a real provider.
+package smstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Message contains a text message received by the Server.
+type Message struct {
+	APIKey string
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Text   string `json:"text"`
+}
+
+// Server is a minimal SMS gateway accepting every message with any API key,
+// unless it is told to answer with an error status.
+type Server struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	messages []Message
+	requests int
+	status   int
+}
+
+// NewServer starts a Server on a random local port.
+func NewServer() *Server {
+	s := &Server{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL returns the URL messages are posted to.
+func (s *Server) URL() string {
+	return s.server.URL + "/messages"
+}
+
+// Messages returns the messages accepted so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+	return messages
+}
+
+// Requests returns how many requests the Server received, accepted or not.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// SetStatus makes the Server answer every request with status instead of
+// accepting the message, 0 accepts messages again.
+func (s *Server) SetStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Close stops the Server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		fmt.Fprintf(w, `{"error":%q}`, http.StatusText(s.status))
+		return
+	}
+
+	var message Message
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&message) != nil || message.To == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid message"}`)
+		return
+	}
+
+	message.APIKey = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.messages = append(s.messages, message)
+	fmt.Fprintf(w, `{"message_id":"sms-%d","status":"queued"}`, len(s.messages))
+}
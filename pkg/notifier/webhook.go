@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// List of headers sent with every webhook request.
+const (
+	WebhookEventIDHeader   = "X-Webhook-Id"
+	WebhookEventTypeHeader = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookResponseLength is the longest part of a webhook response body that is read.
+const maxWebhookResponseLength = 2000
+
+// ErrWebhookAddress is returned when a webhook endpoint is not on a public address.
+var ErrWebhookAddress = errors.New("webhook endpoint is not a public address")
+
+// privateNetworks are the ranges webhooks are never sent to, so an endpoint can
+// not be used to reach the services next to the dispatcher.
+var privateNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+// Resolver returns the addresses of a host.
+type Resolver func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+// IsPublicIP returns true when ip is not a loopback, private, link-local or
+// unspecified address.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// SignWebhook returns the signature of a webhook body sent at timestamp. Receivers
+// compute HMAC-SHA256 of "<timestamp>.<body>" with the shared secret, compare it to
+// the X-Webhook-Signature header, and reject requests with an old timestamp so a
+// captured request can not be replayed.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookResponse contains the response of a webhook endpoint.
+type WebhookResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// WebhookSender posts signed webhook requests. It only connects to public
+// addresses, so a host resolving to a private one after it was registered is
+// still refused.
+type WebhookSender struct {
+	client       *http.Client
+	allowPrivate bool
+}
+
+// NewWebhookSender returns new instance of WebhookSender.
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	s := &WebhookSender{}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: s.checkAddress,
+	}
+	s.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+	return s
+}
+
+// SetAllowPrivate lets the sender connect to private addresses, for local
+// endpoints in development and tests.
+func (s *WebhookSender) SetAllowPrivate(allow bool) *WebhookSender {
+	s.allowPrivate = allow
+	return s
+}
+
+// checkAddress refuses connections to addresses that are not public.
+func (s *WebhookSender) checkAddress(network string, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(net.ParseIP(host)) {
+		return ErrWebhookAddress
+	}
+	return nil
+}
+
+// Send posts a signed event body to url. It returns an error when the endpoint
+// can not be reached or does not answer with a 2xx status, along with the
+// response when there is one.
+func (s *WebhookSender) Send(ctx context.Context, url string, secret string, eventID int64, eventType string,
+	body []byte) (*WebhookResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventIDHeader, strconv.FormatInt(eventID, 10))
+	req.Header.Set(WebhookEventTypeHeader, eventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxWebhookResponseLength))
+	resp := &WebhookResponse{
+		StatusCode: res.StatusCode,
+		Body:       string(respBody),
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return resp, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return resp, nil
+}
@@ -0,0 +1,152 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 64
+
+// Event is a message published to a topic.
+type Event struct {
+	ID    int64
+	Topic string
+	Type  string
+	Data  []byte
+}
+
+// Hub is an in-process publish/subscribe hub. It keeps the latest events so a
+// subscriber that reconnects can resume after the last event it received.
+// Event IDs start from the current time in microseconds, so they keep
+// increasing across restarts and an ID of a previous process is detected as
+// missed instead of being mixed up with the new events.
+type Hub struct {
+	mu          sync.Mutex
+	lastID      int64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]bool
+}
+
+// Subscription receives the events of a topic, or of every topic when its topic is empty.
+type Subscription struct {
+	hub    *Hub
+	topic  string
+	events chan Event
+	closed bool
+	// Missed is true when events after the requested ID are no longer kept, the
+	// subscriber has to reload its state.
+	Missed bool
+}
+
+// NewHub returns new instance of Hub keeping the latest historySize events.
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		lastID:      time.Now().UnixNano() / int64(time.Microsecond),
+		historySize: historySize,
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Publish sends an event with data encoded as JSON to the subscribers of the
+// topic. Publishing on a nil hub does nothing. A subscriber that does not keep
+// up is dropped, it resumes with the last event ID it received.
+func (h *Hub) Publish(topic string, eventType string, data interface{}) error {
+	if h == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event := Event{ID: h.lastID, Topic: topic, Type: eventType, Data: raw}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for subscription := range h.subscribers {
+		if !subscription.matches(topic) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a subscription to topic, or to every topic when topic is
+// empty. When lastEventID is set, the kept events after it are delivered first.
+func (h *Hub) Subscribe(topic string, lastEventID int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	replay := make([]Event, 0)
+	missed := false
+	if lastEventID > 0 {
+		// the event right after lastEventID has to be kept for nothing to be missed
+		missed = lastEventID < h.lastID && (len(h.history) == 0 || h.history[0].ID > lastEventID+1)
+
+		for _, event := range h.history {
+			if event.ID > lastEventID && (topic == "" || event.Topic == topic) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		hub:    h,
+		topic:  topic,
+		events: make(chan Event, len(replay)+subscriberBuffer),
+		Missed: missed,
+	}
+	for _, event := range replay {
+		subscription.events <- event
+	}
+
+	h.subscribers[subscription] = true
+	return subscription
+}
+
+// Events returns the channel events are received from, it is closed when the
+// subscription is closed or dropped.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+func (s *Subscription) matches(topic string) bool {
+	return s.topic == "" || s.topic == topic
+}
+
+// remove unregisters a subscription, the caller holds the lock.
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+
+	subscription.closed = true
+	delete(h.subscribers, subscription)
+	close(subscription.events)
+}
@@ -0,0 +1,189 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/pkg/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+// receive returns the events waiting on a subscription without blocking.
+func receive(subscription *pubsub.Subscription) []pubsub.Event {
+	events := make([]pubsub.Event, 0)
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// publish publishes events to topics in order and returns their IDs.
+func publish(t *testing.T, hub *pubsub.Hub, topics ...string) []int64 {
+	watcher := hub.Subscribe("", 0)
+	defer watcher.Close()
+
+	for _, topic := range topics {
+		err := hub.Publish(topic, "order.status_changed", map[string]string{"topic": topic})
+		assert.Nil(t, err)
+	}
+
+	ids := make([]int64, 0)
+	for _, event := range receive(watcher) {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestSubscribe(t *testing.T) {
+	// TestSubscribeEmptyHistory
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+
+		// Case: an ID of a previous process is missed when nothing is kept
+		subscription := hub.Subscribe("", 1)
+		assert.True(t, subscription.Missed)
+		assert.Empty(t, receive(subscription))
+
+		// Case: no last event ID is never missed
+		subscription = hub.Subscribe("", 0)
+		assert.False(t, subscription.Missed)
+	}(t)
+
+	// TestSubscribeHistory
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+		ids := publish(t, hub, "ORDER-1", "ORDER-1", "ORDER-1")
+		assert.Len(t, ids, 3)
+
+		// Case: the kept events after the last event ID are replayed
+		subscription := hub.Subscribe("", ids[0])
+		assert.False(t, subscription.Missed)
+		events := receive(subscription)
+		assert.Len(t, events, 2)
+		assert.Equal(t, ids[1], events[0].ID)
+		assert.Equal(t, ids[2], events[1].ID)
+		assert.Equal(t, `{"topic":"ORDER-1"}`, string(events[0].Data))
+
+		// Case: a subscriber that is up to date has nothing to replay
+		subscription = hub.Subscribe("", ids[2])
+		assert.False(t, subscription.Missed)
+		assert.Empty(t, receive(subscription))
+
+		// Case: an ID of a previous process is missed and every kept event is replayed
+		subscription = hub.Subscribe("", ids[0]-1000)
+		assert.True(t, subscription.Missed)
+		assert.Len(t, receive(subscription), 3)
+	}(t)
+
+	// TestSubscribeFullHistory
+	func(t *testing.T) {
+		hub := pubsub.NewHub(2)
+		ids := publish(t, hub, "ORDER-1", "ORDER-1", "ORDER-1")
+
+		// Case: the event right after the last event ID is still kept
+		subscription := hub.Subscribe("", ids[0])
+		assert.False(t, subscription.Missed)
+		assert.Len(t, receive(subscription), 2)
+
+		// Case: the event right after the last event ID is no longer kept
+		subscription = hub.Subscribe("", ids[0]-1)
+		assert.True(t, subscription.Missed)
+		events := receive(subscription)
+		assert.Len(t, events, 2)
+		assert.Equal(t, ids[1], events[0].ID)
+	}(t)
+
+	// TestSubscribeTopic
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+		ids := publish(t, hub, "ORDER-1", "ORDER-2", "ORDER-1")
+
+		// Case: only the events of the topic are replayed
+		subscription := hub.Subscribe("ORDER-1", ids[0]-1)
+		assert.False(t, subscription.Missed)
+		events := receive(subscription)
+		assert.Len(t, events, 2)
+		assert.Equal(t, ids[0], events[0].ID)
+		assert.Equal(t, ids[2], events[1].ID)
+
+		// Case: only the events of the topic are received
+		err := hub.Publish("ORDER-2", "order.status_changed", nil)
+		assert.Nil(t, err)
+		err = hub.Publish("ORDER-1", "order.status_changed", nil)
+		assert.Nil(t, err)
+		events = receive(subscription)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "ORDER-1", events[0].Topic)
+
+		// Case: a subscription without topic receives every topic
+		all := hub.Subscribe("", ids[0]-1)
+		assert.Len(t, receive(all), 5)
+	}(t)
+}
+
+func TestPublish(t *testing.T) {
+	// TestPublishNilHub
+	func(t *testing.T) {
+		var hub *pubsub.Hub
+
+		// Case: publishing on a nil hub does nothing
+		err := hub.Publish("ORDER-1", "order.status_changed", nil)
+		assert.Nil(t, err)
+	}(t)
+
+	// TestPublishInvalidData
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+
+		// Case: data that cannot be encoded is not published
+		err := hub.Publish("ORDER-1", "order.status_changed", make(chan int))
+		assert.Error(t, err)
+		assert.Empty(t, receive(hub.Subscribe("", 1)))
+	}(t)
+
+	// TestPublishSlowSubscriber
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+		slow := hub.Subscribe("ORDER-1", 0)
+		other := hub.Subscribe("ORDER-2", 0)
+
+		// Case: a subscriber that does not keep up is dropped and its channel closed
+		for i := 0; i < 65; i++ {
+			err := hub.Publish("ORDER-1", "order.status_changed", i)
+			assert.Nil(t, err)
+		}
+		assert.Len(t, receive(slow), 64)
+		_, ok := <-slow.Events()
+		assert.False(t, ok)
+
+		// Case: subscribers of other topics are kept
+		err := hub.Publish("ORDER-2", "order.status_changed", nil)
+		assert.Nil(t, err)
+		assert.Len(t, receive(other), 1)
+
+		// Case: closing a dropped subscription does nothing
+		assert.NotPanics(t, slow.Close)
+		err = hub.Publish("ORDER-1", "order.status_changed", nil)
+		assert.Nil(t, err)
+	}(t)
+
+	// TestPublishClosedSubscription
+	func(t *testing.T) {
+		hub := pubsub.NewHub(10)
+		subscription := hub.Subscribe("", 0)
+
+		// Case: a closed subscription receives nothing and can be closed again
+		subscription.Close()
+		_, ok := <-subscription.Events()
+		assert.False(t, ok)
+		err := hub.Publish("ORDER-1", "order.status_changed", nil)
+		assert.Nil(t, err)
+		assert.NotPanics(t, subscription.Close)
+	}(t)
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// cursor is the position of the last row of a page in keyset pagination.
+type cursor struct {
+	Sort  string `json:"s,omitempty"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// EncodeCursor returns an opaque cursor from the sort value and ID of the last row of a page.
+func EncodeCursor(value string, id int64) string {
+	raw, _ := json.Marshal(cursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor returns the sort value and ID stored in a cursor made by EncodeCursor.
+func DecodeCursor(encoded string) (string, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var c cursor
+	err = json.Unmarshal(raw, &c)
+	return c.Value, c.ID, err
+}
+
+// EncodeSortCursor returns an opaque cursor like EncodeCursor for a page sorted
+// by sort, so the cursor can not be used with another sort.
+func EncodeSortCursor(sort string, value string, id int64) string {
+	raw, _ := json.Marshal(cursor{Sort: sort, Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeSortCursor returns the sort, sort value and ID stored in a cursor made
+// by EncodeSortCursor.
+func DecodeSortCursor(encoded string) (string, string, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	var c cursor
+	err = json.Unmarshal(raw, &c)
+	return c.Sort, c.Value, c.ID, err
+}
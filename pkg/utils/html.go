@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// allowedTags are the tags kept by SanitizeHTML, with the attributes each of
+// them may carry.
+var allowedTags = map[string][]string{
+	"p":          nil,
+	"br":         nil,
+	"b":          nil,
+	"strong":     nil,
+	"i":          nil,
+	"em":         nil,
+	"u":          nil,
+	"ul":         nil,
+	"ol":         nil,
+	"li":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"blockquote": nil,
+	"a":          {"href"},
+}
+
+// voidTags are the allowed tags without a closing tag.
+var voidTags = map[string]bool{
+	"br": true,
+}
+
+// droppedTags are removed along with their content.
+var droppedTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"template": true,
+}
+
+// allowedSchemes are the link targets kept in href attributes.
+var allowedSchemes = []string{"http://", "https://", "mailto:"}
+
+var (
+	tagPattern       = regexp.MustCompile(`<!--[\s\S]*?-->|<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	attributePattern = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// SanitizeHTML returns rich text with only the formatting tags of allowedTags.
+// Other tags and comments are removed, scripts and styles with their content,
+// text is escaped and tags left open are closed, so the result is safe to render.
+func SanitizeHTML(value string) string {
+	var out strings.Builder
+	open := make([]string, 0)
+	dropping := ""
+	last := 0
+
+	for _, match := range tagPattern.FindAllStringSubmatchIndex(value, -1) {
+		if dropping == "" {
+			out.WriteString(escapeText(value[last:match[0]]))
+		}
+		last = match[1]
+
+		// comment
+		if match[4] < 0 {
+			continue
+		}
+
+		closing := match[3] > match[2]
+		name := strings.ToLower(value[match[4]:match[5]])
+
+		if dropping != "" {
+			if closing && name == dropping {
+				dropping = ""
+			}
+			continue
+		}
+
+		if droppedTags[name] {
+			if !closing {
+				dropping = name
+			}
+			continue
+		}
+
+		attributes, ok := allowedTags[name]
+		if !ok || (closing && voidTags[name]) {
+			continue
+		}
+
+		if closing {
+			// closing a tag closes the tags opened inside it as well
+			for index := len(open) - 1; index >= 0; index-- {
+				if open[index] == name {
+					closeTags(&out, open[index:])
+					open = open[:index]
+					break
+				}
+			}
+			continue
+		}
+
+		out.WriteString("<" + name + sanitizeAttributes(attributes, value[match[6]:match[7]]) + ">")
+		if !voidTags[name] {
+			open = append(open, name)
+		}
+	}
+
+	if dropping == "" {
+		out.WriteString(escapeText(value[last:]))
+	}
+	closeTags(&out, open)
+
+	return strings.TrimSpace(out.String())
+}
+
+// escapeText escapes text once, whether or not it was escaped already.
+func escapeText(text string) string {
+	return html.EscapeString(html.UnescapeString(text))
+}
+
+// closeTags writes the closing tags of the open tags, innermost first.
+func closeTags(out *strings.Builder, open []string) {
+	for index := len(open) - 1; index >= 0; index-- {
+		out.WriteString("</" + open[index] + ">")
+	}
+}
+
+// sanitizeAttributes returns the allowed attributes of a tag, links only keep
+// targets with a scheme of allowedSchemes and do not pass on the page's rank.
+// Like browsers, only the first occurrence of an attribute counts.
+func sanitizeAttributes(allowed []string, raw string) string {
+	var out strings.Builder
+	seen := make(map[string]bool)
+
+	for _, match := range attributePattern.FindAllStringSubmatch(raw, -1) {
+		name := strings.ToLower(match[1])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if !containsAttribute(allowed, name) {
+			continue
+		}
+
+		value := strings.TrimSpace(html.UnescapeString(match[2] + match[3] + match[4]))
+		if name == "href" && !hasAllowedScheme(value) {
+			continue
+		}
+
+		out.WriteString(" " + name + `="` + html.EscapeString(value) + `"`)
+		if name == "href" {
+			out.WriteString(` rel="nofollow noopener"`)
+		}
+	}
+
+	return out.String()
+}
+
+func containsAttribute(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAllowedScheme(value string) bool {
+	value = strings.ToLower(value)
+	for _, scheme := range allowedSchemes {
+		if strings.HasPrefix(value, scheme) {
+			return true
+		}
+	}
+	return false
+}
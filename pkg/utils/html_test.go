@@ -0,0 +1,146 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "allowed formatting is kept",
+			value:    "<p>Water <b>resistant</b> to <em>200m</em></p><ul><li>steel</li></ul>",
+			expected: "<p>Water <b>resistant</b> to <em>200m</em></p><ul><li>steel</li></ul>",
+		},
+		{
+			name:     "tag names are lowercased",
+			value:    "<P>text<BR></P>",
+			expected: "<p>text<br></p>",
+		},
+		{
+			name:     "quoted > inside an attribute",
+			value:    `<a href="https://example.com/?q=a>b">link</a>`,
+			expected: `<a href="https://example.com/?q=a&gt;b" rel="nofollow noopener">link</a>`,
+		},
+		{
+			name:     "single quoted > inside an attribute",
+			value:    `<p title='x>y'>text</p>`,
+			expected: "<p>text</p>",
+		},
+		{
+			name:     "unterminated quote is text",
+			value:    `<p title="x>text`,
+			expected: "&lt;p title=&#34;x&gt;text",
+		},
+		{
+			name:     "script with its content",
+			value:    "before<script>alert(1)</script>after",
+			expected: "beforeafter",
+		},
+		{
+			name:     "unclosed script",
+			value:    "before<script>alert(1)<p>after</p>",
+			expected: "before",
+		},
+		{
+			name:     "mixed case script",
+			value:    "<ScRiPt>alert(1)</sCrIpT>after",
+			expected: "after",
+		},
+		{
+			name:     "style and iframe with their content",
+			value:    "<style>body{display:none}</style><iframe src=\"https://example.com\">x</iframe>text",
+			expected: "text",
+		},
+		{
+			name:     "javascript href",
+			value:    `<a href="javascript:alert(1)">x</a>`,
+			expected: "<a>x</a>",
+		},
+		{
+			name:     "entity encoded javascript href",
+			value:    `<a href="&#106;avascript:alert(1)">x</a>`,
+			expected: "<a>x</a>",
+		},
+		{
+			name:     "hex entity and whitespace in javascript href",
+			value:    `<a href=" &#x4A;AVASCRIPT&colon;alert(1)">x</a>`,
+			expected: "<a>x</a>",
+		},
+		{
+			name:     "data href",
+			value:    `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`,
+			expected: "<a>x</a>",
+		},
+		{
+			name:     "unquoted href",
+			value:    `<a href=https://example.com>x</a>`,
+			expected: `<a href="https://example.com" rel="nofollow noopener">x</a>`,
+		},
+		{
+			name:     "duplicate href keeps the first",
+			value:    `<a href="https://a.example.com" href="https://b.example.com">x</a>`,
+			expected: `<a href="https://a.example.com" rel="nofollow noopener">x</a>`,
+		},
+		{
+			name:     "duplicate href after a refused one",
+			value:    `<a href="javascript:alert(1)" href="https://b.example.com">x</a>`,
+			expected: "<a>x</a>",
+		},
+		{
+			name:     "event handler attributes",
+			value:    `<p onclick="steal()" ONMOUSEOVER='steal()' onload=steal()>text</p>`,
+			expected: "<p>text</p>",
+		},
+		{
+			name:     "attributes that are not allowed",
+			value:    `<p style="color:red" class="x">text</p><img src=x onerror=alert(1)>`,
+			expected: "<p>text</p>",
+		},
+		{
+			name:     "comments",
+			value:    "a<!-- comment -->b<!-- <script>alert(1)</script> -->c",
+			expected: "abc",
+		},
+		{
+			name:     "unbalanced tags are closed",
+			value:    "<p><b>bold<i>both",
+			expected: "<p><b>bold<i>both</i></b></p>",
+		},
+		{
+			name:     "misnested tags",
+			value:    "<b><i>x</b></i>",
+			expected: "<b><i>x</i></b>",
+		},
+		{
+			name:     "closing tags without opening tag",
+			value:    "</p>text</b>",
+			expected: "text",
+		},
+		{
+			name:     "already escaped text is not escaped twice",
+			value:    "&lt;b&gt; &amp;amp; 5 &lt; 6",
+			expected: "&lt;b&gt; &amp;amp; 5 &lt; 6",
+		},
+		{
+			name:     "text is escaped",
+			value:    `5 < 6 & "quoted"`,
+			expected: "5 &lt; 6 &amp; &#34;quoted&#34;",
+		},
+		{
+			name:     "escaped tags stay text",
+			value:    "&lt;script&gt;alert(1)&lt;/script&gt;",
+			expected: "&lt;script&gt;alert(1)&lt;/script&gt;",
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, utils.SanitizeHTML(c.value), c.name)
+	}
+}
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/richardsahvic/jamtangan/domain/model"
 )
@@ -16,3 +19,39 @@ func RequestRequired(fieldName string) (int, *model.BaseResponse) {
 func RequestInvalid(fieldName string) (int, *model.BaseResponse) {
 	return http.StatusBadRequest, &model.BaseResponse{RawMessage: fmt.Sprintf("%s is invalid", fieldName)}
 }
+
+// Unauthorized returns a response for requests without valid credentials.
+func Unauthorized() (int, *model.BaseResponse) {
+	return http.StatusUnauthorized, &model.BaseResponse{RawMessage: "unauthorized"}
+}
+
+// IsAdmin returns true when the request carries the admin token as a bearer
+// token. Admin access is disabled when token is empty.
+func IsAdmin(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, "Bearer ")), []byte(token)) == 1
+}
+
+// ETag returns the entity tag of a resource version.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseETag returns the resource version of an entity tag, weak tags are
+// accepted as well.
+func ParseETag(tag string) (int64, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	value, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -13,3 +13,10 @@ func GenerateOrderID() string {
 	cleanUUID := strings.Replace(newUUID.String(), "-", "", -1)
 	return fmt.Sprintf("ORDER-%s", cleanUUID)
 }
+
+// GenerateCartID returns a generated cart ID with prefix "CART-<UUID>".
+func GenerateCartID() string {
+	newUUID := uuid.New()
+	cleanUUID := strings.Replace(newUUID.String(), "-", "", -1)
+	return fmt.Sprintf("CART-%s", cleanUUID)
+}
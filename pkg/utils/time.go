@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// TimePtr returns pointer of a nullable time, or nil when it is not set.
+func TimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// ParseDurations parses durations such as "1h" or "90m", in the given order.
+func ParseDurations(values []string) ([]time.Duration, error) {
+	durations := make([]time.Duration, len(values))
+	for index, value := range values {
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		durations[index] = duration
+	}
+	return durations, nil
+}
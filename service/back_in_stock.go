@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// BackInStockService manage logical syntax for back-in-stock subscriptions.
+type BackInStockService interface {
+	Subscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse)
+	Unsubscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse)
+	List(ctx context.Context, customerID string) (int, *model.BaseResponse)
+}
+
+type backInStockServiceImpl struct {
+	backInStockRepo repository.BackInStockRepository
+	productRepo     repository.ProductRepository
+}
+
+// NewBackInStockService returns new instance of backInStockServiceImpl.
+func NewBackInStockService() *backInStockServiceImpl {
+	return &backInStockServiceImpl{}
+}
+
+// SetBackInStockRepo injects back-in-stock's repo for backInStockServiceImpl.
+func (s *backInStockServiceImpl) SetBackInStockRepo(repo repository.BackInStockRepository) *backInStockServiceImpl {
+	s.backInStockRepo = repo
+	return s
+}
+
+// SetProductRepo injects product's repo for backInStockServiceImpl.
+func (s *backInStockServiceImpl) SetProductRepo(repo repository.ProductRepository) *backInStockServiceImpl {
+	s.productRepo = repo
+	return s
+}
+
+// Validate validates if all dependency for backInStockServiceImpl is complete.
+func (s *backInStockServiceImpl) Validate() *backInStockServiceImpl {
+	if s.backInStockRepo == nil {
+		log.Panic("Back-in-stock service need back-in-stock repository")
+	}
+	if s.productRepo == nil {
+		log.Panic("Back-in-stock service need product repository")
+	}
+	return s
+}
+
+// Subscribe subscribes a customer to a sold-out SKU, subscribing again returns
+// the pending subscription.
+func (s *backInStockServiceImpl) Subscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Subscribe")
+
+	product, err := s.productRepo.GetBySKU(request.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get product by SKU, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return utils.RequestInvalid("sku")
+	}
+
+	if product.Stock > 0 {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: fmt.Sprintf("sku %s is in stock", product.SKU)}
+	}
+
+	subscription, err := s.backInStockRepo.GetPending(request.CustomerID, product.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get back-in-stock subscription, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if subscription == nil {
+		subscription = &model.BackInStockSubscription{
+			CustomerID: request.CustomerID,
+			ProductID:  product.ID,
+			SKU:        product.SKU,
+			Status:     model.BackInStockPending,
+		}
+
+		err = s.backInStockRepo.Create(subscription)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to create back-in-stock subscription, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: backInStockResponse(subscription)}
+}
+
+// Unsubscribe cancels the pending subscription of a customer to a SKU.
+func (s *backInStockServiceImpl) Unsubscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Unsubscribe")
+
+	subscription, err := s.backInStockRepo.GetPending(request.CustomerID, request.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get back-in-stock subscription, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if subscription == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	err = s.backInStockRepo.Cancel(subscription.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to cancel back-in-stock subscription, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{}
+}
+
+// List returns the subscriptions of a customer, newest first.
+func (s *backInStockServiceImpl) List(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("customer_id")
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	subscriptions, err := s.backInStockRepo.ListByCustomerID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list back-in-stock subscriptions, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := make([]model.BackInStockResponse, len(subscriptions))
+	for index, subscription := range subscriptions {
+		resp[index] = backInStockResponse(subscription)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// backInStockResponse returns the response of a back-in-stock subscription.
+func backInStockResponse(subscription *model.BackInStockSubscription) model.BackInStockResponse {
+	return model.BackInStockResponse{
+		ID:         subscription.ID,
+		CustomerID: subscription.CustomerID,
+		SKU:        subscription.SKU,
+		Status:     subscription.Status,
+		CreatedAt:  subscription.CreatedAt,
+		NotifiedAt: utils.TimePtr(subscription.NotifiedAt),
+	}
+}
+
+// writeBackInStockEvent writes product.back_in_stock when the stock of a product
+// goes from zero to positive.
+func writeBackInStockEvent(tx *sqlx.Tx, repo repository.OutboxRepository, product *model.Product, stock int64) error {
+	if product.Stock > 0 || stock <= 0 {
+		return nil
+	}
+
+	return writeOutboxEvent(tx, repo, model.OutboxEventProductBackInStock, product.SKU, model.ProductEvent{
+		ID:      product.ID,
+		BrandID: product.BrandID,
+		SKU:     product.SKU,
+		Stock:   stock,
+		Price:   product.Price,
+	})
+}
+
+// BackInStockChannel notifies the subscribers of a SKU that is available again.
+// Subscribers are notified oldest first, and no more of them than the units in
+// stock times perUnit, the others wait for the next restock. Every subscriber
+// gets a notification.back_in_stock event, delivered by the customer channels.
+type BackInStockChannel struct {
+	backInStockRepo repository.BackInStockRepository
+	productRepo     repository.ProductRepository
+	outboxRepo      repository.OutboxRepository
+	txRepo          repository.TxRepository
+	perUnit         int64
+}
+
+// NewBackInStockChannel returns new instance of BackInStockChannel.
+func NewBackInStockChannel() *BackInStockChannel {
+	return &BackInStockChannel{}
+}
+
+// SetBackInStockRepo injects back-in-stock's repo for BackInStockChannel.
+func (c *BackInStockChannel) SetBackInStockRepo(repo repository.BackInStockRepository) *BackInStockChannel {
+	c.backInStockRepo = repo
+	return c
+}
+
+// SetProductRepo injects product's repo for BackInStockChannel.
+func (c *BackInStockChannel) SetProductRepo(repo repository.ProductRepository) *BackInStockChannel {
+	c.productRepo = repo
+	return c
+}
+
+// SetOutboxRepo injects outbox's repo for BackInStockChannel.
+func (c *BackInStockChannel) SetOutboxRepo(repo repository.OutboxRepository) *BackInStockChannel {
+	c.outboxRepo = repo
+	return c
+}
+
+// SetTxRepo injects transaction runner for BackInStockChannel.
+func (c *BackInStockChannel) SetTxRepo(repo repository.TxRepository) *BackInStockChannel {
+	c.txRepo = repo
+	return c
+}
+
+// SetPerUnit sets how many subscribers are notified for every unit in stock.
+func (c *BackInStockChannel) SetPerUnit(perUnit int64) *BackInStockChannel {
+	c.perUnit = perUnit
+	return c
+}
+
+// Validate validates if all dependency for BackInStockChannel is complete.
+func (c *BackInStockChannel) Validate() *BackInStockChannel {
+	if c.backInStockRepo == nil {
+		log.Panic("Back-in-stock channel need back-in-stock repository")
+	}
+	if c.productRepo == nil {
+		log.Panic("Back-in-stock channel need product repository")
+	}
+	if c.outboxRepo == nil {
+		log.Panic("Back-in-stock channel need outbox repository")
+	}
+	if c.txRepo == nil {
+		log.Panic("Back-in-stock channel need tx repository")
+	}
+	if c.perUnit <= 0 {
+		log.Panic("Back-in-stock channel need subscribers per unit")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *BackInStockChannel) Name() string {
+	return "back_in_stock"
+}
+
+// Send notifies the subscribers of the SKU of a product.back_in_stock event.
+func (c *BackInStockChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	if event.EventType != model.OutboxEventProductBackInStock {
+		return nil
+	}
+
+	var payload model.ProductEvent
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return nil
+	}
+
+	// the stock may have been sold again since the event was written
+	product, err := c.productRepo.GetBySKU(payload.SKU)
+	if err != nil {
+		return err
+	}
+
+	if product == nil || product.Stock <= 0 {
+		return nil
+	}
+
+	return c.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		subscriptions, err := c.backInStockRepo.ClaimPendingTx(tx, product.SKU, product.Stock*c.perUnit)
+		if err != nil || len(subscriptions) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(subscriptions))
+		for index, subscription := range subscriptions {
+			ids[index] = subscription.ID
+
+			err = writeOutboxEvent(tx, c.outboxRepo, model.OutboxEventBackInStockAlert, product.SKU, model.BackInStockAlertEvent{
+				SubscriptionID: subscription.ID,
+				CustomerID:     subscription.CustomerID,
+				ProductID:      product.ID,
+				SKU:            product.SKU,
+				Stock:          product.Stock,
+				Price:          product.Price,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return c.backInStockRepo.MarkNotifiedTx(tx, ids)
+	})
+}
@@ -0,0 +1,246 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubscribeBackInStock(t *testing.T) {
+	prepare()
+
+	// TestSubscribeBackInStockInvalidRequest
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		backInStockService := service.NewBackInStockService().
+			SetProductRepo(mockProductRepo)
+
+		// Case: missing customer
+		httpCode, _ := backInStockService.Subscribe(context.Background(), model.BackInStockRequest{SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: unknown SKU
+		mockProductRepo.On("GetBySKU", "sku-unknown").Return(nil, nil)
+		httpCode, _ = backInStockService.Subscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: "sku-unknown"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestSubscribeBackInStockInStock
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockBackInStockRepo := new(repoMock.BackInStockRepository)
+		backInStockService := service.NewBackInStockService().
+			SetBackInStockRepo(mockBackInStockRepo).
+			SetProductRepo(mockProductRepo)
+
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Stock: 3}, nil)
+		httpCode, resp := backInStockService.Subscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockBackInStockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	}(t)
+
+	// TestSubscribeBackInStockSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockBackInStockRepo := new(repoMock.BackInStockRepository)
+		backInStockService := service.NewBackInStockService().
+			SetBackInStockRepo(mockBackInStockRepo).
+			SetProductRepo(mockProductRepo)
+
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test"}, nil)
+
+		// Case: new subscription
+		mockBackInStockRepo.On("GetPending", int64(7), "sku-test").Return(nil, nil).Once()
+		mockBackInStockRepo.On("Create", mock.MatchedBy(func(subscription *model.BackInStockSubscription) bool {
+			return subscription.CustomerID == 7 && subscription.ProductID == 1 && subscription.Status == model.BackInStockPending
+		})).Return(nil)
+		httpCode, resp := backInStockService.Subscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: " sku-test "})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.BackInStockResponse).SKU, "sku-test")
+
+		// Case: subscribing again returns the pending subscription
+		mockBackInStockRepo.On("GetPending", int64(7), "sku-test").
+			Return(&model.BackInStockSubscription{ID: 5, CustomerID: 7, SKU: "sku-test", Status: model.BackInStockPending}, nil)
+		httpCode, resp = backInStockService.Subscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.BackInStockResponse).ID, int64(5))
+		mockBackInStockRepo.AssertNumberOfCalls(t, "Create", 1)
+	}(t)
+}
+
+func TestUnsubscribeBackInStock(t *testing.T) {
+	prepare()
+
+	mockBackInStockRepo := new(repoMock.BackInStockRepository)
+	backInStockService := service.NewBackInStockService().
+		SetBackInStockRepo(mockBackInStockRepo)
+
+	// Case: no pending subscription
+	mockBackInStockRepo.On("GetPending", int64(7), "sku-none").Return(nil, nil)
+	httpCode, _ := backInStockService.Unsubscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: "sku-none"})
+	assert.Equal(t, httpCode, http.StatusNotFound)
+
+	// Case: pending subscription is cancelled
+	mockBackInStockRepo.On("GetPending", int64(7), "sku-test").Return(&model.BackInStockSubscription{ID: 5}, nil)
+	mockBackInStockRepo.On("Cancel", int64(5)).Return(nil)
+	httpCode, _ = backInStockService.Unsubscribe(context.Background(), model.BackInStockRequest{CustomerID: 7, SKU: "sku-test"})
+	assert.Equal(t, httpCode, http.StatusOK)
+	mockBackInStockRepo.AssertNumberOfCalls(t, "Cancel", 1)
+}
+
+func TestBackInStockChannel(t *testing.T) {
+	prepare()
+
+	payload, _ := json.Marshal(model.ProductEvent{ID: 1, SKU: "sku-test", Stock: 2})
+	event := &model.OutboxEvent{
+		EventType:   model.OutboxEventProductBackInStock,
+		AggregateID: "sku-test",
+		Payload:     payload,
+	}
+
+	// TestBackInStockChannelSoldAgain
+	func(t *testing.T) {
+		mockBackInStockRepo := new(repoMock.BackInStockRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		channel := service.NewBackInStockChannel().
+			SetBackInStockRepo(mockBackInStockRepo).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(new(repoMock.OutboxRepository)).
+			SetTxRepo(mockTxRepo).
+			SetPerUnit(2).
+			Validate()
+
+		// Case: other events are ignored
+		err := channel.Send(context.Background(), &model.OutboxEvent{EventType: model.OutboxEventOrderCreated})
+		assert.Nil(t, err)
+
+		// Case: the stock is back to zero, nobody is notified
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test"}, nil)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		mockTxRepo.AssertNotCalled(t, "WithTx", mock.Anything)
+	}(t)
+
+	// TestBackInStockChannelFailed
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		channel := service.NewBackInStockChannel().
+			SetBackInStockRepo(new(repoMock.BackInStockRepository)).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(new(repoMock.OutboxRepository)).
+			SetTxRepo(new(repoMock.TxRepository)).
+			SetPerUnit(1)
+
+		mockProductRepo.On("GetBySKU", "sku-test").Return(nil, errors.New("error"))
+		err := channel.Send(context.Background(), event)
+		assert.NotNil(t, err)
+	}(t)
+
+	// TestBackInStockChannelSuccess
+	func(t *testing.T) {
+		mockBackInStockRepo := new(repoMock.BackInStockRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		channel := service.NewBackInStockChannel().
+			SetBackInStockRepo(mockBackInStockRepo).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetPerUnit(2)
+
+		// Case: 3 units in stock notify at most 6 subscribers, oldest first
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Stock: 3}, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockBackInStockRepo.On("ClaimPendingTx", mock.Anything, "sku-test", int64(6)).
+			Return([]*model.BackInStockSubscription{
+				{ID: 10, CustomerID: 7, SKU: "sku-test"},
+				{ID: 11, CustomerID: 8, SKU: "sku-test"},
+			}, nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			var alert model.BackInStockAlertEvent
+			json.Unmarshal(event.Payload, &alert)
+			return event.EventType == model.OutboxEventBackInStockAlert && alert.CustomerID > 0 && alert.Stock == 3
+		})).Return(nil)
+		mockBackInStockRepo.On("MarkNotifiedTx", mock.Anything, []int64{10, 11}).Return(nil)
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 2)
+		mockBackInStockRepo.AssertNumberOfCalls(t, "MarkNotifiedTx", 1)
+	}(t)
+}
+
+func TestRestockProduct(t *testing.T) {
+	prepare()
+
+	// TestRestockProductInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		// Case: missing SKU
+		httpCode, _ := productService.Restock(context.Background(), model.RestockProductRequest{Quantity: 1})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: no quantity
+		httpCode, _ = productService.Restock(context.Background(), model.RestockProductRequest{SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestRestockProductNotFound
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-none"}).Return([]*model.Product{}, nil)
+		httpCode, _ := productService.Restock(context.Background(), model.RestockProductRequest{SKU: "sku-none", Quantity: 1})
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestRestockProductSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, mock.Anything, int64(5)).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductBackInStock && event.AggregateID == "sku-empty"
+		})).Return(nil)
+
+		// Case: a sold-out product is back in stock
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-empty"}).
+			Return([]*model.Product{{ID: 1, SKU: "sku-empty"}}, nil)
+		httpCode, resp := productService.Restock(context.Background(), model.RestockProductRequest{SKU: "sku-empty", Quantity: 5})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.RestockProductResponse).Stock, int64(5))
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+
+		// Case: a product still in stock writes no event
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).
+			Return([]*model.Product{{ID: 2, SKU: "sku-test", Stock: 2}}, nil)
+		httpCode, resp = productService.Restock(context.Background(), model.RestockProductRequest{SKU: "sku-test", Quantity: 5})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.RestockProductResponse).Stock, int64(7))
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+}
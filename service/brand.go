@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/domain/repository"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
@@ -16,10 +21,25 @@ import (
 // BrandService manage logical syntax for brand.
 type BrandService interface {
 	Create(ctx context.Context, request model.CreateBrandRequest) (int, *model.BaseResponse)
+	GetByID(ctx context.Context, brandID string) (int, *model.BaseResponse)
+	List(ctx context.Context, request model.ListBrandRequest) (int, *model.BaseResponse)
+	Update(ctx context.Context, request model.UpdateBrandRequest) (int, *model.BaseResponse)
+	Delete(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse)
+	Restore(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse)
 }
 
+// List of brand limits.
+const (
+	maxBrandNameLength    = 100
+	defaultBrandListLimit = 20
+	maxBrandListLimit     = 100
+)
+
 type brandServiceImpl struct {
-	brandRepo repository.BrandRepository
+	brandRepo   repository.BrandRepository
+	productRepo repository.ProductRepository
+	outboxRepo  repository.OutboxRepository
+	txRepo      repository.TxRepository
 }
 
 // NewBrandService returns new instance of brandServiceImpl.
@@ -33,25 +53,69 @@ func (s *brandServiceImpl) SetBrandRepo(repo repository.BrandRepository) *brandS
 	return s
 }
 
+// SetProductRepo injects product's repo for brandServiceImpl.
+func (s *brandServiceImpl) SetProductRepo(repo repository.ProductRepository) *brandServiceImpl {
+	s.productRepo = repo
+	return s
+}
+
+// SetOutboxRepo injects outbox's repo for brandServiceImpl.
+func (s *brandServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *brandServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetTxRepo injects tx's repo for brandServiceImpl.
+func (s *brandServiceImpl) SetTxRepo(repo repository.TxRepository) *brandServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
 // Validate validates if all dependency for brandServiceImpl is complete.
 func (s *brandServiceImpl) Validate() *brandServiceImpl {
 	if s.brandRepo == nil {
 		log.Panic("Brand service need brand repository")
 	}
+	if s.productRepo == nil {
+		log.Panic("Brand service need product repository")
+	}
+	if s.outboxRepo == nil {
+		log.Panic("Brand service need outbox repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Brand service need tx repository")
+	}
 	return s
 }
 
 // Create creates a new brand and store it into the database.
 func (s *brandServiceImpl) Create(ctx context.Context, request model.CreateBrandRequest) (int, *model.BaseResponse) {
 	// validate request
-	if strings.TrimSpace(request.Name) == "" {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
 		return utils.RequestRequired("name")
+	} else if len(name) > maxBrandNameLength {
+		return utils.RequestInvalid("name")
 	}
 
 	log := logger.GetLoggerContext(ctx, "service", "Create")
 
-	id, err := s.brandRepo.Create(request.Name)
-	if err != nil {
+	var id int64
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		id, err = s.brandRepo.CreateTx(tx, name)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventBrandCreated, strconv.FormatInt(id, 10), model.BrandEvent{
+			ID:   id,
+			Name: name,
+		})
+	})
+	if errors.Is(err, repository.ErrDuplicateBrandName) {
+		return duplicateBrandName(name)
+	} else if err != nil {
 		log.Error(fmt.Sprintf("failed to create brand, err: %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
 	}
@@ -62,3 +126,288 @@ func (s *brandServiceImpl) Create(ctx context.Context, request model.CreateBrand
 
 	return http.StatusOK, &model.BaseResponse{ResultData: resp}
 }
+
+// GetByID returns a brand with the count and total stock of its active products.
+func (s *brandServiceImpl) GetByID(ctx context.Context, brandID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(brandID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(brandID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "GetByID")
+
+	brand, err := s.brandRepo.GetSummaryByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get brand by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if brand == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: brandResponse(brand)}
+}
+
+// List returns brands whose name contains the searched name, ordered by name a
+// page at a time. The next page is requested with the next_cursor of the previous page.
+func (s *brandServiceImpl) List(ctx context.Context, request model.ListBrandRequest) (int, *model.BaseResponse) {
+	filter := model.BrandFilter{
+		Name:  strings.TrimSpace(request.Name),
+		Limit: defaultBrandListLimit,
+	}
+
+	// validate request
+	if request.Limit != "" {
+		limit, err := strconv.Atoi(request.Limit)
+		if err != nil || limit <= 0 || limit > maxBrandListLimit {
+			return utils.RequestInvalid("limit")
+		}
+		filter.Limit = limit
+	}
+	if request.Cursor != "" {
+		name, id, err := utils.DecodeCursor(request.Cursor)
+		if err != nil || id <= 0 {
+			return utils.RequestInvalid("cursor")
+		}
+		filter.AfterName = name
+		filter.AfterID = id
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	// one more row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	brands, err := s.brandRepo.List(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list brands, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListBrandResponse{
+		Brands: make([]model.GetBrandResponse, 0, len(brands)),
+	}
+
+	if len(brands) > limit {
+		brands = brands[:limit]
+
+		last := brands[limit-1]
+		resp.NextCursor = utils.EncodeCursor(last.Name, last.ID)
+	}
+
+	for _, brand := range brands {
+		resp.Brands = append(resp.Brands, brandResponse(brand))
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Update renames a brand, the name must not be used by another brand.
+func (s *brandServiceImpl) Update(ctx context.Context, request model.UpdateBrandRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.ID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(request.ID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return utils.RequestRequired("name")
+	} else if len(name) > maxBrandNameLength {
+		return utils.RequestInvalid("name")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Update")
+
+	var brand *model.Brand
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		brand, err = s.brandRepo.GetByIDForUpdate(tx, id)
+		if err != nil || brand == nil || brand.Name == name {
+			return err
+		}
+
+		err = s.brandRepo.UpdateTx(tx, id, name)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventBrandUpdated, request.ID, model.BrandEvent{
+			ID:   id,
+			Name: name,
+		})
+	})
+	if errors.Is(err, repository.ErrDuplicateBrandName) {
+		return duplicateBrandName(name)
+	} else if err != nil {
+		log.Error(fmt.Sprintf("failed to update brand, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if brand == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return s.GetByID(ctx, request.ID)
+}
+
+// Delete soft-deletes a brand. A brand that still has products is only deleted
+// with cascade, which soft-deletes the products at the same time.
+func (s *brandServiceImpl) Delete(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.ID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(request.ID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Delete")
+
+	var brand *model.Brand
+	var count int64
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		brand, err = s.brandRepo.GetByIDForUpdate(tx, id)
+		if err != nil || brand == nil {
+			return err
+		}
+
+		count, err = s.productRepo.CountByBrandIDTx(tx, id)
+		if err != nil || (count > 0 && !request.Cascade) {
+			return err
+		}
+
+		// products deleted with the brand share its deletion time
+		at := time.Now().UTC().Truncate(time.Second)
+		err = s.brandRepo.DeleteTx(tx, id, at)
+		if err != nil {
+			return err
+		}
+		brand.DeletedAt = sql.NullTime{Time: at, Valid: true}
+
+		if count > 0 {
+			count, err = s.productRepo.DeleteByBrandIDTx(tx, id, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventBrandDeleted, request.ID, model.BrandEvent{
+			ID:           brand.ID,
+			Name:         brand.Name,
+			ProductCount: count,
+		})
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete brand, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if brand == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	resp := deleteBrandResponse(brand, count)
+	if !brand.DeletedAt.Valid {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "brand has products, delete them or cascade", ResultData: resp}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Restore brings back a soft-deleted brand, with cascade also the products that
+// were deleted along with it. Restoring a brand that is not deleted does nothing.
+func (s *brandServiceImpl) Restore(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.ID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(request.ID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Restore")
+
+	var brand *model.Brand
+	var count int64
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		brand, err = s.brandRepo.GetByIDForUpdate(tx, id, repository.IncludeDeleted)
+		if err != nil || brand == nil || !brand.DeletedAt.Valid {
+			return err
+		}
+
+		err = s.brandRepo.RestoreTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if request.Cascade {
+			count, err = s.productRepo.RestoreByBrandIDTx(tx, id, brand.DeletedAt.Time)
+			if err != nil {
+				return err
+			}
+		}
+		brand.DeletedAt = sql.NullTime{}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventBrandRestored, request.ID, model.BrandEvent{
+			ID:           brand.ID,
+			Name:         brand.Name,
+			ProductCount: count,
+		})
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to restore brand, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if brand == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: deleteBrandResponse(brand, count)}
+}
+
+// brandResponse returns the response of a brand.
+func brandResponse(brand *model.BrandSummary) model.GetBrandResponse {
+	return model.GetBrandResponse{
+		ID:           brand.ID,
+		Name:         brand.Name,
+		ProductCount: brand.ProductCount,
+		TotalStock:   brand.TotalStock,
+		CreatedAt:    brand.CreatedAt,
+		UpdatedAt:    utils.TimePtr(brand.UpdatedAt),
+	}
+}
+
+// duplicateBrandName returns the response of saving a brand with the name of
+// another brand.
+func duplicateBrandName(name string) (int, *model.BaseResponse) {
+	return http.StatusConflict, &model.BaseResponse{RawMessage: fmt.Sprintf("brand %q already exists", name)}
+}
+
+// deleteBrandResponse returns the response of deleting or restoring a brand.
+func deleteBrandResponse(brand *model.Brand, productCount int64) model.DeleteBrandResponse {
+	return model.DeleteBrandResponse{
+		ID:           brand.ID,
+		Name:         brand.Name,
+		DeletedAt:    utils.TimePtr(brand.DeletedAt),
+		ProductCount: productCount,
+	}
+}
@@ -2,13 +2,18 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
 	"github.com/richardsahvic/jamtangan/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	// serviceMock "github.com/richardsahvic/jamtangan/service/mocks"
 	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
@@ -33,32 +38,347 @@ func TestCreateBrand(t *testing.T) {
 	// TestCreateBrandInternalError
 	func(t *testing.T) {
 		mockBrandRepo := new(repoMock.BrandRepository)
-		brandService := service.NewBrandService().SetBrandRepo(mockBrandRepo)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
 
 		// Case: internal error
 		req := model.CreateBrandRequest{
 			Name: "jam",
 		}
-		mockBrandRepo.On("Create", req.Name).Return(int64(0), errors.New("error"))
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockBrandRepo.On("CreateTx", mock.Anything, req.Name).Return(int64(0), errors.New("error"))
 		httpCode, resp := brandService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusInternalServerError)
 		assert.NotNil(t, resp.RawMessage, "Response raw message should not be nil")
-		mockBrandRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockBrandRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+
+	// TestCreateBrandDuplicateName
+	func(t *testing.T) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: the name of another brand is refused
+		req := model.CreateBrandRequest{
+			Name: " jam ",
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockBrandRepo.On("CreateTx", mock.Anything, "jam").Return(int64(0), repository.ErrDuplicateBrandName)
+		httpCode, resp := brandService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, `brand "jam" already exists`)
+		mockOutboxRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
 	}(t)
 
 	// TestCreateBrandSuccess
 	func(t *testing.T) {
 		mockBrandRepo := new(repoMock.BrandRepository)
-		brandService := service.NewBrandService().SetBrandRepo(mockBrandRepo)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
 
 		// Case: Success
 		req := model.CreateBrandRequest{
 			Name: "jam",
 		}
-		mockBrandRepo.On("Create", req.Name).Return(int64(1), nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockBrandRepo.On("CreateTx", mock.Anything, req.Name).Return(int64(1), nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventBrandCreated && event.AggregateID == "1"
+		})).Return(nil)
 		httpCode, resp := brandService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusOK)
 		assert.NotNil(t, resp.ResultData, "Result should not be nil")
-		mockBrandRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockBrandRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+}
+
+func TestDeleteBrand(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.BrandRepository, *repoMock.ProductRepository, *repoMock.OutboxRepository, service.BrandService) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			Validate()
+		return mockBrandRepo, mockProductRepo, mockOutboxRepo, brandService
+	}
+
+	// TestDeleteBrandInvalidRequest
+	func(t *testing.T) {
+		brandService := service.NewBrandService()
+
+		// Case: empty ID
+		httpCode, _ := brandService.Delete(context.Background(), model.DeleteBrandRequest{})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid ID
+		httpCode, _ = brandService.Delete(context.Background(), model.DeleteBrandRequest{ID: "abc"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestDeleteBrandNotFound
+	func(t *testing.T) {
+		mockBrandRepo, _, _, brandService := newService()
+
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(nil, nil)
+		httpCode, _ := brandService.Delete(context.Background(), model.DeleteBrandRequest{ID: "1"})
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestDeleteBrandWithProducts
+	func(t *testing.T) {
+		mockBrandRepo, mockProductRepo, mockOutboxRepo, brandService := newService()
+
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Brand{ID: 1, Name: "jam"}, nil)
+		mockProductRepo.On("CountByBrandIDTx", mock.Anything, int64(1)).Return(int64(2), nil)
+		mockBrandRepo.On("DeleteTx", mock.Anything, int64(1), mock.Anything).Return(nil)
+		mockProductRepo.On("DeleteByBrandIDTx", mock.Anything, int64(1), mock.Anything).Return(int64(2), nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: a brand with products is not deleted without cascade
+		httpCode, resp := brandService.Delete(context.Background(), model.DeleteBrandRequest{ID: "1"})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.ResultData.(model.DeleteBrandResponse).ProductCount, int64(2))
+		mockBrandRepo.AssertNotCalled(t, "DeleteTx", mock.Anything, mock.Anything, mock.Anything)
+
+		// Case: cascade deletes the products at the same time as the brand
+		httpCode, resp = brandService.Delete(context.Background(), model.DeleteBrandRequest{ID: "1", Cascade: true})
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.DeleteBrandResponse)
+		assert.NotNil(t, result.DeletedAt)
+		assert.Equal(t, result.ProductCount, int64(2))
+		mockProductRepo.AssertCalled(t, "DeleteByBrandIDTx", mock.Anything, int64(1), *result.DeletedAt)
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventBrandDeleted && event.AggregateID == "1"
+		}))
+	}(t)
+
+	// TestDeleteBrandWithoutProducts
+	func(t *testing.T) {
+		mockBrandRepo, mockProductRepo, mockOutboxRepo, brandService := newService()
+
+		// Case: a brand without products is deleted
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Brand{ID: 1, Name: "jam"}, nil)
+		mockProductRepo.On("CountByBrandIDTx", mock.Anything, int64(1)).Return(int64(0), nil)
+		mockBrandRepo.On("DeleteTx", mock.Anything, int64(1), mock.Anything).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		httpCode, _ := brandService.Delete(context.Background(), model.DeleteBrandRequest{ID: "1"})
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockProductRepo.AssertNotCalled(t, "DeleteByBrandIDTx", mock.Anything, mock.Anything, mock.Anything)
+	}(t)
+
+	// TestRestoreBrand
+	func(t *testing.T) {
+		mockBrandRepo, mockProductRepo, mockOutboxRepo, brandService := newService()
+		deletedAt := time.Date(2022, 2, 18, 9, 0, 0, 0, time.UTC)
+
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1), repository.IncludeDeleted).Return(&model.Brand{
+			ID:        1,
+			Name:      "jam",
+			DeletedAt: sql.NullTime{Time: deletedAt, Valid: true},
+		}, nil)
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(2), repository.IncludeDeleted).Return(&model.Brand{ID: 2, Name: "watch"}, nil)
+		mockBrandRepo.On("RestoreTx", mock.Anything, int64(1)).Return(nil)
+		mockProductRepo.On("RestoreByBrandIDTx", mock.Anything, int64(1), deletedAt).Return(int64(2), nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: cascade restores the products deleted along with the brand
+		httpCode, resp := brandService.Restore(context.Background(), model.DeleteBrandRequest{ID: "1", Cascade: true})
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.DeleteBrandResponse)
+		assert.Nil(t, result.DeletedAt)
+		assert.Equal(t, result.ProductCount, int64(2))
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventBrandRestored && event.AggregateID == "1"
+		}))
+
+		// Case: restoring a brand that is not deleted does nothing
+		httpCode, _ = brandService.Restore(context.Background(), model.DeleteBrandRequest{ID: "2"})
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockBrandRepo.AssertNumberOfCalls(t, "RestoreTx", 1)
+	}(t)
+}
+
+func TestGetBrand(t *testing.T) {
+	prepare()
+
+	// TestGetBrandInvalidRequest
+	func(t *testing.T) {
+		brandService := service.NewBrandService()
+
+		// Case: empty ID
+		httpCode, _ := brandService.GetByID(context.Background(), "")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid ID
+		httpCode, _ = brandService.GetByID(context.Background(), "abc")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestGetBrandSuccess
+	func(t *testing.T) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo)
+
+		// Case: brand with its active products
+		mockBrandRepo.On("GetSummaryByID", int64(1)).Return(&model.BrandSummary{ID: 1, Name: "jam", ProductCount: 2, TotalStock: 13}, nil)
+		httpCode, resp := brandService.GetByID(context.Background(), "1")
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.GetBrandResponse)
+		assert.Equal(t, result.ProductCount, int64(2))
+		assert.Equal(t, result.TotalStock, int64(13))
+
+		// Case: unknown or deleted brand
+		mockBrandRepo.On("GetSummaryByID", int64(2)).Return(nil, nil)
+		httpCode, _ = brandService.GetByID(context.Background(), "2")
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+}
+
+func TestListBrand(t *testing.T) {
+	prepare()
+
+	// TestListBrandInvalidRequest
+	func(t *testing.T) {
+		brandService := service.NewBrandService()
+
+		// Case: invalid limit
+		httpCode, _ := brandService.List(context.Background(), model.ListBrandRequest{Limit: "0"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid cursor
+		httpCode, _ = brandService.List(context.Background(), model.ListBrandRequest{Cursor: "???"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestListBrandErrorDatabase
+	func(t *testing.T) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo)
+
+		mockBrandRepo.On("List", mock.Anything).Return(nil, errors.New("error"))
+		httpCode, resp := brandService.List(context.Background(), model.ListBrandRequest{})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestListBrandSuccess
+	func(t *testing.T) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo)
+
+		// Case: a full page has a cursor to the next one
+		mockBrandRepo.On("List", model.BrandFilter{Name: "ja", Limit: 3}).Return([]*model.BrandSummary{
+			{ID: 1, Name: "jam"},
+			{ID: 3, Name: "jam tangan"},
+			{ID: 2, Name: "jams"},
+		}, nil)
+		httpCode, resp := brandService.List(context.Background(), model.ListBrandRequest{Name: " ja ", Limit: "2"})
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListBrandResponse)
+		assert.Len(t, result.Brands, 2)
+		assert.NotEmpty(t, result.NextCursor)
+
+		// Case: the next page starts after the last brand of the previous one
+		mockBrandRepo.On("List", model.BrandFilter{Name: "ja", AfterName: "jam tangan", AfterID: 3, Limit: 3}).Return([]*model.BrandSummary{
+			{ID: 2, Name: "jams"},
+		}, nil)
+		httpCode, resp = brandService.List(context.Background(), model.ListBrandRequest{Name: "ja", Limit: "2", Cursor: result.NextCursor})
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result = resp.ResultData.(model.ListBrandResponse)
+		assert.Len(t, result.Brands, 1)
+		assert.Empty(t, result.NextCursor)
+	}(t)
+}
+
+func TestUpdateBrand(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.BrandRepository, *repoMock.OutboxRepository, service.BrandService) {
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		brandService := service.NewBrandService().
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+		return mockBrandRepo, mockOutboxRepo, brandService
+	}
+
+	// TestUpdateBrandInvalidRequest
+	func(t *testing.T) {
+		brandService := service.NewBrandService()
+
+		// Case: empty name
+		httpCode, _ := brandService.Update(context.Background(), model.UpdateBrandRequest{ID: "1", Name: " "})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: name too long
+		httpCode, _ = brandService.Update(context.Background(), model.UpdateBrandRequest{ID: "1", Name: strings.Repeat("a", 101)})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestUpdateBrandDuplicateName
+	func(t *testing.T) {
+		mockBrandRepo, mockOutboxRepo, brandService := newService()
+
+		// Case: the name of another brand is refused
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Brand{ID: 1, Name: "jam"}, nil)
+		mockBrandRepo.On("UpdateTx", mock.Anything, int64(1), "watch").Return(repository.ErrDuplicateBrandName)
+		httpCode, _ := brandService.Update(context.Background(), model.UpdateBrandRequest{ID: "1", Name: "watch"})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		mockOutboxRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything)
+	}(t)
+
+	// TestUpdateBrandSuccess
+	func(t *testing.T) {
+		mockBrandRepo, mockOutboxRepo, brandService := newService()
+
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Brand{ID: 1, Name: "jam"}, nil)
+		mockBrandRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(nil, nil)
+		mockBrandRepo.On("UpdateTx", mock.Anything, int64(1), "jam tangan").Return(nil)
+		mockBrandRepo.On("GetSummaryByID", int64(1)).Return(&model.BrandSummary{ID: 1, Name: "jam tangan"}, nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: brand is renamed
+		httpCode, resp := brandService.Update(context.Background(), model.UpdateBrandRequest{ID: "1", Name: "jam tangan"})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.GetBrandResponse).Name, "jam tangan")
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventBrandUpdated && event.AggregateID == "1"
+		}))
+
+		// Case: unknown brand
+		httpCode, _ = brandService.Update(context.Background(), model.UpdateBrandRequest{ID: "2", Name: "jam tangan"})
+		assert.Equal(t, httpCode, http.StatusNotFound)
 	}(t)
 }
@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// CartService manage logical syntax for cart.
+type CartService interface {
+	Create(ctx context.Context, request model.CreateCartRequest) (int, *model.BaseResponse)
+	Get(ctx context.Context, cartID string) (int, *model.BaseResponse)
+	AddItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse)
+	UpdateItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse)
+	RemoveItem(ctx context.Context, cartID string, sku string) (int, *model.BaseResponse)
+	Checkout(ctx context.Context, request model.CheckoutCartRequest) (int, *model.BaseResponse)
+}
+
+type cartServiceImpl struct {
+	cartRepo           repository.CartRepository
+	productRepo        repository.ProductRepository
+	transactionService TransactionService
+	cartTTL            time.Duration
+	checkoutTimeout    time.Duration
+}
+
+// NewCartService returns new instance of cartServiceImpl.
+func NewCartService() *cartServiceImpl {
+	return &cartServiceImpl{}
+}
+
+// SetCartRepo injects cart's repo for cartServiceImpl.
+func (s *cartServiceImpl) SetCartRepo(repo repository.CartRepository) *cartServiceImpl {
+	s.cartRepo = repo
+	return s
+}
+
+// SetProductRepo injects product's repo for cartServiceImpl.
+func (s *cartServiceImpl) SetProductRepo(repo repository.ProductRepository) *cartServiceImpl {
+	s.productRepo = repo
+	return s
+}
+
+// SetTransactionService injects transaction's service for cartServiceImpl.
+func (s *cartServiceImpl) SetTransactionService(service TransactionService) *cartServiceImpl {
+	s.transactionService = service
+	return s
+}
+
+// SetCartTTL sets how long a cart is kept since its last change.
+func (s *cartServiceImpl) SetCartTTL(ttl time.Duration) *cartServiceImpl {
+	s.cartTTL = ttl
+	return s
+}
+
+// SetCheckoutTimeout sets how long a cart may stay claimed by a checkout, a claim
+// left by a checkout that stopped midway is released after it. It has to be longer
+// than placing an order takes.
+func (s *cartServiceImpl) SetCheckoutTimeout(timeout time.Duration) *cartServiceImpl {
+	s.checkoutTimeout = timeout
+	return s
+}
+
+// Validate validates if all dependency for cartServiceImpl is complete.
+func (s *cartServiceImpl) Validate() *cartServiceImpl {
+	if s.cartRepo == nil {
+		log.Panic("Cart service need cart repository")
+	}
+	if s.productRepo == nil {
+		log.Panic("Cart service need product repository")
+	}
+	if s.transactionService == nil {
+		log.Panic("Cart service need transaction service")
+	}
+	if s.cartTTL <= 0 {
+		log.Panic("Cart service need cart TTL")
+	}
+	if s.checkoutTimeout <= 0 {
+		log.Panic("Cart service need checkout timeout")
+	}
+	return s
+}
+
+// Create creates a new empty cart and store it into the database.
+func (s *cartServiceImpl) Create(ctx context.Context, request model.CreateCartRequest) (int, *model.BaseResponse) {
+	// validate request
+	if request.CustomerID < 0 {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Create")
+
+	cart := model.Cart{
+		CartID:     utils.GenerateCartID(),
+		CustomerID: sql.NullInt64{Int64: request.CustomerID, Valid: request.CustomerID != 0},
+		Status:     model.CartStatusActive,
+		ExpiresAt:  time.Now().Add(s.cartTTL),
+	}
+
+	err := s.cartRepo.Create(&cart)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to create cart, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp, err := s.buildResponse(&cart, []*model.CartItem{})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to build cart response, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Get returns a cart with the live price and stock of its items.
+func (s *cartServiceImpl) Get(ctx context.Context, cartID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(cartID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Get")
+
+	cart, err := s.cartRepo.GetByCartID(cartID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get cart by cart id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if cart == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return s.respond(ctx, cart)
+}
+
+// AddItem adds quantity of an SKU to a cart.
+func (s *cartServiceImpl) AddItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.SKU) == "" {
+		return utils.RequestRequired("sku")
+	} else if request.Quantity <= 0 {
+		return utils.RequestInvalid("quantity")
+	}
+
+	return s.saveItem(ctx, request, true)
+}
+
+// UpdateItem replaces the quantity of an SKU in a cart, a zero quantity removes it.
+func (s *cartServiceImpl) UpdateItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.SKU) == "" {
+		return utils.RequestRequired("sku")
+	} else if request.Quantity < 0 {
+		return utils.RequestInvalid("quantity")
+	}
+
+	if request.Quantity == 0 {
+		return s.RemoveItem(ctx, request.CartID, request.SKU)
+	}
+
+	return s.saveItem(ctx, request, false)
+}
+
+// RemoveItem removes an SKU from a cart.
+func (s *cartServiceImpl) RemoveItem(ctx context.Context, cartID string, sku string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(sku) == "" {
+		return utils.RequestRequired("sku")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "RemoveItem")
+
+	cart, httpCode, resp := s.getActiveCart(ctx, cartID)
+	if cart == nil {
+		return httpCode, resp
+	}
+
+	err := s.cartRepo.DeleteItem(cart.CartID, sku)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete cart item, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return s.extend(ctx, cart)
+}
+
+// Checkout places an order from the items of a cart through the transaction service.
+// Prices are checked again first: when a product price has changed since it was put
+// in the cart the new price is saved and the checkout is rejected, so the customer
+// confirms the new total before ordering. The saved prices are sent with the order
+// so a price changing while the order is placed rejects the checkout the same way.
+func (s *cartServiceImpl) Checkout(ctx context.Context, request model.CheckoutCartRequest) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "Checkout")
+
+	cart, httpCode, resp := s.getActiveCart(ctx, request.CartID)
+	if cart == nil {
+		return httpCode, resp
+	}
+
+	items, err := s.cartRepo.GetItems(cart.CartID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get cart items, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if len(items) == 0 {
+		return utils.RequestRequired("items")
+	}
+
+	httpCode, resp = s.refreshPrices(ctx, cart, items)
+	if resp != nil {
+		return httpCode, resp
+	}
+
+	// claim the cart so a concurrent checkout of the same cart can not place a second order
+	claimed, err := s.cartRepo.UpdateStatus(cart.CartID, model.CartStatusActive, model.CartStatusCheckingOut, "")
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to update cart status, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if !claimed {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "cart is already checked out"}
+	}
+
+	orderRequest := model.CreateTransactionRequest{
+		CustomerID:     cart.CustomerID.Int64,
+		Items:          make([]model.TransactionItem, len(items)),
+		CouponCodes:    request.CouponCodes,
+		ExpectedPrices: make(map[string]model.Money),
+	}
+	for index, item := range items {
+		orderRequest.Items[index] = model.TransactionItem{
+			SKU:      item.SKU,
+			Quantity: item.Quantity,
+		}
+		orderRequest.ExpectedPrices[item.SKU] = item.Price
+	}
+
+	httpCode, resp = s.transactionService.Create(ctx, orderRequest)
+	if httpCode != http.StatusOK {
+		// the order was not placed, the cart can be changed and checked out again
+		_, err = s.cartRepo.UpdateStatus(cart.CartID, model.CartStatusCheckingOut, model.CartStatusActive, "")
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to update cart status, err : %s", err.Error()))
+		}
+
+		if _, ok := resp.ResultData.(model.PriceChangedResponse); ok {
+			cart.Status = model.CartStatusActive
+			if priceCode, priceResp := s.refreshPrices(ctx, cart, items); priceResp != nil {
+				return priceCode, priceResp
+			}
+		}
+		return httpCode, resp
+	}
+
+	orderID := resp.ResultData.(model.CreateTransactionResponse).OrderID
+	_, err = s.cartRepo.UpdateStatus(cart.CartID, model.CartStatusCheckingOut, model.CartStatusCheckedOut, orderID)
+	if err != nil {
+		// the order is placed, only the cart keeps the wrong status
+		log.Error(fmt.Sprintf("failed to update cart status, err : %s", err.Error()))
+	}
+
+	return httpCode, resp
+}
+
+// refreshPrices saves the current price of the cart items whose product price has
+// changed and returns the conflict to send with the cart, or a nil response when
+// every price is unchanged.
+func (s *cartServiceImpl) refreshPrices(ctx context.Context, cart *model.Cart, items []*model.CartItem) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "refreshPrices")
+
+	cartResp, err := s.buildResponse(cart, items)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to build cart response, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	priceChanged := false
+	for _, item := range cartResp.Items {
+		if !item.PriceChanged {
+			continue
+		}
+
+		priceChanged = true
+		err = s.cartRepo.SaveItem(&model.CartItem{
+			CartID:   cart.CartID,
+			SKU:      item.SKU,
+			Quantity: item.Quantity,
+			Price:    item.Price,
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to save cart item, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+	}
+
+	if priceChanged {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "cart prices have changed", ResultData: cartResp}
+	}
+
+	return 0, nil
+}
+
+// saveItem stores an SKU of a cart with the current product price, adding to the
+// quantity already in the cart when add is true.
+func (s *cartServiceImpl) saveItem(ctx context.Context, request model.CartItemRequest, add bool) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "saveItem")
+
+	cart, httpCode, resp := s.getActiveCart(ctx, request.CartID)
+	if cart == nil {
+		return httpCode, resp
+	}
+
+	product, err := s.productRepo.GetBySKU(request.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get product by sku, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil || product.DeletedAt.Valid {
+		return utils.RequestInvalid("sku")
+	}
+
+	if product.Status != model.ProductStatusActive {
+		err = &unavailableProductError{sku: product.SKU, status: product.Status}
+		return http.StatusConflict, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	quantity := request.Quantity
+	if add {
+		items, err := s.cartRepo.GetItems(cart.CartID)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to get cart items, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+
+		for _, item := range items {
+			if item.SKU == product.SKU {
+				quantity += item.Quantity
+			}
+		}
+	}
+
+	if quantity > product.Stock {
+		return http.StatusConflict, &model.BaseResponse{
+			RawMessage: fmt.Sprintf("insufficient stock for sku %s", product.SKU),
+			ResultData: model.InsufficientStockResponse{
+				Items: []model.InsufficientStockItem{{SKU: product.SKU, Requested: quantity, Available: product.Stock}},
+			},
+		}
+	}
+
+	_, err = product.Price.Mul(quantity)
+	if err != nil {
+		return utils.RequestInvalid("quantity")
+	}
+
+	err = s.cartRepo.SaveItem(&model.CartItem{
+		CartID:   cart.CartID,
+		SKU:      product.SKU,
+		Quantity: quantity,
+		Price:    product.Price,
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to save cart item, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return s.extend(ctx, cart)
+}
+
+// getActiveCart returns a cart that can still be changed, or the response to send when it can not.
+// A cart claimed by a checkout for longer than the checkout timeout is released first.
+func (s *cartServiceImpl) getActiveCart(ctx context.Context, cartID string) (*model.Cart, int, *model.BaseResponse) {
+	if strings.TrimSpace(cartID) == "" {
+		httpCode, resp := utils.RequestRequired("cart_id")
+		return nil, httpCode, resp
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "getActiveCart")
+
+	cart, err := s.cartRepo.GetByCartID(cartID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get cart by cart id, err : %s", err.Error()))
+		return nil, http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if cart != nil && cart.Status == model.CartStatusCheckingOut && cart.UpdatedAt.Valid &&
+		cart.UpdatedAt.Time.Before(time.Now().Add(-s.checkoutTimeout)) {
+		released, err := s.cartRepo.ReleaseCheckout(cart.CartID, time.Now().Add(-s.checkoutTimeout))
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to release cart checkout, err : %s", err.Error()))
+			return nil, http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+
+		if released {
+			log.Warn(fmt.Sprintf("released cart %s left in checkout", cart.CartID))
+			cart.Status = model.CartStatusActive
+		}
+	}
+
+	if cart == nil {
+		return nil, http.StatusNotFound, &model.BaseResponse{}
+	} else if cart.Status != model.CartStatusActive {
+		return nil, http.StatusConflict, &model.BaseResponse{RawMessage: "cart is already checked out"}
+	} else if !time.Now().Before(cart.ExpiresAt) {
+		return nil, http.StatusGone, &model.BaseResponse{RawMessage: "cart is expired"}
+	}
+
+	return cart, 0, nil
+}
+
+// extend moves the expiry of a changed cart and responds with the cart.
+func (s *cartServiceImpl) extend(ctx context.Context, cart *model.Cart) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "extend")
+
+	cart.ExpiresAt = time.Now().Add(s.cartTTL)
+	err := s.cartRepo.Extend(cart.CartID, cart.ExpiresAt)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to extend cart, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return s.respond(ctx, cart)
+}
+
+// respond returns the response of a cart with its items.
+func (s *cartServiceImpl) respond(ctx context.Context, cart *model.Cart) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "respond")
+
+	items, err := s.cartRepo.GetItems(cart.CartID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get cart items, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp, err := s.buildResponse(cart, items)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to build cart response, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// buildResponse returns the cart items with the live price and stock of their products.
+// An item whose product is gone is shown with a zero price and is not available.
+func (s *cartServiceImpl) buildResponse(cart *model.Cart, items []*model.CartItem) (model.CartResponse, error) {
+	skus := make([]string, len(items))
+	for index, item := range items {
+		skus[index] = item.SKU
+	}
+
+	products, err := s.productRepo.GetBySKUs(skus)
+	if err != nil {
+		return model.CartResponse{}, err
+	}
+
+	productBySKU := make(map[string]*model.Product)
+	for _, product := range products {
+		if !product.DeletedAt.Valid {
+			productBySKU[product.SKU] = product
+		}
+	}
+
+	resp := model.CartResponse{
+		CartID:     cart.CartID,
+		CustomerID: cart.CustomerID.Int64,
+		Status:     cart.Status,
+		OrderID:    cart.OrderID.String,
+		Items:      make([]model.CartItemResponse, 0, len(items)),
+		ExpiresAt:  cart.ExpiresAt,
+	}
+	if cart.Status == model.CartStatusActive && !time.Now().Before(cart.ExpiresAt) {
+		resp.Status = model.CartStatusExpired
+	}
+
+	for _, item := range items {
+		itemResp := model.CartItemResponse{
+			SKU:        item.SKU,
+			Quantity:   item.Quantity,
+			AddedPrice: item.Price,
+		}
+
+		if product, ok := productBySKU[item.SKU]; ok {
+			subtotal, err := product.Price.Mul(item.Quantity)
+			if err != nil {
+				return model.CartResponse{}, err
+			}
+
+			itemResp.Price = product.Price
+			itemResp.PriceChanged = product.Price.Cmp(item.Price) != 0
+			itemResp.Stock = product.Stock
+			itemResp.Available = product.Status == model.ProductStatusActive && product.Stock >= item.Quantity
+			itemResp.Subtotal = subtotal
+		}
+
+		resp.Items = append(resp.Items, itemResp)
+		resp.TotalPrice = resp.TotalPrice.Add(itemResp.Subtotal)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,315 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+	serviceMock "github.com/richardsahvic/jamtangan/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddCartItem(t *testing.T) {
+	prepare()
+
+	// TestAddCartItemInvalidRequest
+	func(t *testing.T) {
+		cartService := service.NewCartService()
+
+		req := model.CartItemRequest{
+			CartID:   "cart-test",
+			SKU:      "sku-test",
+			Quantity: 0,
+		}
+		httpCode, resp := cartService.AddItem(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestAddCartItemExpired
+	func(t *testing.T) {
+		mockCartRepo := new(repoMock.CartRepository)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetCartTTL(time.Hour)
+
+		req := model.CartItemRequest{
+			CartID:   "cart-test",
+			SKU:      "sku-test",
+			Quantity: 1,
+		}
+		mockCartRepo.On("GetByCartID", "cart-test").Return(&model.Cart{
+			CartID:    "cart-test",
+			Status:    model.CartStatusActive,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+		httpCode, resp := cartService.AddItem(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusGone)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockCartRepo.AssertNumberOfCalls(t, "SaveItem", 0)
+	}(t)
+
+	// TestAddCartItemInsufficientStock
+	func(t *testing.T) {
+		mockCartRepo := new(repoMock.CartRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetProductRepo(mockProductRepo).
+			SetCartTTL(time.Hour)
+
+		// Case: quantity already in the cart is added to the requested one
+		req := model.CartItemRequest{
+			CartID:   "cart-test",
+			SKU:      "sku-test",
+			Quantity: 2,
+		}
+		mockCartRepo.On("GetByCartID", "cart-test").Return(&model.Cart{
+			CartID:    "cart-test",
+			Status:    model.CartStatusActive,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockCartRepo.On("GetItems", "cart-test").Return([]*model.CartItem{
+			{CartID: "cart-test", SKU: "sku-test", Quantity: 2, Price: model.NewMoney(10000)},
+		}, nil)
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive}, nil)
+		httpCode, resp := cartService.AddItem(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+
+		result := resp.ResultData.(model.InsufficientStockResponse)
+		assert.Equal(t, []model.InsufficientStockItem{{SKU: "sku-test", Requested: 4, Available: 3}}, result.Items)
+		mockCartRepo.AssertNumberOfCalls(t, "SaveItem", 0)
+	}(t)
+
+	// TestAddCartItemUnavailable
+	func(t *testing.T) {
+		mockCartRepo := new(repoMock.CartRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetProductRepo(mockProductRepo).
+			SetCartTTL(time.Hour)
+
+		// Case: a draft product can not be added before it is for sale
+		req := model.CartItemRequest{
+			CartID:   "cart-test",
+			SKU:      "sku-draft",
+			Quantity: 1,
+		}
+		mockCartRepo.On("GetByCartID", "cart-test").Return(&model.Cart{
+			CartID:    "cart-test",
+			Status:    model.CartStatusActive,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockProductRepo.On("GetBySKU", "sku-draft").Return(&model.Product{ID: 2, SKU: "sku-draft", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusDraft}, nil)
+		httpCode, resp := cartService.AddItem(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, "sku sku-draft is draft and can not be ordered")
+		mockCartRepo.AssertNumberOfCalls(t, "SaveItem", 0)
+	}(t)
+
+	// TestAddCartItemSuccess
+	func(t *testing.T) {
+		mockCartRepo := new(repoMock.CartRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetProductRepo(mockProductRepo).
+			SetCartTTL(time.Hour)
+
+		req := model.CartItemRequest{
+			CartID:   "cart-test",
+			SKU:      "sku-test",
+			Quantity: 2,
+		}
+		mockCartRepo.On("GetByCartID", "cart-test").Return(&model.Cart{
+			CartID:    "cart-test",
+			Status:    model.CartStatusActive,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}, nil)
+		mockCartRepo.On("GetItems", "cart-test").Return([]*model.CartItem{}, nil).Once()
+		mockCartRepo.On("SaveItem", mock.MatchedBy(func(item *model.CartItem) bool {
+			return item.Quantity == 2 && item.Price == model.NewMoney(10000)
+		})).Return(nil)
+		mockCartRepo.On("Extend", "cart-test", mock.Anything).Return(nil)
+		mockCartRepo.On("GetItems", "cart-test").Return([]*model.CartItem{
+			{CartID: "cart-test", SKU: "sku-test", Quantity: 2, Price: model.NewMoney(10000)},
+		}, nil)
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive}, nil)
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		httpCode, resp := cartService.AddItem(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.CartResponse)
+		assert.Equal(t, model.NewMoney(20000), result.TotalPrice)
+		assert.True(t, result.Items[0].Available)
+		assert.True(t, result.ExpiresAt.After(time.Now().Add(time.Minute)))
+		mockCartRepo.AssertNumberOfCalls(t, "Extend", 1)
+	}(t)
+}
+
+func TestCheckoutCart(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.CartRepository, *repoMock.ProductRepository, *serviceMock.TransactionService, service.CartService) {
+		mockCartRepo := new(repoMock.CartRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockTransactionService := new(serviceMock.TransactionService)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetProductRepo(mockProductRepo).
+			SetTransactionService(mockTransactionService).
+			SetCartTTL(time.Hour).
+			SetCheckoutTimeout(5 * time.Minute)
+
+		mockCartRepo.On("GetByCartID", "cart-test").Return(&model.Cart{
+			CartID:    "cart-test",
+			Status:    model.CartStatusActive,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockCartRepo.On("GetItems", "cart-test").Return([]*model.CartItem{
+			{CartID: "cart-test", SKU: "sku-test", Quantity: 2, Price: model.NewMoney(10000)},
+		}, nil)
+		return mockCartRepo, mockProductRepo, mockTransactionService, cartService
+	}
+
+	req := model.CheckoutCartRequest{
+		CartID: "cart-test",
+	}
+
+	// TestCheckoutCartPriceChanged
+	func(t *testing.T) {
+		mockCartRepo, mockProductRepo, mockTransactionService, cartService := newService()
+
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(12000), Status: model.ProductStatusActive},
+		}, nil)
+		mockCartRepo.On("SaveItem", mock.MatchedBy(func(item *model.CartItem) bool {
+			return item.Price == model.NewMoney(12000)
+		})).Return(nil)
+		httpCode, resp := cartService.Checkout(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+
+		result := resp.ResultData.(model.CartResponse)
+		assert.True(t, result.Items[0].PriceChanged)
+		assert.Equal(t, model.NewMoney(24000), result.TotalPrice)
+		mockCartRepo.AssertNumberOfCalls(t, "SaveItem", 1)
+		mockTransactionService.AssertNumberOfCalls(t, "Create", 0)
+	}(t)
+
+	// TestCheckoutCartOrderFailed
+	func(t *testing.T) {
+		mockCartRepo, mockProductRepo, mockTransactionService, cartService := newService()
+
+		// Case: the cart can be checked out again when the order is rejected
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusActive, model.CartStatusCheckingOut, "").Return(true, nil)
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusCheckingOut, model.CartStatusActive, "").Return(true, nil)
+		mockTransactionService.On("Create", mock.Anything, mock.Anything).Return(http.StatusConflict, &model.BaseResponse{RawMessage: "insufficient stock"})
+		httpCode, _ := cartService.Checkout(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		mockCartRepo.AssertNumberOfCalls(t, "UpdateStatus", 2)
+	}(t)
+
+	// TestCheckoutCartPriceChangedWhileOrdering
+	func(t *testing.T) {
+		mockCartRepo, mockProductRepo, mockTransactionService, cartService := newService()
+
+		// Case: a price changed after the cart was checked is saved and the checkout is rejected
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil).Once()
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(12000), Status: model.ProductStatusActive},
+		}, nil).Once()
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusActive, model.CartStatusCheckingOut, "").Return(true, nil)
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusCheckingOut, model.CartStatusActive, "").Return(true, nil)
+		mockTransactionService.On("Create", mock.Anything, mock.Anything).Return(http.StatusConflict, &model.BaseResponse{
+			RawMessage: "price has changed for sku sku-test",
+			ResultData: model.PriceChangedResponse{Items: []model.PriceChangedItem{
+				{SKU: "sku-test", ExpectedPrice: model.NewMoney(10000), Price: model.NewMoney(12000)},
+			}},
+		})
+		mockCartRepo.On("SaveItem", mock.MatchedBy(func(item *model.CartItem) bool {
+			return item.Price == model.NewMoney(12000)
+		})).Return(nil)
+		httpCode, resp := cartService.Checkout(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, "cart prices have changed", resp.RawMessage)
+
+		result := resp.ResultData.(model.CartResponse)
+		assert.Equal(t, model.CartStatusActive, result.Status)
+		assert.Equal(t, model.NewMoney(24000), result.TotalPrice)
+		mockCartRepo.AssertCalled(t, "UpdateStatus", "cart-test", model.CartStatusCheckingOut, model.CartStatusActive, "")
+		mockCartRepo.AssertNumberOfCalls(t, "SaveItem", 1)
+	}(t)
+
+	// TestCheckoutCartStaleClaim
+	func(t *testing.T) {
+		mockCartRepo := new(repoMock.CartRepository)
+		cartService := service.NewCartService().
+			SetCartRepo(mockCartRepo).
+			SetProductRepo(new(repoMock.ProductRepository)).
+			SetTransactionService(new(serviceMock.TransactionService)).
+			SetCartTTL(time.Hour).
+			SetCheckoutTimeout(5 * time.Minute)
+
+		// Case: a cart claimed by a checkout in progress can not be checked out
+		mockCartRepo.On("GetByCartID", "cart-claimed").Return(&model.Cart{
+			CartID:    "cart-claimed",
+			Status:    model.CartStatusCheckingOut,
+			ExpiresAt: time.Now().Add(time.Hour),
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+		httpCode, resp := cartService.Checkout(context.Background(), model.CheckoutCartRequest{CartID: "cart-claimed"})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, "cart is already checked out", resp.RawMessage)
+		mockCartRepo.AssertNumberOfCalls(t, "ReleaseCheckout", 0)
+
+		// Case: a claim older than the checkout timeout is released and the cart can be used again
+		mockCartRepo.On("GetByCartID", "cart-stale").Return(&model.Cart{
+			CartID:    "cart-stale",
+			Status:    model.CartStatusCheckingOut,
+			ExpiresAt: time.Now().Add(time.Hour),
+			UpdatedAt: sql.NullTime{Time: time.Now().Add(-10 * time.Minute), Valid: true},
+		}, nil)
+		mockCartRepo.On("ReleaseCheckout", "cart-stale", mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockCartRepo.On("GetItems", "cart-stale").Return([]*model.CartItem{}, nil)
+		httpCode, resp = cartService.Checkout(context.Background(), model.CheckoutCartRequest{CartID: "cart-stale"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, "items is required", resp.RawMessage)
+		mockCartRepo.AssertNumberOfCalls(t, "ReleaseCheckout", 1)
+	}(t)
+
+	// TestCheckoutCartSuccess
+	func(t *testing.T) {
+		mockCartRepo, mockProductRepo, mockTransactionService, cartService := newService()
+
+		mockProductRepo.On("GetBySKUs", []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusActive, model.CartStatusCheckingOut, "").Return(true, nil)
+		mockCartRepo.On("UpdateStatus", "cart-test", model.CartStatusCheckingOut, model.CartStatusCheckedOut, "ORDER-test").Return(true, nil)
+		mockTransactionService.On("Create", mock.Anything, mock.MatchedBy(func(request model.CreateTransactionRequest) bool {
+			return len(request.Items) == 1 && request.Items[0].SKU == "sku-test" && request.Items[0].Quantity == 2 &&
+				request.ExpectedPrices["sku-test"] == model.NewMoney(10000)
+		})).Return(http.StatusOK, &model.BaseResponse{ResultData: model.CreateTransactionResponse{OrderID: "ORDER-test"}})
+		httpCode, resp := cartService.Checkout(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.CreateTransactionResponse)
+		assert.Equal(t, "ORDER-test", result.OrderID)
+		mockCartRepo.AssertCalled(t, "UpdateStatus", "cart-test", model.CartStatusCheckingOut, model.CartStatusCheckedOut, "ORDER-test")
+	}(t)
+}
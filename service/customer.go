@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// localePattern matches locales such as "en" or "en-US".
+var localePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+// phonePattern matches phone numbers in E.164 format, such as "+6281234567890".
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// maxPushTokenLength is the longest push token a customer can register.
+const maxPushTokenLength = 255
+
+// CustomerService manage logical syntax for customer.
+type CustomerService interface {
+	Save(ctx context.Context, request model.SaveCustomerRequest) (int, *model.BaseResponse)
+	Get(ctx context.Context, customerID string) (int, *model.BaseResponse)
+	GetPreferences(ctx context.Context, customerID string) (int, *model.BaseResponse)
+	SavePreferences(ctx context.Context, request model.SaveNotificationPreferencesRequest) (int, *model.BaseResponse)
+}
+
+type customerServiceImpl struct {
+	customerRepo   repository.CustomerRepository
+	preferenceRepo repository.NotificationPreferenceRepository
+	txRepo         repository.TxRepository
+	defaultLocale  string
+}
+
+// NewCustomerService returns new instance of customerServiceImpl.
+func NewCustomerService() *customerServiceImpl {
+	return &customerServiceImpl{}
+}
+
+// SetCustomerRepo injects customer's repo for customerServiceImpl.
+func (s *customerServiceImpl) SetCustomerRepo(repo repository.CustomerRepository) *customerServiceImpl {
+	s.customerRepo = repo
+	return s
+}
+
+// SetPreferenceRepo injects notification preference's repo for customerServiceImpl.
+func (s *customerServiceImpl) SetPreferenceRepo(repo repository.NotificationPreferenceRepository) *customerServiceImpl {
+	s.preferenceRepo = repo
+	return s
+}
+
+// SetTxRepo injects transaction runner for customerServiceImpl.
+func (s *customerServiceImpl) SetTxRepo(repo repository.TxRepository) *customerServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
+// SetDefaultLocale sets the locale of customers that have not chosen one.
+func (s *customerServiceImpl) SetDefaultLocale(locale string) *customerServiceImpl {
+	s.defaultLocale = locale
+	return s
+}
+
+// Validate validates if all dependency for customerServiceImpl is complete.
+func (s *customerServiceImpl) Validate() *customerServiceImpl {
+	if s.customerRepo == nil {
+		log.Panic("Customer service need customer repository")
+	}
+	if s.preferenceRepo == nil {
+		log.Panic("Customer service need notification preference repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Customer service need tx repository")
+	}
+	if s.defaultLocale == "" {
+		log.Panic("Customer service need default locale")
+	}
+	return s
+}
+
+// Save stores the contact details of a customer.
+func (s *customerServiceImpl) Save(ctx context.Context, request model.SaveCustomerRequest) (int, *model.BaseResponse) {
+	request.Email = strings.TrimSpace(request.Email)
+	request.Phone = strings.TrimSpace(request.Phone)
+	request.PushToken = strings.TrimSpace(request.PushToken)
+	if request.Locale == "" {
+		request.Locale = s.defaultLocale
+	}
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if request.Email != "" && !isEmail(request.Email) {
+		return utils.RequestInvalid("email")
+	} else if request.Phone != "" && !phonePattern.MatchString(request.Phone) {
+		return utils.RequestInvalid("phone")
+	} else if len(request.PushToken) > maxPushTokenLength {
+		return utils.RequestInvalid("push_token")
+	} else if !localePattern.MatchString(request.Locale) {
+		return utils.RequestInvalid("locale")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Save")
+
+	customer := model.Customer{
+		ID:        request.CustomerID,
+		Email:     sql.NullString{String: request.Email, Valid: request.Email != ""},
+		Phone:     sql.NullString{String: request.Phone, Valid: request.Phone != ""},
+		PushToken: sql.NullString{String: request.PushToken, Valid: request.PushToken != ""},
+		Locale:    request.Locale,
+	}
+
+	err := s.customerRepo.Save(&customer)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to save customer, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: customerResponse(&customer)}
+}
+
+// Get returns the contact details of a customer.
+func (s *customerServiceImpl) Get(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Get")
+
+	customer, err := s.customerRepo.GetByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get customer by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if customer == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: customerResponse(customer)}
+}
+
+// GetPreferences returns the notification preferences of a customer for every
+// channel and category, with the defaults of the ones the customer has not chosen.
+func (s *customerServiceImpl) GetPreferences(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("customer_id")
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "GetPreferences")
+
+	customer, err := s.customerRepo.GetByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get customer by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if customer == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	preferences, err := s.preferenceRepo.GetByCustomerID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get notification preferences, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: preferencesResponse(customer, preferences)}
+}
+
+// SavePreferences changes the timezone, quiet hours and channel preferences of a customer.
+func (s *customerServiceImpl) SavePreferences(ctx context.Context, request model.SaveNotificationPreferencesRequest) (int, *model.BaseResponse) {
+	request.Timezone = strings.TrimSpace(request.Timezone)
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	}
+
+	if request.Timezone != "" {
+		_, err := time.LoadLocation(request.Timezone)
+		if err != nil || request.Timezone == "Local" {
+			return utils.RequestInvalid("timezone")
+		}
+	}
+
+	if request.QuietHours != nil && (request.QuietHours.Start != "" || request.QuietHours.End != "") {
+		start, okStart := parseClock(request.QuietHours.Start)
+		end, okEnd := parseClock(request.QuietHours.End)
+		if !okStart || !okEnd || start == end {
+			return utils.RequestInvalid("quiet_hours")
+		}
+	}
+
+	preferences := make([]*model.NotificationPreference, 0, len(request.Preferences))
+	for _, item := range request.Preferences {
+		if !isNotificationChannel(item.Channel) || !isNotificationCategory(item.Category) {
+			return utils.RequestInvalid("preferences")
+		}
+
+		preferences = append(preferences, &model.NotificationPreference{
+			CustomerID: request.CustomerID,
+			Channel:    item.Channel,
+			Category:   item.Category,
+			Enabled:    item.Enabled,
+		})
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "SavePreferences")
+
+	var customer *model.Customer
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		customer, err = s.customerRepo.GetByIDForUpdate(tx, request.CustomerID)
+		if err != nil || customer == nil {
+			return err
+		}
+
+		if request.Timezone != "" {
+			customer.Timezone = request.Timezone
+		}
+		if request.QuietHours != nil {
+			customer.QuietHoursStart = sql.NullString{String: request.QuietHours.Start, Valid: request.QuietHours.Start != ""}
+			customer.QuietHoursEnd = sql.NullString{String: request.QuietHours.End, Valid: request.QuietHours.End != ""}
+		}
+
+		err = s.customerRepo.UpdateNotificationSettingsTx(tx, customer)
+		if err != nil {
+			return err
+		}
+
+		return s.preferenceRepo.SaveTx(tx, preferences)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to save notification preferences, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if customer == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return s.GetPreferences(ctx, strconv.FormatInt(request.CustomerID, 10))
+}
+
+// isNotificationChannel returns true when channel is a known notification channel.
+func isNotificationChannel(channel model.NotificationChannel) bool {
+	for _, value := range model.NotificationChannels {
+		if value == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// isNotificationCategory returns true when category is a known notification category.
+func isNotificationCategory(category model.NotificationCategory) bool {
+	for _, value := range model.NotificationCategories {
+		if value == category {
+			return true
+		}
+	}
+	return false
+}
+
+// preferencesResponse returns the preferences of a customer for every channel and category.
+func preferencesResponse(customer *model.Customer, preferences []*model.NotificationPreference) model.NotificationPreferencesResponse {
+	chosen := make(map[string]bool)
+	for _, preference := range preferences {
+		chosen[string(preference.Channel)+"/"+string(preference.Category)] = preference.Enabled
+	}
+
+	resp := model.NotificationPreferencesResponse{
+		CustomerID:  customer.ID,
+		Timezone:    customer.Timezone,
+		Preferences: make([]model.NotificationPreferenceItem, 0),
+	}
+	if customer.QuietHoursStart.Valid && customer.QuietHoursEnd.Valid {
+		resp.QuietHours = &model.QuietHours{Start: customer.QuietHoursStart.String, End: customer.QuietHoursEnd.String}
+	}
+
+	for _, channel := range model.NotificationChannels {
+		for _, category := range model.NotificationCategories {
+			enabled, ok := chosen[string(channel)+"/"+string(category)]
+			if !ok {
+				enabled = model.DefaultNotificationEnabled(category)
+			}
+			resp.Preferences = append(resp.Preferences, model.NotificationPreferenceItem{
+				Channel:  channel,
+				Category: category,
+				Enabled:  enabled,
+			})
+		}
+	}
+
+	return resp
+}
+
+// isEmail returns true when value is a bare email address.
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+// customerResponse returns the response of a customer.
+func customerResponse(customer *model.Customer) model.CustomerResponse {
+	return model.CustomerResponse{
+		CustomerID: customer.ID,
+		Email:      customer.Email.String,
+		Phone:      customer.Phone.String,
+		HasPush:    customer.PushToken.Valid,
+		Locale:     customer.Locale,
+		Timezone:   customer.Timezone,
+	}
+}
@@ -0,0 +1,197 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSaveCustomer(t *testing.T) {
+	prepare()
+
+	// TestSaveCustomerInvalidRequest
+	func(t *testing.T) {
+		customerService := service.NewCustomerService().
+			SetDefaultLocale("en")
+
+		// Case: invalid email
+		req := model.SaveCustomerRequest{
+			CustomerID: 7,
+			Email:      "budi at example.com",
+		}
+		httpCode, resp := customerService.Save(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: phone number not in E.164 format
+		req = model.SaveCustomerRequest{
+			CustomerID: 7,
+			Phone:      "081234567890",
+		}
+		httpCode, resp = customerService.Save(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: invalid locale
+		req = model.SaveCustomerRequest{
+			CustomerID: 7,
+			Email:      "budi@example.com",
+			Locale:     "indonesian",
+		}
+		httpCode, resp = customerService.Save(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestSaveCustomerSuccess
+	func(t *testing.T) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		customerService := service.NewCustomerService().
+			SetCustomerRepo(mockCustomerRepo).
+			SetDefaultLocale("en")
+
+		// Case: default locale is used
+		req := model.SaveCustomerRequest{
+			CustomerID: 7,
+			Email:      " budi@example.com ",
+		}
+		mockCustomerRepo.On("Save", &model.Customer{
+			ID:     7,
+			Email:  sql.NullString{String: "budi@example.com", Valid: true},
+			Locale: "en",
+		}).Return(nil)
+		httpCode, resp := customerService.Save(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData, model.CustomerResponse{CustomerID: 7, Email: "budi@example.com", Locale: "en"})
+
+		// Case: phone number and push token are saved
+		req = model.SaveCustomerRequest{
+			CustomerID: 8,
+			Phone:      "+6281234567890",
+			PushToken:  "device-token",
+			Locale:     "id",
+		}
+		mockCustomerRepo.On("Save", &model.Customer{
+			ID:        8,
+			Phone:     sql.NullString{String: "+6281234567890", Valid: true},
+			PushToken: sql.NullString{String: "device-token", Valid: true},
+			Locale:    "id",
+		}).Return(nil)
+		httpCode, resp = customerService.Save(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData, model.CustomerResponse{CustomerID: 8, Phone: "+6281234567890", HasPush: true, Locale: "id"})
+	}(t)
+}
+
+func TestSaveNotificationPreferences(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.CustomerRepository, *repoMock.NotificationPreferenceRepository, service.CustomerService) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		mockPreferenceRepo := new(repoMock.NotificationPreferenceRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		customerService := service.NewCustomerService().
+			SetCustomerRepo(mockCustomerRepo).
+			SetPreferenceRepo(mockPreferenceRepo).
+			SetTxRepo(mockTxRepo).
+			SetDefaultLocale("en").
+			Validate()
+		return mockCustomerRepo, mockPreferenceRepo, customerService
+	}
+
+	// TestSaveNotificationPreferencesInvalidRequest
+	func(t *testing.T) {
+		_, _, customerService := newService()
+
+		// Case: unknown timezone
+		req := model.SaveNotificationPreferencesRequest{CustomerID: 7, Timezone: "Mars/Olympus"}
+		httpCode, resp := customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: quiet hours without end
+		req = model.SaveNotificationPreferencesRequest{CustomerID: 7, QuietHours: &model.QuietHours{Start: "22:00"}}
+		httpCode, resp = customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: unknown channel
+		req = model.SaveNotificationPreferencesRequest{
+			CustomerID:  7,
+			Preferences: []model.NotificationPreferenceItem{{Channel: "pigeon", Category: model.NotificationCategoryMarketing}},
+		}
+		httpCode, resp = customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestSaveNotificationPreferencesNotFound
+	func(t *testing.T) {
+		mockCustomerRepo, _, customerService := newService()
+
+		mockCustomerRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(nil, nil)
+		req := model.SaveNotificationPreferencesRequest{CustomerID: 7, Timezone: "Asia/Jakarta"}
+		httpCode, _ := customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestSaveNotificationPreferencesErrorDatabase
+	func(t *testing.T) {
+		mockCustomerRepo, mockPreferenceRepo, customerService := newService()
+
+		mockCustomerRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(&model.Customer{ID: 7, Timezone: "UTC"}, nil)
+		mockCustomerRepo.On("UpdateNotificationSettingsTx", mock.Anything, mock.Anything).Return(nil)
+		mockPreferenceRepo.On("SaveTx", mock.Anything, mock.Anything).Return(errors.New("error"))
+		req := model.SaveNotificationPreferencesRequest{
+			CustomerID:  7,
+			Preferences: []model.NotificationPreferenceItem{{Channel: model.NotificationChannelSMS, Category: model.NotificationCategoryMarketing, Enabled: true}},
+		}
+		httpCode, _ := customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+	}(t)
+
+	// TestSaveNotificationPreferencesSuccess
+	func(t *testing.T) {
+		mockCustomerRepo, mockPreferenceRepo, customerService := newService()
+
+		// Case: given fields are saved, every channel and category is returned
+		customer := &model.Customer{ID: 7, Timezone: "UTC"}
+		mockCustomerRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(customer, nil)
+		mockCustomerRepo.On("UpdateNotificationSettingsTx", mock.Anything, mock.MatchedBy(func(customer *model.Customer) bool {
+			return customer.Timezone == "Asia/Jakarta" && customer.QuietHoursStart.String == "22:00" && customer.QuietHoursEnd.String == "07:00"
+		})).Return(nil)
+		mockPreferenceRepo.On("SaveTx", mock.Anything, []*model.NotificationPreference{
+			{CustomerID: 7, Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryTransactional, Enabled: false},
+		}).Return(nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(customer, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{
+			{CustomerID: 7, Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryTransactional, Enabled: false},
+		}, nil)
+		req := model.SaveNotificationPreferencesRequest{
+			CustomerID:  7,
+			Timezone:    "Asia/Jakarta",
+			QuietHours:  &model.QuietHours{Start: "22:00", End: "07:00"},
+			Preferences: []model.NotificationPreferenceItem{{Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryTransactional}},
+		}
+		httpCode, resp := customerService.SavePreferences(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.NotificationPreferencesResponse)
+		assert.Equal(t, result.Timezone, "Asia/Jakarta")
+		assert.Equal(t, result.QuietHours, &model.QuietHours{Start: "22:00", End: "07:00"})
+		assert.Len(t, result.Preferences, 8)
+		assert.Equal(t, result.Preferences[0], model.NotificationPreferenceItem{Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryTransactional, Enabled: false})
+		assert.Equal(t, result.Preferences[1], model.NotificationPreferenceItem{Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryMarketing, Enabled: false})
+		assert.Equal(t, result.Preferences[2], model.NotificationPreferenceItem{Channel: model.NotificationChannelSMS, Category: model.NotificationCategoryTransactional, Enabled: true})
+	}(t)
+}
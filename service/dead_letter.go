@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+const (
+	defaultDeadLetterLimit = 20
+	maxDeadLetterLimit     = 100
+	// maxDeadLetterRetry is the most dead letters retried in one request.
+	maxDeadLetterRetry = 100
+)
+
+// DeadLetterService manage logical syntax for dead letters.
+type DeadLetterService interface {
+	List(ctx context.Context, request model.ListDeadLetterRequest) (int, *model.BaseResponse)
+	Get(ctx context.Context, id string) (int, *model.BaseResponse)
+	Retry(ctx context.Context, request model.RetryDeadLetterRequest) (int, *model.BaseResponse)
+	Purge(ctx context.Context, request model.PurgeDeadLetterRequest) (int, *model.BaseResponse)
+}
+
+type deadLetterServiceImpl struct {
+	deadLetterRepo repository.DeadLetterRepository
+	outboxRepo     repository.OutboxRepository
+	txRepo         repository.TxRepository
+}
+
+// NewDeadLetterService returns new instance of deadLetterServiceImpl.
+func NewDeadLetterService() *deadLetterServiceImpl {
+	return &deadLetterServiceImpl{}
+}
+
+// SetDeadLetterRepo injects dead letter's repo for deadLetterServiceImpl.
+func (s *deadLetterServiceImpl) SetDeadLetterRepo(repo repository.DeadLetterRepository) *deadLetterServiceImpl {
+	s.deadLetterRepo = repo
+	return s
+}
+
+// SetOutboxRepo injects outbox's repo for deadLetterServiceImpl.
+func (s *deadLetterServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *deadLetterServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetTxRepo injects transaction runner for deadLetterServiceImpl.
+func (s *deadLetterServiceImpl) SetTxRepo(repo repository.TxRepository) *deadLetterServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
+// Validate validates if all dependency for deadLetterServiceImpl is complete.
+func (s *deadLetterServiceImpl) Validate() *deadLetterServiceImpl {
+	if s.deadLetterRepo == nil {
+		log.Panic("Dead letter service need dead letter repository")
+	}
+	if s.outboxRepo == nil {
+		log.Panic("Dead letter service need outbox repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Dead letter service need tx repository")
+	}
+	return s
+}
+
+// List returns a page of dead letters, newest first.
+func (s *deadLetterServiceImpl) List(ctx context.Context, request model.ListDeadLetterRequest) (int, *model.BaseResponse) {
+	// validate request
+	var err error
+	filter := model.DeadLetterFilter{
+		EventType: model.OutboxEventType(strings.TrimSpace(request.EventType)),
+		Limit:     defaultDeadLetterLimit,
+	}
+
+	if request.Status != "" {
+		filter.Status = model.DeadLetterStatus(request.Status)
+		if filter.Status != model.DeadLetterStatusDead && filter.Status != model.DeadLetterStatusRetried {
+			return utils.RequestInvalid("status")
+		}
+	}
+
+	if request.Limit != "" {
+		filter.Limit, err = strconv.Atoi(request.Limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > maxDeadLetterLimit {
+			return utils.RequestInvalid("limit")
+		}
+	}
+
+	if request.Cursor != "" {
+		_, filter.BeforeID, err = utils.DecodeCursor(request.Cursor)
+		if err != nil || filter.BeforeID <= 0 {
+			return utils.RequestInvalid("cursor")
+		}
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	letters, err := s.deadLetterRepo.List(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list dead letters, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListDeadLetterResponse{
+		DeadLetters: make([]model.DeadLetterResponse, len(letters)),
+	}
+	for index, letter := range letters {
+		resp.DeadLetters[index] = deadLetterResponse(letter)
+	}
+
+	if len(letters) == filter.Limit {
+		resp.NextCursor = utils.EncodeCursor("", letters[len(letters)-1].ID)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Get returns the details of a dead letter.
+func (s *deadLetterServiceImpl) Get(ctx context.Context, id string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(id) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	letterID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Get")
+
+	letter, err := s.deadLetterRepo.GetByID(letterID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get dead letter, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if letter == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: deadLetterResponse(letter)}
+}
+
+// Retry puts dead letters back into the outbox as new events, which are only
+// delivered to the channels that did not receive them. The new events keep the
+// ID of the first event as their origin, receivers see them as the same event. The payload of a single
+// dead letter can be replaced to fix what made it fail.
+func (s *deadLetterServiceImpl) Retry(ctx context.Context, request model.RetryDeadLetterRequest) (int, *model.BaseResponse) {
+	// validate request
+	if len(request.IDs) == 0 {
+		return utils.RequestRequired("ids")
+	} else if len(request.IDs) > maxDeadLetterRetry {
+		return utils.RequestInvalid("ids")
+	}
+
+	for _, id := range request.IDs {
+		if id <= 0 {
+			return utils.RequestInvalid("ids")
+		}
+	}
+
+	if len(request.Payload) > 0 {
+		payload := bytes.TrimSpace(request.Payload)
+		if len(request.IDs) > 1 || !json.Valid(payload) || payload[0] != '{' {
+			return utils.RequestInvalid("payload")
+		}
+		request.Payload = payload
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Retry")
+
+	resp := model.RetryDeadLetterResponse{
+		Retried: make([]model.RetriedDeadLetter, 0),
+		Skipped: make([]int64, 0),
+	}
+
+	for _, id := range request.IDs {
+		var event *model.OutboxEvent
+		err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+			letter, err := s.deadLetterRepo.GetByIDForUpdate(tx, id)
+			if err != nil || letter == nil || letter.Status != model.DeadLetterStatusDead {
+				return err
+			}
+
+			if len(request.Payload) > 0 {
+				letter.Payload = request.Payload
+			}
+
+			event = &model.OutboxEvent{
+				OriginEventID:     letter.OriginEventID,
+				EventType:         letter.EventType,
+				AggregateID:       letter.AggregateID,
+				Payload:           letter.Payload,
+				DeliveredChannels: letter.DeliveredChannels,
+			}
+			if !event.OriginEventID.Valid {
+				event.OriginEventID = sql.NullInt64{Int64: letter.EventID, Valid: true}
+			}
+			err = s.outboxRepo.CreateTx(tx, event)
+			if err != nil {
+				return err
+			}
+
+			letter.RetriedEventID.Int64, letter.RetriedEventID.Valid = event.ID, true
+			return s.deadLetterRepo.MarkRetriedTx(tx, letter)
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to retry dead letter %d, err : %s", id, err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error(), ResultData: resp}
+		}
+
+		if event == nil {
+			resp.Skipped = append(resp.Skipped, id)
+			continue
+		}
+		resp.Retried = append(resp.Retried, model.RetriedDeadLetter{ID: id, EventID: event.ID})
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Purge removes the dead letters matching the request, at least one filter is required.
+func (s *deadLetterServiceImpl) Purge(ctx context.Context, request model.PurgeDeadLetterRequest) (int, *model.BaseResponse) {
+	// validate request
+	purge := model.DeadLetterPurge{
+		IDs:       request.IDs,
+		EventType: model.OutboxEventType(strings.TrimSpace(request.EventType)),
+		Status:    model.DeadLetterStatus(strings.TrimSpace(request.Status)),
+	}
+
+	if len(purge.IDs) == 0 && purge.EventType == "" && purge.Status == "" && strings.TrimSpace(request.Before) == "" {
+		return utils.RequestRequired("ids, event_type, status or before")
+	}
+
+	for _, id := range purge.IDs {
+		if id <= 0 {
+			return utils.RequestInvalid("ids")
+		}
+	}
+
+	if purge.Status != "" && purge.Status != model.DeadLetterStatusDead && purge.Status != model.DeadLetterStatusRetried {
+		return utils.RequestInvalid("status")
+	}
+
+	if strings.TrimSpace(request.Before) != "" {
+		before, err := time.Parse(time.RFC3339, strings.TrimSpace(request.Before))
+		if err != nil {
+			return utils.RequestInvalid("before")
+		}
+		purge.Before = &before
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Purge")
+
+	purged, err := s.deadLetterRepo.Purge(purge)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to purge dead letters, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: model.PurgeDeadLetterResponse{Purged: purged}}
+}
+
+// deadLetterResponse returns the response of a dead letter.
+func deadLetterResponse(letter *model.DeadLetter) model.DeadLetterResponse {
+	resp := model.DeadLetterResponse{
+		ID:                letter.ID,
+		EventID:           letter.EventID,
+		EventType:         letter.EventType,
+		AggregateID:       letter.AggregateID,
+		Payload:           letter.Payload,
+		Attempts:          letter.Attempts,
+		DeliveredChannels: make([]string, 0),
+		LastError:         letter.LastError.String,
+		Status:            letter.Status,
+		EventCreatedAt:    letter.EventCreatedAt,
+		CreatedAt:         letter.CreatedAt,
+		RetriedAt:         utils.TimePtr(letter.RetriedAt),
+	}
+
+	if letter.DeliveredChannels != "" {
+		resp.DeliveredChannels = strings.Split(letter.DeliveredChannels, ",")
+	}
+
+	if letter.RetriedEventID.Valid {
+		resp.RetriedEventID = &letter.RetriedEventID.Int64
+	}
+
+	return resp
+}
@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListDeadLetter(t *testing.T) {
+	prepare()
+
+	// TestListDeadLetterInvalidRequest
+	func(t *testing.T) {
+		deadLetterService := service.NewDeadLetterService()
+
+		// Case: unknown status
+		httpCode, _ := deadLetterService.List(context.Background(), model.ListDeadLetterRequest{Status: "sent"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid cursor
+		httpCode, _ = deadLetterService.List(context.Background(), model.ListDeadLetterRequest{Cursor: "!"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestListDeadLetterSuccess
+	func(t *testing.T) {
+		mockDeadLetterRepo := new(repoMock.DeadLetterRepository)
+		deadLetterService := service.NewDeadLetterService().
+			SetDeadLetterRepo(mockDeadLetterRepo)
+
+		// Case: a full page returns the cursor of the next page
+		mockDeadLetterRepo.On("List", model.DeadLetterFilter{EventType: model.OutboxEventOrderCreated, Status: model.DeadLetterStatusDead, Limit: 1}).
+			Return([]*model.DeadLetter{
+				{ID: 9, EventID: 90, EventType: model.OutboxEventOrderCreated, Payload: []byte(`{}`), DeliveredChannels: "email,inbox",
+					LastError: sql.NullString{String: "webhook: timeout", Valid: true}, Status: model.DeadLetterStatusDead},
+			}, nil)
+		req := model.ListDeadLetterRequest{
+			EventType: string(model.OutboxEventOrderCreated),
+			Status:    string(model.DeadLetterStatusDead),
+			Limit:     "1",
+		}
+		httpCode, resp := deadLetterService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListDeadLetterResponse)
+		assert.Len(t, result.DeadLetters, 1)
+		assert.Equal(t, result.DeadLetters[0].DeliveredChannels, []string{"email", "inbox"})
+		assert.Equal(t, result.DeadLetters[0].LastError, "webhook: timeout")
+		assert.Equal(t, result.NextCursor, utils.EncodeCursor("", 9))
+	}(t)
+}
+
+func TestRetryDeadLetter(t *testing.T) {
+	prepare()
+
+	// TestRetryDeadLetterInvalidRequest
+	func(t *testing.T) {
+		deadLetterService := service.NewDeadLetterService()
+
+		// Case: missing IDs
+		httpCode, _ := deadLetterService.Retry(context.Background(), model.RetryDeadLetterRequest{})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: a payload for several dead letters
+		httpCode, _ = deadLetterService.Retry(context.Background(), model.RetryDeadLetterRequest{IDs: []int64{1, 2}, Payload: json.RawMessage(`{}`)})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: a payload that is not a JSON object
+		httpCode, _ = deadLetterService.Retry(context.Background(), model.RetryDeadLetterRequest{IDs: []int64{1}, Payload: json.RawMessage(`[1`)})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestRetryDeadLetterErrorDatabase
+	func(t *testing.T) {
+		mockDeadLetterRepo := new(repoMock.DeadLetterRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		deadLetterService := service.NewDeadLetterService().
+			SetDeadLetterRepo(mockDeadLetterRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockDeadLetterRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(nil, errors.New("error"))
+		httpCode, _ := deadLetterService.Retry(context.Background(), model.RetryDeadLetterRequest{IDs: []int64{1}})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+	}(t)
+
+	// TestRetryDeadLetterSuccess
+	func(t *testing.T) {
+		mockDeadLetterRepo := new(repoMock.DeadLetterRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		deadLetterService := service.NewDeadLetterService().
+			SetDeadLetterRepo(mockDeadLetterRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockDeadLetterRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.DeadLetter{
+			ID: 1, EventID: 7, EventType: model.OutboxEventOrderCreated, AggregateID: "ORDER-1",
+			Payload: []byte(`{"order_id":"ORDER-1"}`), DeliveredChannels: "email", Status: model.DeadLetterStatusDead,
+		}, nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventOrderCreated && event.DeliveredChannels == "email" &&
+				string(event.Payload) == `{"order_id":"ORDER-2"}` && event.OriginEventID.Int64 == 7
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.OutboxEvent).ID = 50
+		}).Return(nil)
+		mockDeadLetterRepo.On("MarkRetriedTx", mock.Anything, mock.MatchedBy(func(letter *model.DeadLetter) bool {
+			return letter.ID == 1 && letter.RetriedEventID.Int64 == 50 && string(letter.Payload) == `{"order_id":"ORDER-2"}`
+		})).Return(nil)
+
+		// Case: an edited dead letter is put back into the outbox
+		req := model.RetryDeadLetterRequest{IDs: []int64{1}, Payload: json.RawMessage(` {"order_id":"ORDER-2"} `)}
+		httpCode, resp := deadLetterService.Retry(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.RetryDeadLetterResponse).Retried, []model.RetriedDeadLetter{{ID: 1, EventID: 50}})
+
+		// Case: retried and unknown dead letters are skipped
+		mockDeadLetterRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(&model.DeadLetter{ID: 2, Status: model.DeadLetterStatusRetried}, nil)
+		mockDeadLetterRepo.On("GetByIDForUpdate", mock.Anything, int64(3)).Return(nil, nil)
+		httpCode, resp = deadLetterService.Retry(context.Background(), model.RetryDeadLetterRequest{IDs: []int64{2, 3}})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.RetryDeadLetterResponse).Skipped, []int64{2, 3})
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+}
+
+func TestPurgeDeadLetter(t *testing.T) {
+	prepare()
+
+	// TestPurgeDeadLetterInvalidRequest
+	func(t *testing.T) {
+		deadLetterService := service.NewDeadLetterService()
+
+		// Case: nothing to match
+		httpCode, _ := deadLetterService.Purge(context.Background(), model.PurgeDeadLetterRequest{})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid time
+		httpCode, _ = deadLetterService.Purge(context.Background(), model.PurgeDeadLetterRequest{Before: "yesterday"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestPurgeDeadLetterSuccess
+	func(t *testing.T) {
+		mockDeadLetterRepo := new(repoMock.DeadLetterRepository)
+		deadLetterService := service.NewDeadLetterService().
+			SetDeadLetterRepo(mockDeadLetterRepo)
+
+		before := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+		mockDeadLetterRepo.On("Purge", mock.MatchedBy(func(purge model.DeadLetterPurge) bool {
+			return purge.EventType == model.OutboxEventOrderCreated && purge.Status == model.DeadLetterStatusRetried &&
+				purge.Before.Equal(before) && len(purge.IDs) == 0
+		})).Return(int64(4), nil)
+		req := model.PurgeDeadLetterRequest{
+			EventType: string(model.OutboxEventOrderCreated),
+			Status:    string(model.DeadLetterStatusRetried),
+			Before:    "2022-02-01T00:00:00Z",
+		}
+		httpCode, resp := deadLetterService.Purge(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.PurgeDeadLetterResponse).Purged, int64(4))
+	}(t)
+}
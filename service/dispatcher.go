@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+)
+
+// maxOutboxErrorLength is the longest delivery error kept with an event.
+const maxOutboxErrorLength = 1000
+
+// DispatcherService delivers outbox events to the notification channels.
+type DispatcherService interface {
+	Run(ctx context.Context)
+	DispatchPending(ctx context.Context) (int, error)
+}
+
+type dispatcherServiceImpl struct {
+	outboxRepo     repository.OutboxRepository
+	customerRepo   repository.CustomerRepository
+	preferenceRepo repository.NotificationPreferenceRepository
+	deadLetterRepo repository.DeadLetterRepository
+	txRepo         repository.TxRepository
+	channels       []notifier.Channel
+	pollInterval   time.Duration
+	batchSize      int
+	lease          time.Duration
+	maxAttempts    int64
+	retryBase      time.Duration
+	retryMax       time.Duration
+}
+
+// NewDispatcherService returns new instance of dispatcherServiceImpl.
+func NewDispatcherService() *dispatcherServiceImpl {
+	return &dispatcherServiceImpl{}
+}
+
+// SetOutboxRepo injects outbox's repo for dispatcherServiceImpl.
+func (s *dispatcherServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *dispatcherServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetCustomerRepo injects customer's repo for dispatcherServiceImpl.
+func (s *dispatcherServiceImpl) SetCustomerRepo(repo repository.CustomerRepository) *dispatcherServiceImpl {
+	s.customerRepo = repo
+	return s
+}
+
+// SetPreferenceRepo injects notification preference's repo for dispatcherServiceImpl.
+func (s *dispatcherServiceImpl) SetPreferenceRepo(repo repository.NotificationPreferenceRepository) *dispatcherServiceImpl {
+	s.preferenceRepo = repo
+	return s
+}
+
+// SetDeadLetterRepo injects dead letter's repo for dispatcherServiceImpl.
+func (s *dispatcherServiceImpl) SetDeadLetterRepo(repo repository.DeadLetterRepository) *dispatcherServiceImpl {
+	s.deadLetterRepo = repo
+	return s
+}
+
+// SetTxRepo injects transaction runner for dispatcherServiceImpl.
+func (s *dispatcherServiceImpl) SetTxRepo(repo repository.TxRepository) *dispatcherServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
+// AddChannel registers a channel every event is delivered to.
+func (s *dispatcherServiceImpl) AddChannel(channel notifier.Channel) *dispatcherServiceImpl {
+	s.channels = append(s.channels, channel)
+	return s
+}
+
+// SetPollInterval sets how often the outbox is checked for due events.
+func (s *dispatcherServiceImpl) SetPollInterval(interval time.Duration) *dispatcherServiceImpl {
+	s.pollInterval = interval
+	return s
+}
+
+// SetBatchSize sets how many events are claimed at once, and how long they are
+// leased to this dispatcher before another one may deliver them.
+func (s *dispatcherServiceImpl) SetBatchSize(size int, lease time.Duration) *dispatcherServiceImpl {
+	s.batchSize = size
+	s.lease = lease
+	return s
+}
+
+// SetRetry sets how many times an event is attempted before it is dead, and the
+// backoff between attempts, doubled after every failure up to max.
+func (s *dispatcherServiceImpl) SetRetry(maxAttempts int64, base time.Duration, max time.Duration) *dispatcherServiceImpl {
+	s.maxAttempts = maxAttempts
+	s.retryBase = base
+	s.retryMax = max
+	return s
+}
+
+// Validate validates if all dependency for dispatcherServiceImpl is complete.
+func (s *dispatcherServiceImpl) Validate() *dispatcherServiceImpl {
+	if s.outboxRepo == nil {
+		log.Panic("Dispatcher service need outbox repository")
+	}
+	if s.customerRepo == nil {
+		log.Panic("Dispatcher service need customer repository")
+	}
+	if s.preferenceRepo == nil {
+		log.Panic("Dispatcher service need notification preference repository")
+	}
+	if s.deadLetterRepo == nil {
+		log.Panic("Dispatcher service need dead letter repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Dispatcher service need tx repository")
+	}
+	if len(s.channels) == 0 {
+		log.Panic("Dispatcher service need at least one channel")
+	}
+	if s.pollInterval <= 0 {
+		log.Panic("Dispatcher service need poll interval")
+	}
+	if s.batchSize <= 0 || s.lease <= 0 {
+		log.Panic("Dispatcher service need batch size and lease")
+	}
+	if s.maxAttempts <= 0 || s.retryBase <= 0 || s.retryMax < s.retryBase {
+		log.Panic("Dispatcher service need retry policy")
+	}
+	return s
+}
+
+// Run delivers due events until ctx is done. A full batch is followed by the
+// next one right away, otherwise the outbox is polled again after the interval.
+func (s *dispatcherServiceImpl) Run(ctx context.Context) {
+	log := logger.GetLoggerContext(ctx, "service", "Run")
+
+	for {
+		count, err := s.DispatchPending(ctx)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to dispatch outbox events, err : %s", err.Error()))
+		}
+
+		wait := s.pollInterval
+		if err == nil && count == s.batchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// DispatchPending delivers one batch of due events and returns how many were claimed.
+func (s *dispatcherServiceImpl) DispatchPending(ctx context.Context) (int, error) {
+	log := logger.GetLoggerContext(ctx, "service", "DispatchPending")
+
+	events, err := s.outboxRepo.ClaimDue(time.Now(), s.batchSize, s.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		s.dispatch(ctx, event)
+
+		if event.Status == model.OutboxStatusDead {
+			err = s.bury(event)
+		} else {
+			err = s.outboxRepo.UpdateDelivery(event)
+		}
+		if err != nil {
+			// the lease expires and the event is delivered again
+			log.Error(fmt.Sprintf("failed to update outbox event %d, err : %s", event.ID, err.Error()))
+		}
+	}
+
+	return len(events), nil
+}
+
+// dispatch sends an event to every channel that has not received it yet and
+// updates its delivery state. Events about a customer skip the channels the
+// customer opted out of, and wait for the end of the customer's quiet hours
+// unless they are critical. Channels refusing to send because of their rate
+// limit or circuit breaker are tried again later without using up an attempt.
+func (s *dispatcherServiceImpl) dispatch(ctx context.Context, event *model.OutboxEvent) {
+	log := logger.GetLoggerContext(ctx, "service", "dispatch")
+
+	now := time.Now()
+
+	delivered := make(map[string]bool)
+	for _, name := range strings.Split(event.DeliveredChannels, ",") {
+		if name != "" {
+			delivered[name] = true
+		}
+	}
+
+	failures := make([]string, 0)
+	deferred := false
+	var retryAt time.Time
+
+	recipient, err := loadRecipient(s.customerRepo, s.preferenceRepo, event, now)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("preferences: %s", err.Error()))
+	}
+
+	for _, channel := range s.channels {
+		if err != nil || delivered[channel.Name()] {
+			continue
+		}
+
+		if recipient != nil && !recipient.allows(channel.Name()) {
+			continue
+		}
+
+		if recipient != nil && recipient.holds(channel.Name()) {
+			deferred = true
+			continue
+		}
+
+		err := channel.Send(ctx, event)
+		var unavailable *notifier.UnavailableError
+		if errors.As(err, &unavailable) {
+			log.Info(fmt.Sprintf("outbox event %d is held for %s, err : %s", event.ID, channel.Name(), err.Error()))
+			if retryAt.IsZero() || unavailable.RetryAt.Before(retryAt) {
+				retryAt = unavailable.RetryAt
+			}
+			continue
+		} else if err != nil {
+			log.Warn(fmt.Sprintf("failed to send outbox event %d to %s, err : %s", event.ID, channel.Name(), err.Error()))
+			failures = append(failures, fmt.Sprintf("%s: %s", channel.Name(), err.Error()))
+			continue
+		}
+
+		delivered[channel.Name()] = true
+		if event.DeliveredChannels != "" {
+			event.DeliveredChannels += ","
+		}
+		event.DeliveredChannels += channel.Name()
+	}
+
+	// quiet hours and unavailable channels do not use up an attempt
+	if len(failures) == 0 && (deferred || !retryAt.IsZero()) {
+		event.Status = model.OutboxStatusPending
+		if retryAt.IsZero() || (deferred && recipient.quietUntil.Before(retryAt)) {
+			retryAt = recipient.quietUntil
+		}
+		event.NextAttemptAt = retryAt
+		return
+	}
+
+	event.Attempts++
+
+	if len(failures) == 0 {
+		event.Status = model.OutboxStatusSent
+		event.SentAt = sql.NullTime{Time: now, Valid: true}
+		event.LastError = sql.NullString{}
+		return
+	}
+
+	lastError := truncate(strings.Join(failures, "; "), maxOutboxErrorLength)
+	event.LastError = sql.NullString{String: lastError, Valid: true}
+
+	if event.Attempts >= s.maxAttempts {
+		event.Status = model.OutboxStatusDead
+		log.Error(fmt.Sprintf("outbox event %d is dead after %d attempts, err : %s", event.ID, event.Attempts, lastError))
+		return
+	}
+
+	event.Status = model.OutboxStatusPending
+	event.NextAttemptAt = now.Add(s.backoff(event.Attempts))
+}
+
+// bury moves a dead event from the outbox to the dead letters.
+func (s *dispatcherServiceImpl) bury(event *model.OutboxEvent) error {
+	return s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		err := s.deadLetterRepo.CreateTx(tx, &model.DeadLetter{
+			EventID:           event.ID,
+			OriginEventID:     event.OriginEventID,
+			EventType:         event.EventType,
+			AggregateID:       event.AggregateID,
+			Payload:           event.Payload,
+			Attempts:          event.Attempts,
+			DeliveredChannels: event.DeliveredChannels,
+			LastError:         event.LastError,
+			Status:            model.DeadLetterStatusDead,
+			EventCreatedAt:    event.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.outboxRepo.DeleteTx(tx, event.ID)
+	})
+}
+
+// backoff returns the wait before the next attempt after the given number of failed attempts.
+func (s *dispatcherServiceImpl) backoff(attempts int64) time.Duration {
+	wait := s.retryBase
+	for i := int64(1); i < attempts && wait < s.retryMax; i++ {
+		wait *= 2
+	}
+
+	if wait > s.retryMax {
+		wait = s.retryMax
+	}
+	return wait
+}
+
+// writeOutboxEvent stores a domain event with its JSON payload inside the database
+// transaction of the change it describes.
+func writeOutboxEvent(tx *sqlx.Tx, repo repository.OutboxRepository, eventType model.OutboxEventType,
+	aggregateID string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return repo.CreateTx(tx, &model.OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     raw,
+		Status:      model.OutboxStatusPending,
+	})
+}
@@ -0,0 +1,314 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeChannel records the events it receives and fails while err is set.
+type fakeChannel struct {
+	name   string
+	err    error
+	events []*model.OutboxEvent
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.events = append(c.events, event)
+	return nil
+}
+
+func TestDispatchPending(t *testing.T) {
+	prepare()
+
+	newService := func(channels ...*fakeChannel) (*repoMock.OutboxRepository, service.DispatcherService) {
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		dispatcherService := service.NewDispatcherService().
+			SetOutboxRepo(mockOutboxRepo).
+			SetCustomerRepo(new(repoMock.CustomerRepository)).
+			SetPreferenceRepo(new(repoMock.NotificationPreferenceRepository)).
+			SetDeadLetterRepo(new(repoMock.DeadLetterRepository)).
+			SetTxRepo(new(repoMock.TxRepository)).
+			SetPollInterval(time.Second).
+			SetBatchSize(10, time.Minute).
+			SetRetry(3, 10*time.Second, 15*time.Second)
+		for _, channel := range channels {
+			dispatcherService.AddChannel(channel)
+		}
+		return mockOutboxRepo, dispatcherService.Validate()
+	}
+
+	// TestDispatchPendingErrorDatabase
+	func(t *testing.T) {
+		mockOutboxRepo, dispatcherService := newService(&fakeChannel{name: "log"})
+
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return(nil, errors.New("error"))
+		count, err := dispatcherService.DispatchPending(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 0, count)
+		mockOutboxRepo.AssertNumberOfCalls(t, "UpdateDelivery", 0)
+	}(t)
+
+	// TestDispatchPendingSuccess
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		webhook := &fakeChannel{name: "webhook"}
+		mockOutboxRepo, dispatcherService := newService(email, webhook)
+
+		// Case: a retried event only goes to the channels that have not received it
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending},
+			{ID: 2, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Attempts: 1, DeliveredChannels: "email"},
+		}, nil)
+		mockOutboxRepo.On("UpdateDelivery", mock.Anything).Return(nil)
+		count, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Len(t, email.events, 1)
+		assert.Len(t, webhook.events, 2)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.ID == 2 && event.Status == model.OutboxStatusSent && event.Attempts == 2 &&
+				event.DeliveredChannels == "email,webhook" && event.SentAt.Valid
+		}))
+	}(t)
+
+	// TestDispatchPendingRetry
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		webhook := &fakeChannel{name: "webhook", err: errors.New("timeout")}
+		mockOutboxRepo, dispatcherService := newService(email, webhook)
+
+		// Case: failed attempts wait longer each time, up to the maximum backoff
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending},
+			{ID: 2, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Attempts: 1},
+		}, nil)
+		mockOutboxRepo.On("UpdateDelivery", mock.Anything).Return(nil)
+		start := time.Now()
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			wait := event.NextAttemptAt.Sub(start)
+			return event.ID == 1 && event.Status == model.OutboxStatusPending && event.Attempts == 1 &&
+				event.DeliveredChannels == "email" && event.LastError.Valid && wait >= 10*time.Second && wait < 11*time.Second
+		}))
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			wait := event.NextAttemptAt.Sub(start)
+			return event.ID == 2 && event.Attempts == 2 && wait >= 15*time.Second && wait < 16*time.Second
+		}))
+	}(t)
+
+	// TestDispatchPendingUnavailable
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		retryAt := time.Now().Add(time.Minute)
+		sms := &fakeChannel{name: "sms", err: &notifier.UnavailableError{Name: "sms", Reason: notifier.ErrCircuitOpen, RetryAt: retryAt}}
+		mockOutboxRepo, dispatcherService := newService(email, sms)
+
+		// Case: a channel that refuses to send holds the event without using up an attempt
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Attempts: 2},
+		}, nil)
+		mockOutboxRepo.On("UpdateDelivery", mock.Anything).Return(nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.ID == 1 && event.Status == model.OutboxStatusPending && event.Attempts == 2 &&
+				event.DeliveredChannels == "email" && event.NextAttemptAt.Equal(retryAt)
+		}))
+	}(t)
+
+	// TestDispatchPendingDead
+	func(t *testing.T) {
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockDeadLetterRepo := new(repoMock.DeadLetterRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		dispatcherService := service.NewDispatcherService().
+			SetOutboxRepo(mockOutboxRepo).
+			SetCustomerRepo(new(repoMock.CustomerRepository)).
+			SetPreferenceRepo(new(repoMock.NotificationPreferenceRepository)).
+			SetDeadLetterRepo(mockDeadLetterRepo).
+			SetTxRepo(mockTxRepo).
+			AddChannel(&fakeChannel{name: "email"}).
+			AddChannel(&fakeChannel{name: "webhook", err: errors.New("timeout")}).
+			SetPollInterval(time.Second).
+			SetBatchSize(10, time.Minute).
+			SetRetry(3, 10*time.Second, 15*time.Second).
+			Validate()
+
+		// Case: the last attempt fails and the event is moved to the dead letters
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Payload: []byte(`{"order_id":"ORDER-1"}`), Status: model.OutboxStatusPending, Attempts: 2},
+		}, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockDeadLetterRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOutboxRepo.On("DeleteTx", mock.Anything, int64(1)).Return(nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+
+		mockDeadLetterRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(letter *model.DeadLetter) bool {
+			return letter.EventID == 1 && letter.Status == model.DeadLetterStatusDead && letter.Attempts == 3 &&
+				letter.LastError.String == "webhook: timeout" && letter.DeliveredChannels == "email" &&
+				string(letter.Payload) == `{"order_id":"ORDER-1"}`
+		}))
+		mockOutboxRepo.AssertNumberOfCalls(t, "DeleteTx", 1)
+		mockOutboxRepo.AssertNotCalled(t, "UpdateDelivery", mock.Anything)
+	}(t)
+}
+
+func TestDispatchPreferences(t *testing.T) {
+	prepare()
+
+	newService := func(channels ...*fakeChannel) (*repoMock.OutboxRepository, *repoMock.CustomerRepository, *repoMock.NotificationPreferenceRepository, service.DispatcherService) {
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		mockPreferenceRepo := new(repoMock.NotificationPreferenceRepository)
+		dispatcherService := service.NewDispatcherService().
+			SetOutboxRepo(mockOutboxRepo).
+			SetCustomerRepo(mockCustomerRepo).
+			SetPreferenceRepo(mockPreferenceRepo).
+			SetDeadLetterRepo(new(repoMock.DeadLetterRepository)).
+			SetTxRepo(new(repoMock.TxRepository)).
+			SetPollInterval(time.Second).
+			SetBatchSize(10, time.Minute).
+			SetRetry(3, 10*time.Second, 15*time.Second)
+		for _, channel := range channels {
+			dispatcherService.AddChannel(channel)
+		}
+		mockOutboxRepo.On("UpdateDelivery", mock.Anything).Return(nil)
+		return mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService.Validate()
+	}
+
+	// quiet hours from an hour ago to an hour from now in Jakarta
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Now().In(location)
+	quietCustomer := &model.Customer{
+		ID:              7,
+		Timezone:        "Asia/Jakarta",
+		QuietHoursStart: sql.NullString{String: now.Add(-time.Hour).Format("15:04"), Valid: true},
+		QuietHoursEnd:   sql.NullString{String: now.Add(time.Hour).Format("15:04"), Valid: true},
+	}
+
+	// TestDispatchPreferencesOptOut
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		webhook := &fakeChannel{name: "webhook"}
+		logChannel := &fakeChannel{name: "log"}
+		mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService := newService(email, webhook, logChannel)
+
+		// Case: opted out channels are skipped, partner webhooks are not the customer's to opt out of
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Payload: []byte(`{"order_id":"ORD-001","customer_id":7}`)},
+		}, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{
+			{CustomerID: 7, Channel: model.NotificationChannelEmail, Category: model.NotificationCategoryTransactional, Enabled: false},
+			{CustomerID: 7, Channel: model.NotificationChannelWebhook, Category: model.NotificationCategoryTransactional, Enabled: false},
+		}, nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{ID: 7, Timezone: "UTC"}, nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, email.events, 0)
+		assert.Len(t, webhook.events, 1)
+		assert.Len(t, logChannel.events, 1)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.Status == model.OutboxStatusSent && event.DeliveredChannels == "webhook,log"
+		}))
+	}(t)
+
+	// TestDispatchPreferencesQuietHours
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		logChannel := &fakeChannel{name: "log"}
+		mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService := newService(email, logChannel)
+
+		// Case: the event waits for the end of quiet hours without using an attempt
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Payload: []byte(`{"order_id":"ORD-001","customer_id":7}`)},
+		}, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{}, nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(quietCustomer, nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, email.events, 0)
+		assert.Len(t, logChannel.events, 1)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			wait := time.Until(event.NextAttemptAt)
+			return event.Status == model.OutboxStatusPending && event.Attempts == 0 &&
+				event.DeliveredChannels == "log" && wait > 58*time.Minute && wait <= time.Hour
+		}))
+	}(t)
+
+	// TestDispatchPreferencesPartnerWebhook
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		webhook := &fakeChannel{name: "webhook"}
+		mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService := newService(email, webhook)
+
+		// Case: partners receive the event during the customer's quiet hours
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCreated, Status: model.OutboxStatusPending, Payload: []byte(`{"order_id":"ORD-001","customer_id":7}`)},
+		}, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{}, nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(quietCustomer, nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, email.events, 0)
+		assert.Len(t, webhook.events, 1)
+
+		mockOutboxRepo.AssertCalled(t, "UpdateDelivery", mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.Status == model.OutboxStatusPending && event.DeliveredChannels == "webhook"
+		}))
+
+		// Case: marketing events reach partners although customers are opted out by default
+		email = &fakeChannel{name: "email"}
+		webhook = &fakeChannel{name: "webhook"}
+		mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService = newService(email, webhook)
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 2, EventType: model.OutboxEventCartReminder, Status: model.OutboxStatusPending, Payload: []byte(`{"cart_id":"cart-test","customer_id":7}`)},
+		}, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{}, nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{ID: 7, Timezone: "UTC"}, nil)
+		_, err = dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, email.events, 0)
+		assert.Len(t, webhook.events, 1)
+	}(t)
+
+	// TestDispatchPreferencesCritical
+	func(t *testing.T) {
+		email := &fakeChannel{name: "email"}
+		mockOutboxRepo, mockCustomerRepo, mockPreferenceRepo, dispatcherService := newService(email)
+
+		// Case: critical events are sent during quiet hours
+		mockOutboxRepo.On("ClaimDue", mock.Anything, 10, time.Minute).Return([]*model.OutboxEvent{
+			{ID: 1, EventType: model.OutboxEventOrderCancelled, Status: model.OutboxStatusPending, Payload: []byte(`{"order_id":"ORD-001","customer_id":7}`)},
+		}, nil)
+		mockPreferenceRepo.On("GetByCustomerID", int64(7)).Return([]*model.NotificationPreference{}, nil)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(quietCustomer, nil)
+		_, err := dispatcherService.DispatchPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, email.events, 1)
+	}(t)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+)
+
+// emailData is the data email templates are rendered with.
+type emailData struct {
+	Customer *model.Customer
+	Order    model.GetTranscationDetailResponse
+	Event    model.OrderEvent
+	Reminder model.ReminderEvent
+}
+
+// EmailChannel emails the customer of an order about the order events that have
+// an email template. Events without a customer, or whose customer has no email
+// address, are skipped.
+type EmailChannel struct {
+	customerRepo       repository.CustomerRepository
+	transactionService TransactionService
+	mailer             *notifier.SMTPMailer
+	templates          *notifier.EmailTemplates
+}
+
+// NewEmailChannel returns new instance of EmailChannel.
+func NewEmailChannel() *EmailChannel {
+	return &EmailChannel{}
+}
+
+// SetCustomerRepo injects customer's repo for EmailChannel.
+func (c *EmailChannel) SetCustomerRepo(repo repository.CustomerRepository) *EmailChannel {
+	c.customerRepo = repo
+	return c
+}
+
+// SetTransactionService injects transaction's service for EmailChannel.
+func (c *EmailChannel) SetTransactionService(service TransactionService) *EmailChannel {
+	c.transactionService = service
+	return c
+}
+
+// SetMailer sets the client sending emails.
+func (c *EmailChannel) SetMailer(mailer *notifier.SMTPMailer) *EmailChannel {
+	c.mailer = mailer
+	return c
+}
+
+// SetTemplates sets the templates emails are rendered from.
+func (c *EmailChannel) SetTemplates(templates *notifier.EmailTemplates) *EmailChannel {
+	c.templates = templates
+	return c
+}
+
+// Validate validates if all dependency for EmailChannel is complete.
+func (c *EmailChannel) Validate() *EmailChannel {
+	if c.customerRepo == nil {
+		log.Panic("Email channel need customer repository")
+	}
+	if c.transactionService == nil {
+		log.Panic("Email channel need transaction service")
+	}
+	if c.mailer == nil {
+		log.Panic("Email channel need mailer")
+	}
+	if c.templates == nil {
+		log.Panic("Email channel need templates")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+// Send renders the email of the event in the customer's locale and sends it.
+func (c *EmailChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	if !c.templates.Has("", string(event.EventType)) {
+		return nil
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Send")
+
+	var payload model.OrderEvent
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode order event %d, err : %s", event.ID, err.Error()))
+		return nil
+	}
+
+	if payload.CustomerID == 0 {
+		return nil
+	}
+
+	customer, err := c.customerRepo.GetByID(payload.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil || !customer.Email.Valid {
+		return nil
+	}
+
+	httpCode, resp := c.transactionService.GetDetail(ctx, payload.OrderID)
+	if httpCode != http.StatusOK {
+		return fmt.Errorf("failed to get order %s, status : %d", payload.OrderID, httpCode)
+	}
+
+	order, ok := resp.ResultData.(model.GetTranscationDetailResponse)
+	if !ok {
+		return fmt.Errorf("unexpected order detail of %s", payload.OrderID)
+	}
+
+	data := emailData{
+		Customer: customer,
+		Order:    order,
+		Event:    payload,
+	}
+	if event.EventType == model.OutboxEventOrderReminder {
+		json.Unmarshal(event.Payload, &data.Reminder)
+	}
+
+	email, err := c.templates.Render(customer.Locale, string(event.EventType), customer.Email.String, data)
+	if err != nil {
+		return err
+	}
+
+	return c.mailer.Send(ctx, email)
+}
@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/pkg/notifier/smtptest"
+	"github.com/richardsahvic/jamtangan/service"
+	serviceMock "github.com/richardsahvic/jamtangan/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailChannel(t *testing.T) {
+	prepare()
+
+	server, err := smtptest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	templates, err := notifier.LoadEmailTemplates("../template/email", "en")
+	assert.Nil(t, err)
+
+	order := model.GetTranscationDetailResponse{
+		OrderID: "ORD-001",
+		Status:  model.OrderStatusPending,
+		Items: []model.TransactionItem{
+			{SKU: "SKU-001", Quantity: 2, Subtotal: model.NewMoney(100000)},
+		},
+		TotalAmount: model.NewMoney(100000),
+		CreatedAt:   time.Date(2022, 2, 9, 10, 0, 0, 0, time.UTC),
+	}
+	payload, _ := json.Marshal(model.OrderEvent{OrderID: "ORD-001", CustomerID: 7, Status: model.OrderStatusPending})
+
+	// TestEmailChannelSend
+	func(t *testing.T) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		mockTransactionService := new(serviceMock.TransactionService)
+		channel := service.NewEmailChannel().
+			SetCustomerRepo(mockCustomerRepo).
+			SetTransactionService(mockTransactionService).
+			SetMailer(notifier.NewSMTPMailer(server.Host(), server.Port(), "user", "secret", "shop@example.com", time.Second)).
+			SetTemplates(templates).
+			Validate()
+
+		// Case: email is rendered in the customer's locale with both parts
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{
+			ID:     7,
+			Email:  sql.NullString{String: "budi@example.com", Valid: true},
+			Locale: "id",
+		}, nil)
+		mockTransactionService.On("GetDetail", context.Background(), "ORD-001").
+			Return(http.StatusOK, &model.BaseResponse{ResultData: order})
+
+		event := &model.OutboxEvent{ID: 1, EventType: model.OutboxEventOrderCreated, Payload: payload}
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		messages := server.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, messages[0].From, "shop@example.com", time.Second)
+		assert.Equal(t, messages[0].To, []string{"budi@example.com"})
+		assert.Contains(t, messages[0].Data, "Subject: Pesanan ORD-001 telah diterima")
+		assert.Contains(t, messages[0].Data, "Content-Type: text/plain; charset=UTF-8")
+		assert.Contains(t, messages[0].Data, "Content-Type: text/html; charset=UTF-8")
+		assert.Contains(t, messages[0].Data, "SKU-001")
+	}(t)
+
+	// TestEmailChannelSkip
+	func(t *testing.T) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		channel := service.NewEmailChannel().
+			SetCustomerRepo(mockCustomerRepo).
+			SetTransactionService(new(serviceMock.TransactionService)).
+			SetMailer(notifier.NewSMTPMailer(server.Host(), server.Port(), "", "", "shop@example.com", time.Second)).
+			SetTemplates(templates).
+			Validate()
+		sent := len(server.Messages())
+
+		// Case: event without template
+		event := &model.OutboxEvent{ID: 2, EventType: model.OutboxEventProductCreated, Payload: []byte(`{}`)}
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		// Case: customer without email
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{ID: 7, Locale: "en"}, nil)
+		event = &model.OutboxEvent{ID: 3, EventType: model.OutboxEventOrderCreated, Payload: payload}
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		assert.Len(t, server.Messages(), sent)
+	}(t)
+
+	// TestEmailChannelTimeout
+	func(t *testing.T) {
+		// a server that accepts connections and never greets
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		defer listener.Close()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		mockTransactionService := new(serviceMock.TransactionService)
+		channel := service.NewEmailChannel().
+			SetCustomerRepo(mockCustomerRepo).
+			SetTransactionService(mockTransactionService).
+			SetMailer(notifier.NewSMTPMailer(host, port, "", "", "shop@example.com", 100*time.Millisecond)).
+			SetTemplates(templates).
+			Validate()
+
+		// Case: a stalled server fails the send after the timeout
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{
+			ID:     7,
+			Email:  sql.NullString{String: "budi@example.com", Valid: true},
+			Locale: "en",
+		}, nil)
+		mockTransactionService.On("GetDetail", context.Background(), "ORD-001").
+			Return(http.StatusOK, &model.BaseResponse{ResultData: order})
+
+		start := time.Now()
+		event := &model.OutboxEvent{ID: 4, EventType: model.OutboxEventOrderCreated, Payload: payload}
+		err = channel.Send(context.Background(), event)
+		assert.Error(t, err)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	}(t)
+}
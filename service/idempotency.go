@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// maxIdempotencyKeyLength is the longest idempotency key that can be stored.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyService manage logical syntax for idempotency key.
+type IdempotencyService interface {
+	Execute(ctx context.Context, key string, request interface{}, fn func() (int, *model.BaseResponse)) (int, *model.BaseResponse)
+	Run(ctx context.Context)
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type idempotencyServiceImpl struct {
+	idempotencyRepo repository.IdempotencyRepository
+	keyTTL          time.Duration
+	lockTimeout     time.Duration
+	purgeInterval   time.Duration
+	purgeBatchSize  int
+}
+
+// storedResponse is the serialized form of a response kept with an idempotency key.
+type storedResponse struct {
+	RawMessage string          `json:"raw_message"`
+	ResultData json.RawMessage `json:"data"`
+}
+
+// NewIdempotencyService returns new instance of idempotencyServiceImpl.
+func NewIdempotencyService() *idempotencyServiceImpl {
+	return &idempotencyServiceImpl{}
+}
+
+// SetIdempotencyRepo injects idempotency's repo for idempotencyServiceImpl.
+func (s *idempotencyServiceImpl) SetIdempotencyRepo(repo repository.IdempotencyRepository) *idempotencyServiceImpl {
+	s.idempotencyRepo = repo
+	return s
+}
+
+// SetKeyTTL sets how long an idempotency key is kept before it can be reused.
+func (s *idempotencyServiceImpl) SetKeyTTL(ttl time.Duration) *idempotencyServiceImpl {
+	s.keyTTL = ttl
+	return s
+}
+
+// SetLockTimeout sets how long a request holds its key before a retry may take it
+// over, it has to be longer than the slowest request.
+func (s *idempotencyServiceImpl) SetLockTimeout(timeout time.Duration) *idempotencyServiceImpl {
+	s.lockTimeout = timeout
+	return s
+}
+
+// SetPurge sets how often expired keys are deleted and how many are deleted at once.
+func (s *idempotencyServiceImpl) SetPurge(interval time.Duration, batchSize int) *idempotencyServiceImpl {
+	s.purgeInterval = interval
+	s.purgeBatchSize = batchSize
+	return s
+}
+
+// Validate validates if all dependency for idempotencyServiceImpl is complete.
+func (s *idempotencyServiceImpl) Validate() *idempotencyServiceImpl {
+	if s.idempotencyRepo == nil {
+		log.Panic("Idempotency service need idempotency repository")
+	}
+	if s.keyTTL <= 0 {
+		log.Panic("Idempotency service need key TTL")
+	}
+	if s.lockTimeout <= 0 {
+		log.Panic("Idempotency service need lock timeout")
+	}
+	if s.purgeInterval <= 0 || s.purgeBatchSize <= 0 {
+		log.Panic("Idempotency service need purge interval and batch size")
+	}
+	return s
+}
+
+// Execute runs fn once per idempotency key. A retry with the same key and request
+// gets the stored response of the first request, while a retry with a different
+// request is rejected. Requests are compared decoded, so a retry encoding the
+// same request differently is still a retry. Server errors are not stored so the
+// request can be retried, and a retry takes over a key whose request stopped
+// without storing a response once its lock timeout is over.
+func (s *idempotencyServiceImpl) Execute(ctx context.Context, key string, request interface{},
+	fn func() (int, *model.BaseResponse)) (int, *model.BaseResponse) {
+	// validate request
+	if len(key) > maxIdempotencyKeyLength {
+		return utils.RequestInvalid("Idempotency-Key")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Execute")
+
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to fingerprint request, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	existing, err := s.idempotencyRepo.GetByKey(key)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get idempotency key, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if existing != nil && time.Now().After(existing.ExpiresAt) {
+		err = s.idempotencyRepo.Delete(key)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to delete expired idempotency key, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+		existing = nil
+	}
+
+	if existing != nil && (existing.Fingerprint != fingerprint || existing.ResponseCode.Valid) {
+		return s.replay(existing, fingerprint)
+	}
+
+	now := time.Now()
+	lockedUntil := now.Add(s.lockTimeout)
+
+	var claimed bool
+	if existing != nil {
+		// the request holding the key may have stopped before storing its response
+		claimed, err = s.idempotencyRepo.TakeOver(key, fingerprint, lockedUntil, now)
+	} else {
+		claimed, err = s.idempotencyRepo.Create(&model.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			LockedUntil: sql.NullTime{Time: lockedUntil, Valid: true},
+			ExpiresAt:   now.Add(s.keyTTL),
+		})
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to claim idempotency key, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	// another request with the same key is running
+	if !claimed {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "request with this Idempotency-Key is still in progress"}
+	}
+
+	httpCode, resp := fn()
+
+	if httpCode >= http.StatusInternalServerError {
+		err = s.idempotencyRepo.Delete(key)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to release idempotency key, err : %s", err.Error()))
+		}
+		return httpCode, resp
+	}
+
+	body, err := json.Marshal(resp)
+	if err == nil {
+		err = s.idempotencyRepo.SaveResponse(key, httpCode, string(body))
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to save idempotency key response, err : %s", err.Error()))
+	}
+
+	return httpCode, resp
+}
+
+// Run deletes expired keys until ctx is done.
+func (s *idempotencyServiceImpl) Run(ctx context.Context) {
+	log := logger.GetLoggerContext(ctx, "service", "Run")
+
+	for {
+		count, err := s.PurgeExpired(ctx)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to purge idempotency keys, err : %s", err.Error()))
+		}
+
+		wait := s.purgeInterval
+		if err == nil && count == s.purgeBatchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// PurgeExpired deletes one batch of expired keys and returns how many were deleted.
+func (s *idempotencyServiceImpl) PurgeExpired(ctx context.Context) (int, error) {
+	purged, err := s.idempotencyRepo.PurgeExpired(time.Now(), s.purgeBatchSize)
+	return int(purged), err
+}
+
+// replay returns the stored response of an idempotency key.
+func (s *idempotencyServiceImpl) replay(existing *model.IdempotencyKey, fingerprint string) (int, *model.BaseResponse) {
+	if existing.Fingerprint != fingerprint {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "Idempotency-Key is already used for a different request"}
+	}
+
+	if !existing.ResponseCode.Valid {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "request with this Idempotency-Key is still in progress"}
+	}
+
+	var stored storedResponse
+	err := json.Unmarshal([]byte(existing.ResponseBody.String), &stored)
+	if err != nil {
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := &model.BaseResponse{RawMessage: stored.RawMessage}
+	if len(stored.ResultData) > 0 && string(stored.ResultData) != "null" {
+		resp.ResultData = stored.ResultData
+	}
+
+	return int(existing.ResponseCode.Int64), resp
+}
+
+// requestFingerprint returns the hash of the JSON encoding of a decoded request.
+func requestFingerprint(request interface{}) (string, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,270 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func fingerprint(request interface{}) string {
+	body, _ := json.Marshal(request)
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestIdempotencyExecute(t *testing.T) {
+	prepare()
+
+	request := model.CreateTransactionRequest{
+		Items: []model.TransactionItem{{SKU: "sku-test", Quantity: 1}},
+	}
+
+	// TestIdempotencyExecuteInvalidKey
+	func(t *testing.T) {
+		idempotencyService := service.NewIdempotencyService()
+
+		httpCode, resp := idempotencyService.Execute(context.Background(), strings.Repeat("k", 256), request,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestIdempotencyExecuteFirstRequest
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour).
+			SetLockTimeout(time.Minute)
+
+		mockIdempotencyRepo.On("GetByKey", "key").Return(nil, nil)
+		mockIdempotencyRepo.On("Create", mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+			return key.Key == "key" && key.Fingerprint == fingerprint(request) &&
+				key.LockedUntil.Valid && key.LockedUntil.Time.After(time.Now())
+		})).Return(true, nil)
+		mockIdempotencyRepo.On("SaveResponse", "key", http.StatusOK, mock.Anything).Return(nil)
+
+		executed := 0
+		httpCode, resp := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				executed++
+				return http.StatusOK, &model.BaseResponse{ResultData: model.CreateTransactionResponse{OrderID: "orderID"}}
+			})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.NotNil(t, resp.ResultData)
+		assert.Equal(t, 1, executed)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "SaveResponse", 1)
+	}(t)
+
+	// TestIdempotencyExecuteReplay
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour)
+
+		stored, _ := json.Marshal(&model.BaseResponse{ResultData: model.CreateTransactionResponse{OrderID: "orderID"}})
+		mockIdempotencyRepo.On("GetByKey", "key").Return(&model.IdempotencyKey{
+			Key:          "key",
+			Fingerprint:  fingerprint(request),
+			ResponseCode: sql.NullInt64{Int64: http.StatusOK, Valid: true},
+			ResponseBody: sql.NullString{String: string(stored), Valid: true},
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}, nil)
+
+		httpCode, resp := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		replayed, _ := json.Marshal(resp)
+		assert.JSONEq(t, string(stored), string(replayed))
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "Create", 0)
+	}(t)
+
+	// TestIdempotencyExecuteReencoded
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour)
+
+		stored, _ := json.Marshal(&model.BaseResponse{ResultData: model.CreateTransactionResponse{OrderID: "orderID"}})
+		mockIdempotencyRepo.On("GetByKey", "key").Return(&model.IdempotencyKey{
+			Key:          "key",
+			Fingerprint:  fingerprint(request),
+			ResponseCode: sql.NullInt64{Int64: http.StatusOK, Valid: true},
+			ResponseBody: sql.NullString{String: string(stored), Valid: true},
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}, nil)
+
+		// Case: a retry with other whitespace and key order is replayed
+		var retry model.CreateTransactionRequest
+		json.Unmarshal([]byte(`{ "items": [ { "quantity": 1, "sku": "sku-test" } ] }`), &retry)
+		httpCode, _ := idempotencyService.Execute(context.Background(), "key", retry,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "Create", 0)
+	}(t)
+
+	// TestIdempotencyExecuteDifferentRequest
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour)
+
+		mockIdempotencyRepo.On("GetByKey", "key").Return(&model.IdempotencyKey{
+			Key:          "key",
+			Fingerprint:  fingerprint(model.CreateTransactionRequest{}),
+			ResponseCode: sql.NullInt64{Int64: http.StatusOK, Valid: true},
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}, nil)
+
+		httpCode, resp := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestIdempotencyExecuteInProgress
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour)
+
+		// Case: another request with the same key has just been started
+		mockIdempotencyRepo.On("GetByKey", "key").Return(nil, nil)
+		mockIdempotencyRepo.On("Create", mock.Anything).Return(false, nil)
+
+		httpCode, resp := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+
+		// Case: the request holding the key is still within its lock
+		mockIdempotencyRepo.On("GetByKey", "key-locked").Return(&model.IdempotencyKey{
+			Key:         "key-locked",
+			Fingerprint: fingerprint(request),
+			LockedUntil: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}, nil)
+		mockIdempotencyRepo.On("TakeOver", "key-locked", fingerprint(request), mock.Anything, mock.Anything).Return(false, nil)
+
+		httpCode, resp = idempotencyService.Execute(context.Background(), "key-locked", request,
+			func() (int, *model.BaseResponse) {
+				t.Fatal("request should not be executed")
+				return 0, nil
+			})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, "request with this Idempotency-Key is still in progress", resp.RawMessage)
+	}(t)
+
+	// TestIdempotencyExecuteTakeOver
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour).
+			SetLockTimeout(time.Minute)
+
+		// Case: a key left without response after its lock is over is taken over by the retry
+		mockIdempotencyRepo.On("GetByKey", "key").Return(&model.IdempotencyKey{
+			Key:         "key",
+			Fingerprint: fingerprint(request),
+			LockedUntil: sql.NullTime{Time: time.Now().Add(-time.Second), Valid: true},
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}, nil)
+		mockIdempotencyRepo.On("TakeOver", "key", fingerprint(request), mock.MatchedBy(func(lockedUntil time.Time) bool {
+			return lockedUntil.After(time.Now().Add(50 * time.Second))
+		}), mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockIdempotencyRepo.On("SaveResponse", "key", http.StatusOK, mock.Anything).Return(nil)
+
+		executed := 0
+		httpCode, _ := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				executed++
+				return http.StatusOK, &model.BaseResponse{ResultData: model.CreateTransactionResponse{OrderID: "orderID"}}
+			})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, 1, executed)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "Create", 0)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "SaveResponse", 1)
+	}(t)
+
+	// TestIdempotencyExecuteExpired
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour)
+
+		// Case: expired key is released and the failed request is not stored
+		mockIdempotencyRepo.On("GetByKey", "key").Return(&model.IdempotencyKey{
+			Key:         "key",
+			Fingerprint: fingerprint(model.CreateTransactionRequest{}),
+			ExpiresAt:   time.Now().Add(-time.Minute),
+		}, nil)
+		mockIdempotencyRepo.On("Delete", "key").Return(nil)
+		mockIdempotencyRepo.On("Create", mock.Anything).Return(true, nil)
+
+		httpCode, _ := idempotencyService.Execute(context.Background(), "key", request,
+			func() (int, *model.BaseResponse) {
+				return http.StatusInternalServerError, &model.BaseResponse{RawMessage: "error"}
+			})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "Delete", 2)
+		mockIdempotencyRepo.AssertNumberOfCalls(t, "SaveResponse", 0)
+	}(t)
+}
+
+func TestIdempotencyPurgeExpired(t *testing.T) {
+	prepare()
+
+	// TestIdempotencyPurgeExpiredSuccess
+	func(t *testing.T) {
+		mockIdempotencyRepo := new(repoMock.IdempotencyRepository)
+		idempotencyService := service.NewIdempotencyService().
+			SetIdempotencyRepo(mockIdempotencyRepo).
+			SetKeyTTL(time.Hour).
+			SetLockTimeout(time.Minute).
+			SetPurge(time.Minute, 100).
+			Validate()
+
+		// Case: one batch of expired keys is deleted
+		mockIdempotencyRepo.On("PurgeExpired", mock.MatchedBy(func(now time.Time) bool {
+			return !now.After(time.Now())
+		}), 100).Return(int64(3), nil)
+		count, err := idempotencyService.PurgeExpired(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 3, count)
+	}(t)
+}
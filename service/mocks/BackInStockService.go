@@ -0,0 +1,84 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BackInStockService is an autogenerated mock type for the BackInStockService type
+type BackInStockService struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, customerID
+func (_m *BackInStockService) List(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, customerID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: ctx, request
+func (_m *BackInStockService) Subscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.BackInStockRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.BackInStockRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Unsubscribe provides a mock function with given fields: ctx, request
+func (_m *BackInStockService) Unsubscribe(ctx context.Context, request model.BackInStockRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.BackInStockRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.BackInStockRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
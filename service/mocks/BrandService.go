@@ -36,3 +36,118 @@ func (_m *BrandService) Create(ctx context.Context, request model.CreateBrandReq
 
 	return r0, r1
 }
+
+// Delete provides a mock function with given fields: ctx, request
+func (_m *BrandService) Delete(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.DeleteBrandRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.DeleteBrandRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, brandID
+func (_m *BrandService) GetByID(ctx context.Context, brandID string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, brandID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, brandID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, brandID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, request
+func (_m *BrandService) List(ctx context.Context, request model.ListBrandRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListBrandRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListBrandRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, request
+func (_m *BrandService) Restore(ctx context.Context, request model.DeleteBrandRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.DeleteBrandRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.DeleteBrandRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, request
+func (_m *BrandService) Update(ctx context.Context, request model.UpdateBrandRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.UpdateBrandRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.UpdateBrandRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
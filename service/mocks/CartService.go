@@ -0,0 +1,153 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CartService is an autogenerated mock type for the CartService type
+type CartService struct {
+	mock.Mock
+}
+
+// AddItem provides a mock function with given fields: ctx, request
+func (_m *CartService) AddItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.CartItemRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.CartItemRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Checkout provides a mock function with given fields: ctx, request
+func (_m *CartService) Checkout(ctx context.Context, request model.CheckoutCartRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.CheckoutCartRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.CheckoutCartRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, request
+func (_m *CartService) Create(ctx context.Context, request model.CreateCartRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.CreateCartRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.CreateCartRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, cartID
+func (_m *CartService) Get(ctx context.Context, cartID string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, cartID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, cartID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, cartID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveItem provides a mock function with given fields: ctx, cartID, sku
+func (_m *CartService) RemoveItem(ctx context.Context, cartID string, sku string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, cartID, sku)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, cartID, sku)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, cartID, sku)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateItem provides a mock function with given fields: ctx, request
+func (_m *CartService) UpdateItem(ctx context.Context, request model.CartItemRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.CartItemRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.CartItemRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
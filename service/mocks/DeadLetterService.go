@@ -0,0 +1,107 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetterService is an autogenerated mock type for the DeadLetterService type
+type DeadLetterService struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *DeadLetterService) Get(ctx context.Context, id string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, request
+func (_m *DeadLetterService) List(ctx context.Context, request model.ListDeadLetterRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListDeadLetterRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListDeadLetterRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, request
+func (_m *DeadLetterService) Purge(ctx context.Context, request model.PurgeDeadLetterRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.PurgeDeadLetterRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.PurgeDeadLetterRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Retry provides a mock function with given fields: ctx, request
+func (_m *DeadLetterService) Retry(ctx context.Context, request model.RetryDeadLetterRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.RetryDeadLetterRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.RetryDeadLetterRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.10.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/richardsahvic/jamtangan/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx, key, request, fn
+func (_m *IdempotencyService) Execute(ctx context.Context, key string, request interface{}, fn func() (int, *model.BaseResponse)) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, key, request, fn)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, func() (int, *model.BaseResponse)) int); ok {
+		r0 = rf(ctx, key, request, fn)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string, interface{}, func() (int, *model.BaseResponse)) *model.BaseResponse); ok {
+		r1 = rf(ctx, key, request, fn)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeExpired provides a mock function with given fields: ctx
+func (_m *IdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *IdempotencyService) Run(ctx context.Context) {
+	_m.Called(ctx)
+}
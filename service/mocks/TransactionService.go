@@ -14,6 +14,29 @@ type TransactionService struct {
 	mock.Mock
 }
 
+// Cancel provides a mock function with given fields: ctx, request
+func (_m *TransactionService) Cancel(ctx context.Context, request model.CancelOrderRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.CancelOrderRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.CancelOrderRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, request
 func (_m *TransactionService) Create(ctx context.Context, request model.CreateTransactionRequest) (int, *model.BaseResponse) {
 	ret := _m.Called(ctx, request)
@@ -37,6 +60,29 @@ func (_m *TransactionService) Create(ctx context.Context, request model.CreateTr
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, orderID
+func (_m *TransactionService) Delete(ctx context.Context, orderID string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetDetail provides a mock function with given fields: ctx, orderID
 func (_m *TransactionService) GetDetail(ctx context.Context, orderID string) (int, *model.BaseResponse) {
 	ret := _m.Called(ctx, orderID)
@@ -59,3 +105,72 @@ func (_m *TransactionService) GetDetail(ctx context.Context, orderID string) (in
 
 	return r0, r1
 }
+
+// List provides a mock function with given fields: ctx, request
+func (_m *TransactionService) List(ctx context.Context, request model.ListOrderRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListOrderRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListOrderRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, orderID
+func (_m *TransactionService) Restore(ctx context.Context, orderID string) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, string) *model.BaseResponse); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, request
+func (_m *TransactionService) UpdateStatus(ctx context.Context, request model.UpdateOrderStatusRequest) (int, *model.BaseResponse) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, model.UpdateOrderStatusRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 *model.BaseResponse
+	if rf, ok := ret.Get(1).(func(context.Context, model.UpdateOrderStatusRequest) *model.BaseResponse); ok {
+		r1 = rf(ctx, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.BaseResponse)
+		}
+	}
+
+	return r0, r1
+}
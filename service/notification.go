@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+// NotificationService manage logical syntax for the in-app notification inbox.
+type NotificationService interface {
+	List(ctx context.Context, request model.ListNotificationRequest) (int, *model.BaseResponse)
+	UnreadCount(ctx context.Context, customerID string) (int, *model.BaseResponse)
+	MarkRead(ctx context.Context, request model.MarkNotificationReadRequest) (int, *model.BaseResponse)
+	Delete(ctx context.Context, customerID string, notificationID string) (int, *model.BaseResponse)
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService returns new instance of notificationServiceImpl.
+func NewNotificationService() *notificationServiceImpl {
+	return &notificationServiceImpl{}
+}
+
+// SetNotificationRepo injects notification's repo for notificationServiceImpl.
+func (s *notificationServiceImpl) SetNotificationRepo(repo repository.NotificationRepository) *notificationServiceImpl {
+	s.notificationRepo = repo
+	return s
+}
+
+// Validate validates if all dependency for notificationServiceImpl is complete.
+func (s *notificationServiceImpl) Validate() *notificationServiceImpl {
+	if s.notificationRepo == nil {
+		log.Panic("Notification service need notification repository")
+	}
+	return s
+}
+
+// List returns the inbox of a customer, newest first, with the unread count.
+// The next page is requested with the next_cursor of the previous page.
+func (s *notificationServiceImpl) List(ctx context.Context, request model.ListNotificationRequest) (int, *model.BaseResponse) {
+	// validate request
+	var err error
+	filter := model.NotificationFilter{
+		Limit: defaultNotificationLimit,
+	}
+
+	if strings.TrimSpace(request.CustomerID) == "" {
+		return utils.RequestRequired("customer_id")
+	}
+
+	filter.CustomerID, err = strconv.ParseInt(request.CustomerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	if request.UnreadOnly != "" {
+		filter.UnreadOnly, err = strconv.ParseBool(request.UnreadOnly)
+		if err != nil {
+			return utils.RequestInvalid("unread")
+		}
+	}
+
+	if request.Limit != "" {
+		filter.Limit, err = strconv.Atoi(request.Limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > maxNotificationLimit {
+			return utils.RequestInvalid("limit")
+		}
+	}
+
+	if request.Cursor != "" {
+		_, filter.BeforeID, err = utils.DecodeCursor(request.Cursor)
+		if err != nil || filter.BeforeID <= 0 {
+			return utils.RequestInvalid("cursor")
+		}
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	notifications, err := s.notificationRepo.List(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list notifications, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	unread, err := s.notificationRepo.CountUnread(filter.CustomerID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to count unread notifications, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListNotificationResponse{
+		Notifications: make([]model.NotificationResponse, len(notifications)),
+		UnreadCount:   unread,
+	}
+	for index, notification := range notifications {
+		resp.Notifications[index] = model.NotificationResponse{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      notification.Data,
+			Read:      notification.ReadAt.Valid,
+			ReadAt:    utils.TimePtr(notification.ReadAt),
+			CreatedAt: notification.CreatedAt,
+		}
+	}
+
+	if len(notifications) == filter.Limit {
+		resp.NextCursor = utils.EncodeCursor("", notifications[len(notifications)-1].ID)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// UnreadCount returns how many notifications of a customer are not read yet.
+func (s *notificationServiceImpl) UnreadCount(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("customer_id")
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "UnreadCount")
+
+	unread, err := s.notificationRepo.CountUnread(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to count unread notifications, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: model.UnreadCountResponse{UnreadCount: unread}}
+}
+
+// MarkRead marks a notification, or every notification of the customer, as read.
+func (s *notificationServiceImpl) MarkRead(ctx context.Context, request model.MarkNotificationReadRequest) (int, *model.BaseResponse) {
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if !request.All && request.NotificationID <= 0 {
+		return utils.RequestRequired("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "MarkRead")
+
+	var resp model.MarkNotificationReadResponse
+	if request.All {
+		updated, err := s.notificationRepo.MarkAllRead(request.CustomerID)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to mark all notifications as read, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+		resp.Updated = updated
+	} else {
+		found, err := s.notificationRepo.MarkRead(request.CustomerID, request.NotificationID)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to mark notification as read, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+
+		if !found {
+			return http.StatusNotFound, &model.BaseResponse{}
+		}
+		resp.Updated = 1
+	}
+
+	unread, err := s.notificationRepo.CountUnread(request.CustomerID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to count unread notifications, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+	resp.UnreadCount = unread
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Delete removes a notification from the inbox of a customer.
+func (s *notificationServiceImpl) Delete(ctx context.Context, customerID string, notificationID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("customer_id")
+	} else if strings.TrimSpace(notificationID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	customer, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	id, err := strconv.ParseInt(notificationID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Delete")
+
+	found, err := s.notificationRepo.Delete(customer, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete notification, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if !found {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{}
+}
+
+// orderStatusMessages defines the inbox title and body of order status changes.
+var orderStatusMessages = map[model.OrderStatus][2]string{
+	model.OrderStatusPaid:      {"Payment received", "We have received the payment of order %s."},
+	model.OrderStatusPacked:    {"Order packed", "Order %s is packed and waiting for the courier."},
+	model.OrderStatusShipped:   {"Order shipped", "Order %s is on its way."},
+	model.OrderStatusDelivered: {"Order delivered", "Order %s has been delivered."},
+}
+
+// inboxMessage returns the inbox title, body and data of an event, and false
+// when the event is not shown in the inbox.
+func inboxMessage(eventType model.OutboxEventType, raw json.RawMessage) (string, string, interface{}, bool) {
+	switch eventType {
+	case model.OutboxEventOrderCreated, model.OutboxEventOrderCancelled, model.OutboxEventOrderStatusChanged:
+		var payload model.OrderEvent
+		if json.Unmarshal(raw, &payload) != nil {
+			return "", "", nil, false
+		}
+
+		data := map[string]interface{}{"order_id": payload.OrderID, "status": payload.Status}
+		switch eventType {
+		case model.OutboxEventOrderCreated:
+			return "Order placed", fmt.Sprintf("Order %s has been placed, total %s.", payload.OrderID, payload.TotalAmount), data, true
+		case model.OutboxEventOrderCancelled:
+			return "Order cancelled", fmt.Sprintf("Items of order %s have been cancelled.", payload.OrderID), data, true
+		}
+
+		if message, ok := orderStatusMessages[payload.Status]; ok {
+			return message[0], fmt.Sprintf(message[1], payload.OrderID), data, true
+		}
+	case model.OutboxEventBackInStockAlert:
+		var payload model.BackInStockAlertEvent
+		if json.Unmarshal(raw, &payload) != nil {
+			return "", "", nil, false
+		}
+
+		data := map[string]interface{}{"product_id": payload.ProductID, "sku": payload.SKU}
+		return "Back in stock", fmt.Sprintf("%s is available again, only %d left.", payload.SKU, payload.Stock), data, true
+	case model.OutboxEventPriceDropAlert:
+		var payload model.PriceDropAlertEvent
+		if json.Unmarshal(raw, &payload) != nil {
+			return "", "", nil, false
+		}
+
+		data := map[string]interface{}{"product_id": payload.ProductID, "sku": payload.SKU, "old_price": payload.OldPrice, "new_price": payload.NewPrice}
+		return "Price drop", fmt.Sprintf("%s dropped from %s to %s.", payload.SKU, payload.OldPrice, payload.NewPrice), data, true
+	case model.OutboxEventCartReminder, model.OutboxEventOrderReminder:
+		var payload model.ReminderEvent
+		if json.Unmarshal(raw, &payload) != nil {
+			return "", "", nil, false
+		}
+
+		if eventType == model.OutboxEventCartReminder {
+			data := map[string]interface{}{"cart_id": payload.CartID}
+			return "Your cart is waiting", "You left items in your cart, they are still available.", data, true
+		}
+
+		data := map[string]interface{}{"order_id": payload.OrderID}
+		return "Complete your payment", fmt.Sprintf("Order %s is waiting for payment.", payload.OrderID), data, true
+	}
+	return "", "", nil, false
+}
+
+// InboxChannel stores the order events of a customer in the customer's in-app inbox.
+type InboxChannel struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewInboxChannel returns new instance of InboxChannel.
+func NewInboxChannel() *InboxChannel {
+	return &InboxChannel{}
+}
+
+// SetNotificationRepo injects notification's repo for InboxChannel.
+func (c *InboxChannel) SetNotificationRepo(repo repository.NotificationRepository) *InboxChannel {
+	c.notificationRepo = repo
+	return c
+}
+
+// Validate validates if all dependency for InboxChannel is complete.
+func (c *InboxChannel) Validate() *InboxChannel {
+	if c.notificationRepo == nil {
+		log.Panic("Inbox channel need notification repository")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *InboxChannel) Name() string {
+	return "inbox"
+}
+
+// Send stores the notification of the event, events that are not about a
+// customer or not shown in the inbox are skipped.
+func (c *InboxChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	var recipient struct {
+		CustomerID int64 `json:"customer_id"`
+	}
+	if json.Unmarshal(event.Payload, &recipient) != nil || recipient.CustomerID == 0 {
+		return nil
+	}
+
+	title, body, data, ok := inboxMessage(event.EventType, event.Payload)
+	if !ok {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return c.notificationRepo.Create(&model.Notification{
+		CustomerID: recipient.CustomerID,
+		EventID:    sql.NullInt64{Int64: event.OriginID(), Valid: true},
+		Type:       string(event.EventType),
+		Title:      title,
+		Body:       body,
+		Data:       raw,
+	})
+}
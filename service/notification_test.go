@@ -0,0 +1,181 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListNotification(t *testing.T) {
+	prepare()
+
+	// TestListNotificationInvalidRequest
+	func(t *testing.T) {
+		notificationService := service.NewNotificationService()
+
+		// Case: missing customer
+		httpCode, resp := notificationService.List(context.Background(), model.ListNotificationRequest{})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: limit over the maximum
+		httpCode, resp = notificationService.List(context.Background(), model.ListNotificationRequest{CustomerID: "7", Limit: "1000"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestListNotificationErrorDatabase
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		mockNotificationRepo.On("List", mock.Anything).Return(nil, errors.New("error"))
+		httpCode, _ := notificationService.List(context.Background(), model.ListNotificationRequest{CustomerID: "7"})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+	}(t)
+
+	// TestListNotificationSuccess
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		// Case: a full page returns the cursor of the next page
+		mockNotificationRepo.On("List", model.NotificationFilter{CustomerID: 7, UnreadOnly: true, BeforeID: 50, Limit: 2}).
+			Return([]*model.Notification{
+				{ID: 42, CustomerID: 7, Type: "order.status_changed", Title: "Order shipped", CreatedAt: time.Now()},
+				{ID: 40, CustomerID: 7, Type: "order.created", Title: "Order placed", ReadAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			}, nil)
+		mockNotificationRepo.On("CountUnread", int64(7)).Return(int64(3), nil)
+		req := model.ListNotificationRequest{
+			CustomerID: "7",
+			UnreadOnly: "true",
+			Limit:      "2",
+			Cursor:     utils.EncodeCursor("", 50),
+		}
+		httpCode, resp := notificationService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListNotificationResponse)
+		assert.Len(t, result.Notifications, 2)
+		assert.False(t, result.Notifications[0].Read)
+		assert.True(t, result.Notifications[1].Read)
+		assert.Equal(t, result.UnreadCount, int64(3))
+		assert.Equal(t, result.NextCursor, utils.EncodeCursor("", 40))
+	}(t)
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	prepare()
+
+	// TestMarkNotificationReadInvalidRequest
+	func(t *testing.T) {
+		notificationService := service.NewNotificationService()
+
+		// Case: neither a notification nor all
+		httpCode, resp := notificationService.MarkRead(context.Background(), model.MarkNotificationReadRequest{CustomerID: 7})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestMarkNotificationReadNotFound
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		// Case: notification of another customer
+		mockNotificationRepo.On("MarkRead", int64(7), int64(42)).Return(false, nil)
+		httpCode, _ := notificationService.MarkRead(context.Background(), model.MarkNotificationReadRequest{CustomerID: 7, NotificationID: 42})
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestMarkNotificationReadSuccess
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		// Case: mark all as read
+		mockNotificationRepo.On("MarkAllRead", int64(7)).Return(int64(3), nil)
+		mockNotificationRepo.On("CountUnread", int64(7)).Return(int64(0), nil)
+		httpCode, resp := notificationService.MarkRead(context.Background(), model.MarkNotificationReadRequest{CustomerID: 7, All: true})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData, model.MarkNotificationReadResponse{Updated: 3, UnreadCount: 0})
+		mockNotificationRepo.AssertNumberOfCalls(t, "MarkRead", 0)
+	}(t)
+}
+
+func TestDeleteNotification(t *testing.T) {
+	prepare()
+
+	// TestDeleteNotificationNotFound
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		mockNotificationRepo.On("Delete", int64(7), int64(42)).Return(false, nil)
+		httpCode, _ := notificationService.Delete(context.Background(), "7", "42")
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestDeleteNotificationSuccess
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		notificationService := service.NewNotificationService().
+			SetNotificationRepo(mockNotificationRepo)
+
+		mockNotificationRepo.On("Delete", int64(7), int64(42)).Return(true, nil)
+		httpCode, _ := notificationService.Delete(context.Background(), "7", "42")
+		assert.Equal(t, httpCode, http.StatusOK)
+	}(t)
+}
+
+func TestInboxChannel(t *testing.T) {
+	prepare()
+
+	// TestInboxChannelSend
+	func(t *testing.T) {
+		mockNotificationRepo := new(repoMock.NotificationRepository)
+		channel := service.NewInboxChannel().
+			SetNotificationRepo(mockNotificationRepo).
+			Validate()
+
+		// Case: shipped order is stored once per event
+		mockNotificationRepo.On("Create", mock.MatchedBy(func(notification *model.Notification) bool {
+			return notification.CustomerID == 7 && notification.EventID.Int64 == 9 &&
+				notification.Title == "Order shipped" && notification.Body == "Order ORD-001 is on its way."
+		})).Return(nil)
+		event := &model.OutboxEvent{
+			ID:        9,
+			EventType: model.OutboxEventOrderStatusChanged,
+			Payload:   []byte(`{"order_id":"ORD-001","customer_id":7,"status":"shipped"}`),
+		}
+		err := channel.Send(context.Background(), event)
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertNumberOfCalls(t, "Create", 1)
+
+		// Case: events without a customer are skipped
+		event = &model.OutboxEvent{
+			ID:        10,
+			EventType: model.OutboxEventOrderCreated,
+			Payload:   []byte(`{"order_id":"ORD-002"}`),
+		}
+		err = channel.Send(context.Background(), event)
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertNumberOfCalls(t, "Create", 1)
+	}(t)
+}
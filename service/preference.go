@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+)
+
+// notificationPolicy defines how customers are notified about an event type.
+// Critical notifications are sent during the customer's quiet hours.
+type notificationPolicy struct {
+	category model.NotificationCategory
+	critical bool
+}
+
+// notificationPolicies defines the policy of event types, event types that are
+// not listed are transactional and not critical.
+var notificationPolicies = map[model.OutboxEventType]notificationPolicy{
+	model.OutboxEventOrderCreated:   {category: model.NotificationCategoryTransactional},
+	model.OutboxEventOrderCancelled: {category: model.NotificationCategoryTransactional, critical: true},
+	// customers asked for the alert, it is not marketing
+	model.OutboxEventBackInStockAlert: {category: model.NotificationCategoryTransactional},
+	model.OutboxEventPriceDropAlert:   {category: model.NotificationCategoryTransactional},
+	// abandoned carts are marketing, customers opt in to their reminders
+	model.OutboxEventCartReminder:  {category: model.NotificationCategoryMarketing},
+	model.OutboxEventOrderReminder: {category: model.NotificationCategoryTransactional},
+}
+
+// policyOf returns the notification policy of an event type.
+func policyOf(eventType model.OutboxEventType) notificationPolicy {
+	if policy, ok := notificationPolicies[eventType]; ok {
+		return policy
+	}
+	return notificationPolicy{category: model.NotificationCategoryTransactional}
+}
+
+// customerChannels maps the dispatcher channels that deliver to the customer to
+// the preference governing them. Other channels, such as the partner webhooks
+// named "webhook" and the inbox, ignore the customer's preferences and quiet
+// hours, so one customer can not hold back what partners receive.
+var customerChannels = map[string]model.NotificationChannel{
+	"email": model.NotificationChannelEmail,
+	"sms":   model.NotificationChannelSMS,
+	"push":  model.NotificationChannelPush,
+}
+
+// recipient is the customer an event is about, with the preferences used to
+// decide which channels the event is sent through.
+type recipient struct {
+	policy     notificationPolicy
+	enabled    map[model.NotificationChannel]bool
+	quietUntil time.Time
+}
+
+// allows returns true when the customer receives the event through channel,
+// channels that do not deliver to the customer are always allowed.
+func (r *recipient) allows(channel string) bool {
+	preference, ok := customerChannels[channel]
+	return !ok || r.enabled[preference]
+}
+
+// holds returns true when the event has to wait for the end of quiet hours
+// before it is sent through channel.
+func (r *recipient) holds(channel string) bool {
+	_, ok := customerChannels[channel]
+	return ok && !r.quietUntil.IsZero() && !r.policy.critical
+}
+
+// loadRecipient returns the recipient of an event at now, or nil when the event
+// is not about a customer.
+func loadRecipient(customerRepo repository.CustomerRepository, preferenceRepo repository.NotificationPreferenceRepository, event *model.OutboxEvent, now time.Time) (*recipient, error) {
+	if len(event.Payload) == 0 {
+		return nil, nil
+	}
+
+	var payload struct {
+		CustomerID int64 `json:"customer_id"`
+	}
+	if json.Unmarshal(event.Payload, &payload) != nil || payload.CustomerID == 0 {
+		return nil, nil
+	}
+
+	r := &recipient{
+		policy:  policyOf(event.EventType),
+		enabled: make(map[model.NotificationChannel]bool),
+	}
+	for _, channel := range model.NotificationChannels {
+		r.enabled[channel] = model.DefaultNotificationEnabled(r.policy.category)
+	}
+
+	preferences, err := preferenceRepo.GetByCustomerID(payload.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Category == r.policy.category {
+			r.enabled[preference.Channel] = preference.Enabled
+		}
+	}
+
+	customer, err := customerRepo.GetByID(payload.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer != nil && customer.QuietHoursStart.Valid && customer.QuietHoursEnd.Valid {
+		location, err := time.LoadLocation(customer.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+
+		if until, quiet := quietHoursEnd(now, location, customer.QuietHoursStart.String, customer.QuietHoursEnd.String); quiet {
+			r.quietUntil = until
+		}
+	}
+
+	return r, nil
+}
+
+// parseClock returns the minutes since midnight of a "HH:MM" time.
+func parseClock(value string) (int, bool) {
+	var hour, minute int
+	if len(value) != 5 {
+		return 0, false
+	}
+
+	_, err := fmt.Sscanf(value, "%02d:%02d", &hour, &minute)
+	if err != nil || hour > 23 || minute > 59 || hour < 0 || minute < 0 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// quietHoursEnd returns when the quiet hours from start to end in location end,
+// and true when now is inside them.
+func quietHoursEnd(now time.Time, location *time.Location, start string, end string) (time.Time, bool) {
+	startMinute, ok := parseClock(start)
+	if !ok {
+		return time.Time{}, false
+	}
+	endMinute, ok := parseClock(end)
+	if !ok || startMinute == endMinute {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	if startMinute < endMinute {
+		quiet = minute >= startMinute && minute < endMinute
+	} else {
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	day := local.Day()
+	if minute >= endMinute {
+		day++
+	}
+	return time.Date(local.Year(), local.Month(), day, endMinute/60, endMinute%60, 0, 0, location), true
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// PriceWatchService manage logical syntax for price watches.
+type PriceWatchService interface {
+	Watch(ctx context.Context, request model.PriceWatchRequest) (int, *model.BaseResponse)
+	Unwatch(ctx context.Context, request model.PriceWatchRequest) (int, *model.BaseResponse)
+	List(ctx context.Context, customerID string) (int, *model.BaseResponse)
+}
+
+type priceWatchServiceImpl struct {
+	priceWatchRepo repository.PriceWatchRepository
+	productRepo    repository.ProductRepository
+}
+
+// NewPriceWatchService returns new instance of priceWatchServiceImpl.
+func NewPriceWatchService() *priceWatchServiceImpl {
+	return &priceWatchServiceImpl{}
+}
+
+// SetPriceWatchRepo injects price watch's repo for priceWatchServiceImpl.
+func (s *priceWatchServiceImpl) SetPriceWatchRepo(repo repository.PriceWatchRepository) *priceWatchServiceImpl {
+	s.priceWatchRepo = repo
+	return s
+}
+
+// SetProductRepo injects product's repo for priceWatchServiceImpl.
+func (s *priceWatchServiceImpl) SetProductRepo(repo repository.ProductRepository) *priceWatchServiceImpl {
+	s.productRepo = repo
+	return s
+}
+
+// Validate validates if all dependency for priceWatchServiceImpl is complete.
+func (s *priceWatchServiceImpl) Validate() *priceWatchServiceImpl {
+	if s.priceWatchRepo == nil {
+		log.Panic("Price watch service need price watch repository")
+	}
+	if s.productRepo == nil {
+		log.Panic("Price watch service need product repository")
+	}
+	return s
+}
+
+// Watch watches the price of a product for a customer, either below a target
+// price or by a percentage of the current price. Watching a product again
+// replaces the target of the active watch.
+func (s *priceWatchServiceImpl) Watch(ctx context.Context, request model.PriceWatchRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	} else if request.TargetPrice == nil && request.DropPercent == nil {
+		return utils.RequestRequired("target_price")
+	} else if request.TargetPrice != nil && request.DropPercent != nil {
+		return utils.RequestInvalid("drop_percent")
+	} else if request.TargetPrice != nil && request.TargetPrice.IsNegative() {
+		return utils.RequestInvalid("target_price")
+	} else if request.DropPercent != nil &&
+		(request.DropPercent.Cmp(model.NewMoney(0)) <= 0 || request.DropPercent.Cmp(model.NewMoney(100)) >= 0) {
+		return utils.RequestInvalid("drop_percent")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Watch")
+
+	product, err := s.productRepo.GetBySKU(request.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get product by SKU, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return utils.RequestInvalid("sku")
+	}
+
+	var target model.Money
+	if request.TargetPrice != nil {
+		target = *request.TargetPrice
+	} else {
+		target = product.Price.Sub(product.Price.Percent(*request.DropPercent))
+	}
+
+	// a target that is already met would never see the price fall to it
+	if target.Cmp(product.Price) >= 0 {
+		return utils.RequestInvalid("target_price")
+	}
+
+	watch, err := s.priceWatchRepo.GetActive(request.CustomerID, product.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get price watch, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if watch == nil {
+		watch = &model.PriceWatch{
+			CustomerID: request.CustomerID,
+			ProductID:  product.ID,
+			SKU:        product.SKU,
+			Status:     model.PriceWatchActive,
+		}
+	}
+
+	watch.BasePrice = product.Price
+	watch.TargetPrice = target
+	watch.DropPercent = request.DropPercent
+	watch.LastNotifiedPrice = nil
+
+	if watch.ID == 0 {
+		err = s.priceWatchRepo.Create(watch)
+	} else {
+		err = s.priceWatchRepo.Update(watch)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to save price watch, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: priceWatchResponse(watch)}
+}
+
+// Unwatch cancels the active watch of a customer on a SKU.
+func (s *priceWatchServiceImpl) Unwatch(ctx context.Context, request model.PriceWatchRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.CustomerID <= 0 {
+		return utils.RequestRequired("customer_id")
+	} else if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Unwatch")
+
+	watch, err := s.priceWatchRepo.GetActive(request.CustomerID, request.SKU)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get price watch, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if watch == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	err = s.priceWatchRepo.Cancel(watch.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to cancel price watch, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{}
+}
+
+// List returns the active watches of a customer, newest first.
+func (s *priceWatchServiceImpl) List(ctx context.Context, customerID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(customerID) == "" {
+		return utils.RequestRequired("customer_id")
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("customer_id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	watches, err := s.priceWatchRepo.ListByCustomerID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list price watches, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := make([]model.PriceWatchResponse, len(watches))
+	for index, watch := range watches {
+		resp[index] = priceWatchResponse(watch)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// priceWatchResponse returns the response of a price watch.
+func priceWatchResponse(watch *model.PriceWatch) model.PriceWatchResponse {
+	return model.PriceWatchResponse{
+		ID:                watch.ID,
+		CustomerID:        watch.CustomerID,
+		SKU:               watch.SKU,
+		BasePrice:         watch.BasePrice,
+		TargetPrice:       watch.TargetPrice,
+		DropPercent:       watch.DropPercent,
+		LastNotifiedPrice: watch.LastNotifiedPrice,
+		Status:            watch.Status,
+		CreatedAt:         watch.CreatedAt,
+		NotifiedAt:        utils.TimePtr(watch.NotifiedAt),
+	}
+}
+
+// writePriceChangedEvent writes product.price_changed when the price of a product changes.
+func writePriceChangedEvent(tx *sqlx.Tx, repo repository.OutboxRepository, product *model.Product, price model.Money) error {
+	if product.Price.Cmp(price) == 0 {
+		return nil
+	}
+
+	return writeOutboxEvent(tx, repo, model.OutboxEventProductPriceChanged, product.SKU, model.PriceChangedEvent{
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		OldPrice:  product.Price,
+		NewPrice:  price,
+	})
+}
+
+// PriceWatchChannel evaluates the price watches of a product whose price fell.
+// Matching watches are claimed in batches, and every one of them gets a
+// notification.price_drop event, delivered by the customer channels.
+type PriceWatchChannel struct {
+	priceWatchRepo repository.PriceWatchRepository
+	productRepo    repository.ProductRepository
+	outboxRepo     repository.OutboxRepository
+	txRepo         repository.TxRepository
+	batchSize      int
+}
+
+// NewPriceWatchChannel returns new instance of PriceWatchChannel.
+func NewPriceWatchChannel() *PriceWatchChannel {
+	return &PriceWatchChannel{}
+}
+
+// SetPriceWatchRepo injects price watch's repo for PriceWatchChannel.
+func (c *PriceWatchChannel) SetPriceWatchRepo(repo repository.PriceWatchRepository) *PriceWatchChannel {
+	c.priceWatchRepo = repo
+	return c
+}
+
+// SetProductRepo injects product's repo for PriceWatchChannel.
+func (c *PriceWatchChannel) SetProductRepo(repo repository.ProductRepository) *PriceWatchChannel {
+	c.productRepo = repo
+	return c
+}
+
+// SetOutboxRepo injects outbox's repo for PriceWatchChannel.
+func (c *PriceWatchChannel) SetOutboxRepo(repo repository.OutboxRepository) *PriceWatchChannel {
+	c.outboxRepo = repo
+	return c
+}
+
+// SetTxRepo injects transaction runner for PriceWatchChannel.
+func (c *PriceWatchChannel) SetTxRepo(repo repository.TxRepository) *PriceWatchChannel {
+	c.txRepo = repo
+	return c
+}
+
+// SetBatchSize sets how many watches are evaluated in one transaction.
+func (c *PriceWatchChannel) SetBatchSize(size int) *PriceWatchChannel {
+	c.batchSize = size
+	return c
+}
+
+// Validate validates if all dependency for PriceWatchChannel is complete.
+func (c *PriceWatchChannel) Validate() *PriceWatchChannel {
+	if c.priceWatchRepo == nil {
+		log.Panic("Price watch channel need price watch repository")
+	}
+	if c.productRepo == nil {
+		log.Panic("Price watch channel need product repository")
+	}
+	if c.outboxRepo == nil {
+		log.Panic("Price watch channel need outbox repository")
+	}
+	if c.txRepo == nil {
+		log.Panic("Price watch channel need tx repository")
+	}
+	if c.batchSize <= 0 {
+		log.Panic("Price watch channel need batch size")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *PriceWatchChannel) Name() string {
+	return "price_watch"
+}
+
+// Send notifies the watches met by the new price of a product.price_changed event.
+func (c *PriceWatchChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	if event.EventType != model.OutboxEventProductPriceChanged {
+		return nil
+	}
+
+	var payload model.PriceChangedEvent
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil || payload.NewPrice.Cmp(payload.OldPrice) >= 0 {
+		return nil
+	}
+
+	// a later change has its own event, the price may be up again
+	product, err := c.productRepo.GetBySKU(payload.SKU)
+	if err != nil {
+		return err
+	}
+
+	if product == nil || product.Price.Cmp(payload.NewPrice) != 0 {
+		return nil
+	}
+
+	for {
+		count, err := c.notifyBatch(product, payload.OldPrice)
+		if err != nil {
+			return err
+		}
+
+		if count < c.batchSize {
+			return nil
+		}
+	}
+}
+
+// notifyBatch notifies one batch of watches met by the price of product and
+// returns how many were claimed. Notified watches no longer match the price.
+func (c *PriceWatchChannel) notifyBatch(product *model.Product, oldPrice model.Money) (int, error) {
+	var count int
+	err := c.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		watches, err := c.priceWatchRepo.ClaimMatchingTx(tx, product.ID, product.Price, c.batchSize)
+		if err != nil || len(watches) == 0 {
+			return err
+		}
+		count = len(watches)
+
+		ids := make([]int64, len(watches))
+		for index, watch := range watches {
+			ids[index] = watch.ID
+
+			err = writeOutboxEvent(tx, c.outboxRepo, model.OutboxEventPriceDropAlert, product.SKU, model.PriceDropAlertEvent{
+				WatchID:     watch.ID,
+				CustomerID:  watch.CustomerID,
+				ProductID:   product.ID,
+				SKU:         product.SKU,
+				OldPrice:    oldPrice,
+				NewPrice:    product.Price,
+				TargetPrice: watch.TargetPrice,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return c.priceWatchRepo.MarkNotifiedTx(tx, ids, product.Price)
+	})
+	return count, err
+}
@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWatchPrice(t *testing.T) {
+	prepare()
+
+	target := model.NewMoney(800)
+	percent := model.NewMoney(25)
+
+	// TestWatchPriceInvalidRequest
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		priceWatchService := service.NewPriceWatchService().
+			SetProductRepo(mockProductRepo)
+
+		// Case: missing target
+		httpCode, _ := priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: target and percentage
+		httpCode, _ = priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test", TargetPrice: &target, DropPercent: &percent})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: percentage out of range
+		full := model.NewMoney(100)
+		httpCode, _ = priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test", DropPercent: &full})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: target is not below the current price
+		above := model.NewMoney(1000)
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Price: model.NewMoney(1000)}, nil)
+		httpCode, _ = priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test", TargetPrice: &above})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestWatchPriceSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockPriceWatchRepo := new(repoMock.PriceWatchRepository)
+		priceWatchService := service.NewPriceWatchService().
+			SetPriceWatchRepo(mockPriceWatchRepo).
+			SetProductRepo(mockProductRepo)
+
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Price: model.NewMoney(1000)}, nil)
+
+		// Case: a percentage is taken off the current price
+		mockPriceWatchRepo.On("GetActive", int64(7), "sku-test").Return(nil, nil).Once()
+		mockPriceWatchRepo.On("Create", mock.MatchedBy(func(watch *model.PriceWatch) bool {
+			return watch.TargetPrice.Cmp(model.NewMoney(750)) == 0 && watch.BasePrice.Cmp(model.NewMoney(1000)) == 0
+		})).Return(nil)
+		httpCode, resp := priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test", DropPercent: &percent})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.PriceWatchResponse).TargetPrice, model.NewMoney(750))
+
+		// Case: watching again replaces the target and may fire again
+		notified := model.NewMoney(700)
+		mockPriceWatchRepo.On("GetActive", int64(7), "sku-test").
+			Return(&model.PriceWatch{ID: 5, CustomerID: 7, SKU: "sku-test", LastNotifiedPrice: &notified}, nil)
+		mockPriceWatchRepo.On("Update", mock.MatchedBy(func(watch *model.PriceWatch) bool {
+			return watch.ID == 5 && watch.TargetPrice.Cmp(target) == 0 && watch.DropPercent == nil && watch.LastNotifiedPrice == nil
+		})).Return(nil)
+		httpCode, _ = priceWatchService.Watch(context.Background(), model.PriceWatchRequest{CustomerID: 7, SKU: "sku-test", TargetPrice: &target})
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockPriceWatchRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockPriceWatchRepo.AssertNumberOfCalls(t, "Update", 1)
+	}(t)
+}
+
+func TestPriceWatchChannel(t *testing.T) {
+	prepare()
+
+	payload, _ := json.Marshal(model.PriceChangedEvent{ProductID: 1, SKU: "sku-test", OldPrice: model.NewMoney(1000), NewPrice: model.NewMoney(700)})
+	event := &model.OutboxEvent{
+		EventType:   model.OutboxEventProductPriceChanged,
+		AggregateID: "sku-test",
+		Payload:     payload,
+	}
+
+	// TestPriceWatchChannelSkipped
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		channel := service.NewPriceWatchChannel().
+			SetPriceWatchRepo(new(repoMock.PriceWatchRepository)).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(new(repoMock.OutboxRepository)).
+			SetTxRepo(mockTxRepo).
+			SetBatchSize(2).
+			Validate()
+
+		// Case: a price increase is ignored
+		raised, _ := json.Marshal(model.PriceChangedEvent{ProductID: 1, SKU: "sku-test", OldPrice: model.NewMoney(700), NewPrice: model.NewMoney(1000)})
+		err := channel.Send(context.Background(), &model.OutboxEvent{EventType: model.OutboxEventProductPriceChanged, Payload: raised})
+		assert.Nil(t, err)
+		mockProductRepo.AssertNotCalled(t, "GetBySKU", mock.Anything)
+
+		// Case: the price changed again since the event was written
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Price: model.NewMoney(900)}, nil)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		mockTxRepo.AssertNotCalled(t, "WithTx", mock.Anything)
+	}(t)
+
+	// TestPriceWatchChannelSuccess
+	func(t *testing.T) {
+		mockPriceWatchRepo := new(repoMock.PriceWatchRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		channel := service.NewPriceWatchChannel().
+			SetPriceWatchRepo(mockPriceWatchRepo).
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetBatchSize(2)
+
+		// Case: watches are evaluated until a batch is not full
+		mockProductRepo.On("GetBySKU", "sku-test").Return(&model.Product{ID: 1, SKU: "sku-test", Price: model.NewMoney(700)}, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockPriceWatchRepo.On("ClaimMatchingTx", mock.Anything, int64(1), model.NewMoney(700), 2).
+			Return([]*model.PriceWatch{{ID: 10, CustomerID: 7}, {ID: 11, CustomerID: 8}}, nil).Once()
+		mockPriceWatchRepo.On("ClaimMatchingTx", mock.Anything, int64(1), model.NewMoney(700), 2).
+			Return([]*model.PriceWatch{{ID: 12, CustomerID: 9}}, nil).Once()
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			var alert model.PriceDropAlertEvent
+			json.Unmarshal(event.Payload, &alert)
+			return event.EventType == model.OutboxEventPriceDropAlert && alert.CustomerID > 0 &&
+				alert.OldPrice.Cmp(model.NewMoney(1000)) == 0 && alert.NewPrice.Cmp(model.NewMoney(700)) == 0
+		})).Return(nil)
+		mockPriceWatchRepo.On("MarkNotifiedTx", mock.Anything, mock.Anything, model.NewMoney(700)).Return(nil)
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		mockPriceWatchRepo.AssertNumberOfCalls(t, "ClaimMatchingTx", 2)
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 3)
+		mockPriceWatchRepo.AssertCalled(t, "MarkNotifiedTx", mock.Anything, []int64{10, 11}, model.NewMoney(700))
+		mockPriceWatchRepo.AssertCalled(t, "MarkNotifiedTx", mock.Anything, []int64{12}, model.NewMoney(700))
+	}(t)
+}
+
+func TestUpdateProductPrice(t *testing.T) {
+	prepare()
+
+	// TestUpdateProductPriceInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		// Case: missing price
+		httpCode, _ := productService.UpdatePrice(context.Background(), model.UpdatePriceRequest{SKU: "sku-test"})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: negative price
+		httpCode, _ = productService.UpdatePrice(context.Background(), model.UpdatePriceRequest{SKU: "sku-test", Price: model.NewMoney(-1)})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestUpdateProductPriceSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).
+			Return([]*model.Product{{ID: 1, SKU: "sku-test", Price: model.NewMoney(1000)}}, nil)
+
+		// Case: the same price changes nothing
+		httpCode, _ := productService.UpdatePrice(context.Background(), model.UpdatePriceRequest{SKU: "sku-test", Price: model.NewMoney(1000)})
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockProductRepo.AssertNotCalled(t, "UpdatePriceTx", mock.Anything, mock.Anything, mock.Anything)
+
+		// Case: a new price writes product.price_changed
+		mockProductRepo.On("UpdatePriceTx", mock.Anything, int64(1), model.NewMoney(700)).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductPriceChanged
+		})).Return(nil)
+		httpCode, resp := productService.UpdatePrice(context.Background(), model.UpdatePriceRequest{SKU: "sku-test", Price: model.NewMoney(700)})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.UpdatePriceResponse).OldPrice, model.NewMoney(1000))
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/domain/repository"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
@@ -19,11 +24,57 @@ type ProductService interface {
 	Create(ctx context.Context, request model.CreateProductRequest) (int, *model.BaseResponse)
 	GetByID(ctx context.Context, productID string) (int, *model.BaseResponse)
 	GetByBrandID(ctx context.Context, brandID string) (int, *model.BaseResponse)
+	List(ctx context.Context, request model.ListProductRequest) (int, *model.BaseResponse)
+	Restock(ctx context.Context, request model.RestockProductRequest) (int, *model.BaseResponse)
+	UpdatePrice(ctx context.Context, request model.UpdatePriceRequest) (int, *model.BaseResponse)
+	Update(ctx context.Context, request model.UpdateProductRequest) (int, *model.BaseResponse)
+	Delete(ctx context.Context, productID string) (int, *model.BaseResponse)
+	Restore(ctx context.Context, productID string) (int, *model.BaseResponse)
+}
+
+// List of product listing page size.
+const (
+	defaultProductListLimit = 20
+	maxProductListLimit     = 100
+)
+
+// List of product content limits.
+const (
+	maxProductNameLength        = 200
+	maxProductDescriptionLength = 65535
+	maxProductAttributes        = 50
+	maxAttributeKeyLength       = 64
+	maxAttributeValueLength     = 255
+)
+
+// productTransitions defines the statuses a product is allowed to move to from
+// its current status, a product that has been sold is never a draft again.
+var productTransitions = map[model.ProductStatus][]model.ProductStatus{
+	model.ProductStatusDraft:        {model.ProductStatusActive, model.ProductStatusDiscontinued},
+	model.ProductStatusActive:       {model.ProductStatusDiscontinued},
+	model.ProductStatusDiscontinued: {model.ProductStatusActive},
+}
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// invalidProductTransitionError is returned when a product is not allowed to move to the requested status.
+type invalidProductTransitionError struct {
+	from model.ProductStatus
+	to   model.ProductStatus
+}
+
+func (e *invalidProductTransitionError) Error() string {
+	return fmt.Sprintf("product status can not change from %s to %s", e.from, e.to)
 }
 
 type productServiceImpl struct {
 	productRepo repository.ProductRepository
 	brandRepo   repository.BrandRepository
+	outboxRepo  repository.OutboxRepository
+	txRepo      repository.TxRepository
 }
 
 // NewProductService returns new instance of productServiceImpl.
@@ -43,6 +94,18 @@ func (s *productServiceImpl) SetBrandRepo(repo repository.BrandRepository) *prod
 	return s
 }
 
+// SetOutboxRepo injects outbox's repo for productServiceImpl.
+func (s *productServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *productServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetTxRepo injects tx's repo for productServiceImpl.
+func (s *productServiceImpl) SetTxRepo(repo repository.TxRepository) *productServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
 // Validate validates if all dependency for productServiceImpl is complete.
 func (s *productServiceImpl) Validate() *productServiceImpl {
 	if s.productRepo == nil {
@@ -51,6 +114,12 @@ func (s *productServiceImpl) Validate() *productServiceImpl {
 	if s.brandRepo == nil {
 		log.Panic("Product service need brand repository")
 	}
+	if s.outboxRepo == nil {
+		log.Panic("Product service need outbox repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Product service need tx repository")
+	}
 	return s
 }
 
@@ -61,8 +130,38 @@ func (s *productServiceImpl) Create(ctx context.Context, request model.CreatePro
 		return utils.RequestRequired("brand_id")
 	} else if strings.TrimSpace(request.SKU) == "" {
 		return utils.RequestRequired("sku")
-	} else if request.Price == 0 {
+	} else if strings.TrimSpace(request.Name) == "" {
+		return utils.RequestRequired("name")
+	} else if request.Price.IsZero() {
 		return utils.RequestRequired("price")
+	} else if request.Price.IsNegative() {
+		return utils.RequestInvalid("price")
+	}
+
+	if request.Status == "" {
+		request.Status = model.ProductStatusActive
+	}
+
+	product := model.Product{
+		BrandID: request.BrandID,
+		SKU:     request.SKU,
+		Stock:   request.Stock,
+		Price:   request.Price,
+		Status:  request.Status,
+	}
+
+	slug := strings.TrimSpace(request.Slug)
+	if slug == "" {
+		slug = slugify(request.Name)
+	}
+	if slug == "" {
+		slug = slugify(request.SKU)
+	}
+
+	if field, ok := setProductContent(&product, &request.Name, &slug, &request.Description, request.Attributes); !ok {
+		return utils.RequestInvalid(field)
+	} else if !isProductStatus(product.Status) {
+		return utils.RequestInvalid("status")
 	}
 
 	log := logger.GetLoggerContext(ctx, "service", "Create")
@@ -77,7 +176,8 @@ func (s *productServiceImpl) Create(ctx context.Context, request model.CreatePro
 		return utils.RequestInvalid("brand_id")
 	}
 
-	checkProduct, err := s.productRepo.GetBySKU(request.SKU)
+	// a deleted product keeps its SKU so it can be restored
+	checkProduct, err := s.productRepo.GetBySKU(request.SKU, repository.IncludeDeleted)
 	if err != nil {
 		log.Error(fmt.Sprintf("failed to get product by SKU, err : %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
@@ -87,15 +187,25 @@ func (s *productServiceImpl) Create(ctx context.Context, request model.CreatePro
 		return utils.RequestInvalid("sku")
 	}
 
-	product := model.Product{
-		BrandID: request.BrandID,
-		SKU:     request.SKU,
-		Stock:   request.Stock,
-		Price:   request.Price,
-	}
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		err := s.productRepo.CreateTx(tx, &product)
+		if err != nil {
+			return err
+		}
 
-	err = s.productRepo.Create(&product)
-	if err != nil {
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventProductCreated, product.SKU, model.ProductEvent{
+			ID:      product.ID,
+			BrandID: product.BrandID,
+			SKU:     product.SKU,
+			Name:    product.Name,
+			Stock:   product.Stock,
+			Price:   product.Price,
+			Status:  product.Status,
+		})
+	})
+	if errors.Is(err, repository.ErrDuplicateProductSlug) {
+		return duplicateProductSlug(product.Slug)
+	} else if err != nil {
 		log.Error(fmt.Sprintf("failed to create product, err : %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
 	}
@@ -131,15 +241,7 @@ func (s *productServiceImpl) GetByID(ctx context.Context, productID string) (int
 		return http.StatusNotFound, &model.BaseResponse{}
 	}
 
-	productResp := model.GetProductResponse{
-		ID:      product.ID,
-		BrandID: product.BrandID,
-		SKU:     product.SKU,
-		Stock:   product.Stock,
-		Price:   product.Price,
-	}
-
-	return http.StatusOK, &model.BaseResponse{ResultData: productResp}
+	return http.StatusOK, &model.BaseResponse{ResultData: productResponse(product)}
 }
 
 // GetByBrandID returns a list of product by the brand's ID from the database.
@@ -156,15 +258,647 @@ func (s *productServiceImpl) GetByBrandID(ctx context.Context, brandID string) (
 
 	log := logger.GetLoggerContext(ctx, "service", "GetByBrandID")
 
-	product, err := s.productRepo.GetByBrandID(id)
+	products, err := s.productRepo.GetByBrandID(id)
 	if err != nil {
 		log.Error(fmt.Sprintf("failed to get product by brand id, err : %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
 	}
 
 	resp := model.GetProductByBrandIDResponse{
-		Products: product,
+		Products: make([]model.GetProductResponse, 0, len(products)),
+	}
+	for _, product := range products {
+		resp.Products = append(resp.Products, productResponse(product))
 	}
 
 	return http.StatusOK, &model.BaseResponse{ResultData: resp}
 }
+
+// List returns products matching the filters of the request, newest first unless
+// sorted by price or stock, a page at a time. The next page is requested with the
+// next_cursor of the previous page.
+func (s *productServiceImpl) List(ctx context.Context, request model.ListProductRequest) (int, *model.BaseResponse) {
+	filter := model.ProductFilter{
+		SKUPrefix: strings.TrimSpace(request.SKUPrefix),
+		SortBy:    "created_at",
+		SortDesc:  true,
+		Limit:     defaultProductListLimit,
+	}
+
+	// validate request
+	if request.BrandID != "" {
+		brandID, err := strconv.ParseInt(request.BrandID, 10, 64)
+		if err != nil || brandID <= 0 {
+			return utils.RequestInvalid("brand_id")
+		}
+		filter.BrandID = brandID
+	}
+	if request.MinPrice != "" {
+		minPrice, err := model.ParseMoney(request.MinPrice)
+		if err != nil {
+			return utils.RequestInvalid("min_price")
+		}
+		filter.MinPrice = &minPrice
+	}
+	if request.MaxPrice != "" {
+		maxPrice, err := model.ParseMoney(request.MaxPrice)
+		if err != nil {
+			return utils.RequestInvalid("max_price")
+		}
+		filter.MaxPrice = &maxPrice
+	}
+	if request.InStock != "" {
+		inStock, err := strconv.ParseBool(request.InStock)
+		if err != nil {
+			return utils.RequestInvalid("in_stock")
+		}
+		filter.InStock = inStock
+	}
+	if request.CreatedFrom != "" {
+		createdFrom, _, err := parseDateTime(request.CreatedFrom)
+		if err != nil {
+			return utils.RequestInvalid("created_from")
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+	if request.CreatedTo != "" {
+		createdTo, dateOnly, err := parseDateTime(request.CreatedTo)
+		if err != nil {
+			return utils.RequestInvalid("created_to")
+		}
+		// a date includes the whole day
+		if dateOnly {
+			createdTo = createdTo.Add(24*time.Hour - time.Second)
+		}
+		filter.CreatedTo = &createdTo
+	}
+	if request.Sort != "" && request.Sort != "newest" {
+		filter.SortBy = strings.TrimPrefix(request.Sort, "-")
+		filter.SortDesc = strings.HasPrefix(request.Sort, "-")
+		if filter.SortBy != "created_at" && filter.SortBy != "price" && filter.SortBy != "stock" {
+			return utils.RequestInvalid("sort")
+		}
+	}
+	if request.Limit != "" {
+		limit, err := strconv.Atoi(request.Limit)
+		if err != nil || limit <= 0 || limit > maxProductListLimit {
+			return utils.RequestInvalid("limit")
+		}
+		filter.Limit = limit
+	}
+	sort := filter.SortBy
+	if filter.SortDesc {
+		sort = "-" + sort
+	}
+	if request.Cursor != "" {
+		// a cursor only continues the sort it was issued for
+		cursorSort, value, id, err := utils.DecodeSortCursor(request.Cursor)
+		if err != nil || cursorSort != sort {
+			return utils.RequestInvalid("cursor")
+		}
+
+		switch filter.SortBy {
+		case "created_at":
+			filter.AfterValue, err = time.Parse(time.RFC3339Nano, value)
+		case "price":
+			filter.AfterValue, err = model.ParseMoney(value)
+		case "stock":
+			filter.AfterValue, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			return utils.RequestInvalid("cursor")
+		}
+		filter.AfterID = id
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	// one more row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	products, err := s.productRepo.List(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list products, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListProductResponse{
+		Products: make([]model.GetProductResponse, 0, len(products)),
+	}
+
+	if len(products) > limit {
+		products = products[:limit]
+
+		last := products[limit-1]
+		switch filter.SortBy {
+		case "created_at":
+			resp.NextCursor = utils.EncodeSortCursor(sort, last.CreatedAt.Format(time.RFC3339Nano), last.ID)
+		case "price":
+			resp.NextCursor = utils.EncodeSortCursor(sort, last.Price.String(), last.ID)
+		case "stock":
+			resp.NextCursor = utils.EncodeSortCursor(sort, strconv.FormatInt(last.Stock, 10), last.ID)
+		}
+	}
+
+	for _, product := range products {
+		resp.Products = append(resp.Products, productResponse(product))
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Restock adds units to the stock of a product, subscribers are notified when
+// a sold-out product is available again.
+func (s *productServiceImpl) Restock(ctx context.Context, request model.RestockProductRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	} else if request.Quantity <= 0 {
+		return utils.RequestInvalid("quantity")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Restock")
+
+	var product *model.Product
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		products, err := s.productRepo.GetBySKUsForUpdate(tx, []string{request.SKU})
+		if err != nil || len(products) == 0 {
+			return err
+		}
+		product = products[0]
+
+		err = s.productRepo.UpdateStockTx(tx, product.ID, request.Quantity)
+		if err != nil {
+			return err
+		}
+
+		return writeBackInStockEvent(tx, s.outboxRepo, product, product.Stock+request.Quantity)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to restock product, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	resp := model.RestockProductResponse{
+		ID:    product.ID,
+		SKU:   product.SKU,
+		Stock: product.Stock + request.Quantity,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// UpdatePrice changes the price of a product, customers watching the product
+// are notified when the price falls to their target.
+func (s *productServiceImpl) UpdatePrice(ctx context.Context, request model.UpdatePriceRequest) (int, *model.BaseResponse) {
+	request.SKU = strings.TrimSpace(request.SKU)
+
+	// validate request
+	if request.SKU == "" {
+		return utils.RequestRequired("sku")
+	} else if request.Price.IsZero() {
+		return utils.RequestRequired("price")
+	} else if request.Price.IsNegative() {
+		return utils.RequestInvalid("price")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "UpdatePrice")
+
+	var product *model.Product
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		products, err := s.productRepo.GetBySKUsForUpdate(tx, []string{request.SKU})
+		if err != nil || len(products) == 0 {
+			return err
+		}
+		product = products[0]
+
+		if product.Price.Cmp(request.Price) == 0 {
+			return nil
+		}
+
+		err = s.productRepo.UpdatePriceTx(tx, product.ID, request.Price)
+		if err != nil {
+			return err
+		}
+
+		return writePriceChangedEvent(tx, s.outboxRepo, product, request.Price)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to update product price, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	resp := model.UpdatePriceResponse{
+		ID:       product.ID,
+		SKU:      product.SKU,
+		OldPrice: product.Price,
+		Price:    request.Price,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Update changes the brand, stock, price, content and status of a product, a
+// partial update only the given ones. The update is refused with 409 and the
+// current product when the product changed since the client read it, and with
+// 409 when the status can not change to the requested one. Subscribers are
+// notified when the product is back in stock and watchers when its price changes.
+func (s *productServiceImpl) Update(ctx context.Context, request model.UpdateProductRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.ID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(request.ID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	content := request.Name != nil || request.Slug != nil || request.Description != nil || request.Attributes != nil
+	if request.Partial && request.BrandID == nil && request.Stock == nil && request.Price == nil && !content && request.Status == nil {
+		return utils.RequestRequired("brand_id, stock, price, content or status")
+	} else if !request.Partial && request.BrandID == nil {
+		return utils.RequestRequired("brand_id")
+	} else if !request.Partial && request.Stock == nil {
+		return utils.RequestRequired("stock")
+	} else if !request.Partial && request.Price == nil {
+		return utils.RequestRequired("price")
+	} else if request.BrandID != nil && *request.BrandID <= 0 {
+		return utils.RequestInvalid("brand_id")
+	} else if request.Stock != nil && *request.Stock < 0 {
+		return utils.RequestInvalid("stock")
+	} else if request.Price != nil && (request.Price.IsZero() || request.Price.IsNegative()) {
+		return utils.RequestInvalid("price")
+	} else if request.Status != nil && !isProductStatus(*request.Status) {
+		return utils.RequestInvalid("status")
+	}
+
+	if field, ok := setProductContent(&model.Product{}, request.Name, request.Slug, request.Description, request.Attributes); !ok {
+		return utils.RequestInvalid(field)
+	}
+
+	// If-Match: * updates whatever the version is
+	version := int64(0)
+	if request.Version != nil {
+		version = *request.Version
+	}
+
+	if ifMatch := strings.TrimSpace(request.IfMatch); ifMatch != "" && ifMatch != "*" {
+		tagVersion, err := utils.ParseETag(ifMatch)
+		if err != nil || (version != 0 && version != tagVersion) {
+			return utils.RequestInvalid("If-Match")
+		}
+		version = tagVersion
+	} else if ifMatch == "" && version == 0 {
+		return http.StatusPreconditionRequired, &model.BaseResponse{RawMessage: "version or If-Match is required"}
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Update")
+
+	if request.BrandID != nil {
+		brand, err := s.brandRepo.GetByID(*request.BrandID)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to get brand, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+
+		if brand == nil {
+			return utils.RequestInvalid("brand_id")
+		}
+	}
+
+	var product, updated *model.Product
+	var slug string
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		current, err := s.productRepo.GetByIDForUpdate(tx, id)
+		if err != nil || current == nil || (version != 0 && current.Version != version) {
+			product = current
+			return err
+		}
+		product = current
+
+		next := *current
+		if request.BrandID != nil {
+			next.BrandID = *request.BrandID
+		}
+		if request.Stock != nil {
+			next.Stock = *request.Stock
+		}
+		if request.Price != nil {
+			next.Price = *request.Price
+		}
+		if request.Status != nil {
+			if !canTransitionProduct(current.Status, *request.Status) {
+				return &invalidProductTransitionError{from: current.Status, to: *request.Status}
+			}
+			next.Status = *request.Status
+		}
+		setProductContent(&next, request.Name, request.Slug, request.Description, request.Attributes)
+
+		if !productChanged(current, &next) {
+			updated = current
+			return nil
+		}
+
+		slug = next.Slug
+		err = s.productRepo.UpdateTx(tx, &next)
+		if err != nil {
+			return err
+		}
+
+		next.Version++
+		next.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		err = writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventProductUpdated, next.SKU, model.ProductEvent{
+			ID:      next.ID,
+			BrandID: next.BrandID,
+			SKU:     next.SKU,
+			Name:    next.Name,
+			Stock:   next.Stock,
+			Price:   next.Price,
+			Status:  next.Status,
+			Version: next.Version,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeBackInStockEvent(tx, s.outboxRepo, current, next.Stock)
+		if err != nil {
+			return err
+		}
+
+		err = writePriceChangedEvent(tx, s.outboxRepo, current, next.Price)
+		if err != nil {
+			return err
+		}
+
+		updated = &next
+		return nil
+	})
+	var transitionErr *invalidProductTransitionError
+	if errors.As(err, &transitionErr) {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: transitionErr.Error()}
+	} else if errors.Is(err, repository.ErrDuplicateProductSlug) {
+		return duplicateProductSlug(slug)
+	} else if err != nil {
+		log.Error(fmt.Sprintf("failed to update product, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	if updated == nil {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "product has been modified", ResultData: productResponse(product)}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: productResponse(updated)}
+}
+
+// Delete soft-deletes a product, it is no longer listed nor sold but can be
+// restored by an admin.
+func (s *productServiceImpl) Delete(ctx context.Context, productID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(productID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(productID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Delete")
+
+	var product *model.Product
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		product, err = s.productRepo.GetByIDForUpdate(tx, id)
+		if err != nil || product == nil {
+			return err
+		}
+
+		at := time.Now().UTC().Truncate(time.Second)
+		err = s.productRepo.DeleteTx(tx, id, at)
+		if err != nil {
+			return err
+		}
+		product.Version++
+		product.DeletedAt = sql.NullTime{Time: at, Valid: true}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventProductDeleted, product.SKU, model.ProductEvent{
+			ID:      product.ID,
+			BrandID: product.BrandID,
+			SKU:     product.SKU,
+			Name:    product.Name,
+			Stock:   product.Stock,
+			Price:   product.Price,
+			Status:  product.Status,
+			Version: product.Version,
+		})
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete product, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: productResponse(product)}
+}
+
+// Restore brings back a soft-deleted product. The product of a deleted brand can
+// only be restored after its brand. Restoring a product that is not deleted does
+// nothing.
+func (s *productServiceImpl) Restore(ctx context.Context, productID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(productID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(productID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Restore")
+
+	var product *model.Product
+	var brandDeleted bool
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		product, err = s.productRepo.GetByIDForUpdate(tx, id, repository.IncludeDeleted)
+		if err != nil || product == nil || !product.DeletedAt.Valid {
+			return err
+		}
+
+		brand, err := s.brandRepo.GetByID(product.BrandID)
+		if err != nil {
+			return err
+		}
+
+		if brand == nil {
+			brandDeleted = true
+			return nil
+		}
+
+		err = s.productRepo.RestoreTx(tx, id)
+		if err != nil {
+			return err
+		}
+		product.Version++
+		product.DeletedAt = sql.NullTime{}
+		product.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventProductRestored, product.SKU, model.ProductEvent{
+			ID:      product.ID,
+			BrandID: product.BrandID,
+			SKU:     product.SKU,
+			Name:    product.Name,
+			Stock:   product.Stock,
+			Price:   product.Price,
+			Status:  product.Status,
+			Version: product.Version,
+		})
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to restore product, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if product == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	if brandDeleted {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: "brand of the product is deleted, restore it first"}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: productResponse(product)}
+}
+
+// productResponse returns the response of a product.
+func productResponse(product *model.Product) model.GetProductResponse {
+	return model.GetProductResponse{
+		ID:          product.ID,
+		BrandID:     product.BrandID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description.String,
+		Attributes:  product.Attributes,
+		Stock:       product.Stock,
+		Price:       product.Price,
+		Status:      product.Status,
+		Version:     product.Version,
+		UpdatedAt:   utils.TimePtr(product.UpdatedAt),
+		DeletedAt:   utils.TimePtr(product.DeletedAt),
+	}
+}
+
+// setProductContent validates the given content of a product and sets it, the
+// description is sanitized first. It returns the invalid field when not ok.
+func setProductContent(product *model.Product, name, slug, description *string, attributes model.ProductAttributes) (string, bool) {
+	if name != nil {
+		product.Name = strings.TrimSpace(*name)
+		if product.Name == "" || len(product.Name) > maxProductNameLength {
+			return "name", false
+		}
+	}
+
+	if slug != nil {
+		product.Slug = strings.TrimSpace(*slug)
+		if !slugPattern.MatchString(product.Slug) || len(product.Slug) > maxProductNameLength {
+			return "slug", false
+		}
+	}
+
+	if description != nil {
+		text := utils.SanitizeHTML(*description)
+		if len(text) > maxProductDescriptionLength {
+			return "description", false
+		}
+		product.Description = sql.NullString{String: text, Valid: text != ""}
+	}
+
+	if attributes != nil {
+		if len(attributes) > maxProductAttributes {
+			return "attributes", false
+		}
+
+		product.Attributes = make(model.ProductAttributes, len(attributes))
+		for key, value := range attributes {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == "" || len(key) > maxAttributeKeyLength || len(value) > maxAttributeValueLength {
+				return "attributes", false
+			}
+			product.Attributes[key] = value
+		}
+	}
+
+	return "", true
+}
+
+// productChanged returns true when an update changes any field of a product.
+func productChanged(current, next *model.Product) bool {
+	if next.BrandID != current.BrandID || next.Stock != current.Stock || next.Price.Cmp(current.Price) != 0 ||
+		next.Name != current.Name || next.Slug != current.Slug || next.Description != current.Description ||
+		next.Status != current.Status || len(next.Attributes) != len(current.Attributes) {
+		return true
+	}
+
+	for key, value := range next.Attributes {
+		if currentValue, ok := current.Attributes[key]; !ok || currentValue != value {
+			return true
+		}
+	}
+	return false
+}
+
+// slugify returns the slug of a name, its letters and digits in lower case
+// joined by hyphens.
+func slugify(name string) string {
+	return strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// isProductStatus returns true when status is a known product status.
+func isProductStatus(status model.ProductStatus) bool {
+	_, ok := productTransitions[status]
+	return ok
+}
+
+// canTransitionProduct returns true when a product is allowed to move from one
+// status to another, staying in the same status is always allowed.
+func canTransitionProduct(from, to model.ProductStatus) bool {
+	if from == to {
+		return true
+	}
+
+	for _, status := range productTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateProductSlug returns the response of saving a product with the slug
+// of another product.
+func duplicateProductSlug(slug string) (int, *model.BaseResponse) {
+	return http.StatusConflict, &model.BaseResponse{RawMessage: fmt.Sprintf("product slug %q already exists", slug)}
+}
@@ -2,18 +2,23 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/cmd"
 	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
 	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
 	"github.com/richardsahvic/jamtangan/pkg/config"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
 	"github.com/richardsahvic/jamtangan/service"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func prepare() {
@@ -22,6 +27,11 @@ func prepare() {
 	return
 }
 
+// runTx runs the mocked database transaction without a real *sqlx.Tx.
+func runTx(fn func(tx *sqlx.Tx) error) error {
+	return fn(nil)
+}
+
 func TestCreateProduct(t *testing.T) {
 	prepare()
 
@@ -47,11 +57,43 @@ func TestCreateProduct(t *testing.T) {
 
 		// Case: empty price
 		req = model.CreateProductRequest{
-			Price: 0,
+			Price: model.Money{},
 		}
 		httpCode, resp = productService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusBadRequest)
 		assert.Nil(t, resp.ResultData)
+
+		// Case: empty name
+		req = model.CreateProductRequest{
+			BrandID: 1,
+			SKU:     "sku-test",
+			Name:    " ",
+			Price:   model.NewMoney(100),
+		}
+		httpCode, resp = productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "name is required")
+
+		// Case: invalid slug
+		req.Name = "Classic Watch"
+		req.Slug = "Classic Watch"
+		httpCode, resp = productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "slug is invalid")
+
+		// Case: invalid attributes
+		req.Slug = ""
+		req.Attributes = model.ProductAttributes{" ": "automatic"}
+		httpCode, resp = productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "attributes is invalid")
+
+		// Case: unknown status
+		req.Attributes = nil
+		req.Status = "sold"
+		httpCode, resp = productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "status is invalid")
 	}(t)
 
 	// TestCreateProductInvalidBrandCheck
@@ -63,7 +105,8 @@ func TestCreateProduct(t *testing.T) {
 		req := model.CreateProductRequest{
 			BrandID: 1,
 			SKU:     "sku-test",
-			Price:   100,
+			Name:    "Classic Watch",
+			Price:   model.NewMoney(100),
 		}
 		mockBrandRepo.On("GetByID", req.BrandID).Return(nil, nil)
 		httpCode, resp := productService.Create(context.Background(), req)
@@ -84,10 +127,11 @@ func TestCreateProduct(t *testing.T) {
 		req := model.CreateProductRequest{
 			BrandID: 1,
 			SKU:     "sku-test",
-			Price:   100,
+			Name:    "Classic Watch",
+			Price:   model.NewMoney(100),
 		}
 		mockBrandRepo.On("GetByID", req.BrandID).Return(&model.Brand{ID: 1}, nil)
-		mockProductRepo.On("GetBySKU", req.SKU).Return(&model.Product{SKU: req.SKU}, nil)
+		mockProductRepo.On("GetBySKU", req.SKU, repository.IncludeDeleted).Return(&model.Product{SKU: req.SKU}, nil)
 		httpCode, resp := productService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusBadRequest)
 		assert.NotEmpty(t, resp.RawMessage)
@@ -99,60 +143,143 @@ func TestCreateProduct(t *testing.T) {
 	func(t *testing.T) {
 		mockBrandRepo := new(repoMock.BrandRepository)
 		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
 		productService := service.NewProductService().
 			SetBrandRepo(mockBrandRepo).
-			SetProductRepo(mockProductRepo)
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
 
 		// Case: invalid brand ID
 		req := model.CreateProductRequest{
 			BrandID: 1,
 			SKU:     "sku-test",
-			Price:   100,
+			Name:    "Classic Watch",
+			Price:   model.NewMoney(100),
 		}
 		result := &model.Product{
 			BrandID: req.BrandID,
 			SKU:     req.SKU,
+			Name:    req.Name,
+			Slug:    "classic-watch",
 			Price:   req.Price,
+			Status:  model.ProductStatusActive,
 		}
 		mockBrandRepo.On("GetByID", req.BrandID).Return(&model.Brand{ID: 1}, nil)
-		mockProductRepo.On("GetBySKU", req.SKU).Return(nil, nil)
-		mockProductRepo.On("Create", result).Return(errors.New("error"))
+		mockProductRepo.On("GetBySKU", req.SKU, repository.IncludeDeleted).Return(nil, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("CreateTx", mock.Anything, result).Return(errors.New("error"))
 		httpCode, resp := productService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusInternalServerError)
 		assert.NotEmpty(t, resp.RawMessage)
 		mockBrandRepo.AssertNumberOfCalls(t, "GetByID", 1)
 		mockProductRepo.AssertNumberOfCalls(t, "GetBySKU", 1)
-		mockProductRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockProductRepo.AssertNumberOfCalls(t, "CreateTx", 1)
 	}(t)
 
 	// TestCreateProductSuccess
 	func(t *testing.T) {
 		mockBrandRepo := new(repoMock.BrandRepository)
 		mockProductRepo := new(repoMock.ProductRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
 		productService := service.NewProductService().
 			SetBrandRepo(mockBrandRepo).
-			SetProductRepo(mockProductRepo)
+			SetProductRepo(mockProductRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
 
 		// Case: invalid brand ID
 		req := model.CreateProductRequest{
 			BrandID: 1,
 			SKU:     "sku-test",
-			Price:   100,
+			Name:    "Classic Watch",
+			Price:   model.NewMoney(100),
 		}
 		result := &model.Product{
 			BrandID: req.BrandID,
 			SKU:     req.SKU,
+			Name:    req.Name,
+			Slug:    "classic-watch",
 			Price:   req.Price,
+			Status:  model.ProductStatusActive,
 		}
 		mockBrandRepo.On("GetByID", req.BrandID).Return(&model.Brand{ID: 1}, nil)
-		mockProductRepo.On("GetBySKU", req.SKU).Return(nil, nil)
-		mockProductRepo.On("Create", result).Return(nil)
+		mockProductRepo.On("GetBySKU", req.SKU, repository.IncludeDeleted).Return(nil, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("CreateTx", mock.Anything, result).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductCreated && event.AggregateID == "sku-test"
+		})).Return(nil)
 		httpCode, resp := productService.Create(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusOK)
 		assert.Empty(t, resp.RawMessage)
 		mockBrandRepo.AssertNumberOfCalls(t, "GetByID", 1)
 		mockProductRepo.AssertNumberOfCalls(t, "GetBySKU", 1)
-		mockProductRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockProductRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+	}(t)
+}
+
+func TestCreateProductContent(t *testing.T) {
+	prepare()
+
+	mockBrandRepo := new(repoMock.BrandRepository)
+	mockProductRepo := new(repoMock.ProductRepository)
+	mockOutboxRepo := new(repoMock.OutboxRepository)
+	mockTxRepo := new(repoMock.TxRepository)
+	productService := service.NewProductService().
+		SetBrandRepo(mockBrandRepo).
+		SetProductRepo(mockProductRepo).
+		SetOutboxRepo(mockOutboxRepo).
+		SetTxRepo(mockTxRepo)
+
+	mockBrandRepo.On("GetByID", int64(1)).Return(&model.Brand{ID: 1}, nil)
+	mockProductRepo.On("GetBySKU", mock.Anything, repository.IncludeDeleted).Return(nil, nil)
+	mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+	mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+	// TestCreateProductContentSanitized
+	func(t *testing.T) {
+		// Case: the description keeps its formatting but not its scripts
+		req := model.CreateProductRequest{
+			BrandID:     1,
+			SKU:         "sku-draft",
+			Name:        "Diver 200m",
+			Description: `<p onclick="steal()">Water <b>resistant</b><script>alert(1)</script></p>`,
+			Attributes:  model.ProductAttributes{" movement ": " automatic "},
+			Price:       model.NewMoney(100),
+			Status:      model.ProductStatusDraft,
+		}
+		mockProductRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+			return product.SKU == "sku-draft"
+		})).Return(nil).Once()
+		httpCode, _ := productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		product := mockProductRepo.Calls[len(mockProductRepo.Calls)-1].Arguments.Get(1).(*model.Product)
+		assert.Equal(t, product.Slug, "diver-200m")
+		assert.Equal(t, product.Description.String, "<p>Water <b>resistant</b></p>")
+		assert.Equal(t, product.Attributes, model.ProductAttributes{"movement": "automatic"})
+		assert.Equal(t, product.Status, model.ProductStatusDraft)
+	}(t)
+
+	// TestCreateProductContentDuplicateSlug
+	func(t *testing.T) {
+		// Case: the slug of another product is refused
+		req := model.CreateProductRequest{
+			BrandID: 1,
+			SKU:     "sku-copy",
+			Name:    "Diver 200m",
+			Price:   model.NewMoney(100),
+		}
+		mockProductRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+			return product.SKU == "sku-copy"
+		})).Return(repository.ErrDuplicateProductSlug).Once()
+		httpCode, resp := productService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, `product slug "diver-200m" already exists`)
 	}(t)
 }
 
@@ -258,11 +385,433 @@ func TestGetByBrandID(t *testing.T) {
 		productService := service.NewProductService().SetProductRepo(mockProductRepo)
 
 		id := "1"
-		mockProductRepo.On("GetByBrandID", int64(1)).Return([]*model.Product{}, nil)
+		mockProductRepo.On("GetByBrandID", int64(1)).Return([]*model.Product{
+			{
+				ID:          1,
+				SKU:         "sku-test",
+				Name:        "Classic Watch",
+				Slug:        "classic-watch",
+				Description: sql.NullString{String: "<p>Steel</p>", Valid: true},
+				BrandID:     1,
+				Price:       model.NewMoney(100),
+				Status:      model.ProductStatusActive,
+			},
+		}, nil)
 		httpCode, resp := productService.GetByBrandID(context.Background(), id)
 		assert.Equal(t, httpCode, http.StatusOK)
 		assert.Empty(t, resp.RawMessage)
-		assert.NotEmpty(t, resp.ResultData)
 		mockProductRepo.AssertNumberOfCalls(t, "GetByBrandID", 1)
+
+		result := resp.ResultData.(model.GetProductByBrandIDResponse)
+		assert.Len(t, result.Products, 1)
+		assert.Equal(t, result.Products[0].Name, "Classic Watch")
+		assert.Equal(t, result.Products[0].Description, "<p>Steel</p>")
+	}(t)
+}
+
+func TestUpdateProduct(t *testing.T) {
+	prepare()
+
+	int64Ptr := func(value int64) *int64 {
+		return &value
+	}
+	moneyPtr := func(value model.Money) *model.Money {
+		return &value
+	}
+
+	// TestUpdateProductInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		// Case: empty ID
+		httpCode, _ := productService.Update(context.Background(), model.UpdateProductRequest{Version: int64Ptr(1)})
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: a full update without price
+		req := model.UpdateProductRequest{ID: "1", BrandID: int64Ptr(1), Stock: int64Ptr(5), Version: int64Ptr(1)}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: a partial update without fields
+		req = model.UpdateProductRequest{ID: "1", Version: int64Ptr(1), Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: negative stock
+		req = model.UpdateProductRequest{ID: "1", Stock: int64Ptr(-1), Version: int64Ptr(1), Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: version and If-Match disagree
+		req = model.UpdateProductRequest{ID: "1", Stock: int64Ptr(5), Version: int64Ptr(1), IfMatch: `"2"`, Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: no version to compare with
+		req = model.UpdateProductRequest{ID: "1", Stock: int64Ptr(5), Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusPreconditionRequired)
+	}(t)
+
+	// TestUpdateProductConflict
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: a stale version gets the current product
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Product{
+			ID: 1, SKU: "sku-test", BrandID: 1, Stock: 3, Price: model.NewMoney(1000), Version: 4,
+		}, nil)
+		req := model.UpdateProductRequest{ID: "1", Stock: int64Ptr(5), IfMatch: `W/"3"`, Partial: true}
+		httpCode, resp := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.ResultData.(model.GetProductResponse).Version, int64(4))
+		assert.Equal(t, resp.ResultData.(model.GetProductResponse).Stock, int64(3))
+		mockProductRepo.AssertNotCalled(t, "UpdateTx", mock.Anything, mock.Anything)
+
+		// Case: unknown product
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(nil, nil)
+		req = model.UpdateProductRequest{ID: "2", Stock: int64Ptr(5), Version: int64Ptr(1), Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestUpdateProductSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockBrandRepo.On("GetByID", int64(2)).Return(&model.Brand{ID: 2}, nil)
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Product{
+			ID: 1, SKU: "sku-test", BrandID: 1, Stock: 0, Price: model.NewMoney(1000), Version: 4,
+		}, nil)
+		mockProductRepo.On("UpdateTx", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+			return product.ID == 1 && product.BrandID == 2 && product.Stock == 5 && product.Price.Cmp(model.NewMoney(800)) == 0
+		})).Return(nil)
+
+		events := make([]model.OutboxEventType, 0)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			events = append(events, args.Get(1).(*model.OutboxEvent).EventType)
+		}).Return(nil)
+
+		// Case: a full update moves to the next version and notifies stock and price changes
+		req := model.UpdateProductRequest{ID: "1", BrandID: int64Ptr(2), Stock: int64Ptr(5), Price: moneyPtr(model.NewMoney(800)), IfMatch: `"4"`}
+		httpCode, resp := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.GetProductResponse)
+		assert.Equal(t, result.Version, int64(5))
+		assert.Equal(t, result.Stock, int64(5))
+		assert.NotNil(t, result.UpdatedAt)
+		assert.Equal(t, events, []model.OutboxEventType{
+			model.OutboxEventProductUpdated,
+			model.OutboxEventProductBackInStock,
+			model.OutboxEventProductPriceChanged,
+		})
+
+		// Case: an update that changes nothing is not written
+		req = model.UpdateProductRequest{ID: "1", Stock: int64Ptr(0), IfMatch: "*", Partial: true}
+		httpCode, resp = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.GetProductResponse).Version, int64(4))
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateTx", 1)
+	}(t)
+}
+
+func TestDeleteProduct(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.ProductRepository, *repoMock.BrandRepository, *repoMock.OutboxRepository, service.ProductService) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+		return mockProductRepo, mockBrandRepo, mockOutboxRepo, productService
+	}
+
+	// TestDeleteProductInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		// Case: empty ID
+		httpCode, _ := productService.Delete(context.Background(), "")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: invalid ID
+		httpCode, _ = productService.Restore(context.Background(), "abc")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestDeleteProductSuccess
+	func(t *testing.T) {
+		mockProductRepo, _, mockOutboxRepo, productService := newService()
+
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Product{ID: 1, SKU: "sku-test", Version: 2}, nil)
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(nil, nil)
+		mockProductRepo.On("DeleteTx", mock.Anything, int64(1), mock.Anything).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: product is soft-deleted
+		httpCode, resp := productService.Delete(context.Background(), "1")
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.GetProductResponse)
+		assert.NotNil(t, result.DeletedAt)
+		assert.Equal(t, result.Version, int64(3))
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductDeleted && event.AggregateID == "sku-test"
+		}))
+
+		// Case: unknown or already deleted product
+		httpCode, _ = productService.Delete(context.Background(), "2")
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+
+	// TestRestoreProduct
+	func(t *testing.T) {
+		mockProductRepo, mockBrandRepo, mockOutboxRepo, productService := newService()
+		deleted := sql.NullTime{Time: time.Now(), Valid: true}
+
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(1), repository.IncludeDeleted).Return(&model.Product{ID: 1, SKU: "sku-1", BrandID: 1, DeletedAt: deleted}, nil)
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(2), repository.IncludeDeleted).Return(&model.Product{ID: 2, SKU: "sku-2", BrandID: 2, DeletedAt: deleted}, nil)
+		mockBrandRepo.On("GetByID", int64(1)).Return(&model.Brand{ID: 1}, nil)
+		mockBrandRepo.On("GetByID", int64(2)).Return(nil, nil)
+		mockProductRepo.On("RestoreTx", mock.Anything, int64(1)).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: product of a deleted brand is not restored
+		httpCode, _ := productService.Restore(context.Background(), "2")
+		assert.Equal(t, httpCode, http.StatusConflict)
+		mockProductRepo.AssertNotCalled(t, "RestoreTx", mock.Anything, int64(2))
+
+		// Case: product is restored
+		httpCode, resp := productService.Restore(context.Background(), "1")
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Nil(t, resp.ResultData.(model.GetProductResponse).DeletedAt)
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductRestored && event.AggregateID == "sku-1"
+		}))
+	}(t)
+}
+
+func TestListProduct(t *testing.T) {
+	prepare()
+
+	// TestListProductInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		requests := []model.ListProductRequest{
+			{BrandID: "abc"},
+			{MinPrice: "a"},
+			{InStock: "maybe"},
+			{CreatedFrom: "yesterday"},
+			{Sort: "sku"},
+			{Limit: "1000"},
+			{Cursor: "%%%"},
+		}
+		for _, req := range requests {
+			httpCode, resp := productService.List(context.Background(), req)
+			assert.Equal(t, httpCode, http.StatusBadRequest)
+			assert.Nil(t, resp.ResultData)
+		}
+	}(t)
+
+	// TestListProductErrorDatabase
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		productService := service.NewProductService().SetProductRepo(mockProductRepo)
+
+		mockProductRepo.On("List", mock.Anything).Return(nil, errors.New("error"))
+		httpCode, resp := productService.List(context.Background(), model.ListProductRequest{})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestListProductSuccess
+	func(t *testing.T) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		productService := service.NewProductService().SetProductRepo(mockProductRepo)
+
+		// Case: newest products first by default
+		mockProductRepo.On("List", mock.MatchedBy(func(filter model.ProductFilter) bool {
+			return filter.SortBy == "created_at" && filter.SortDesc && filter.Limit == 21
+		})).Return([]*model.Product{}, nil).Once()
+		httpCode, resp := productService.List(context.Background(), model.ListProductRequest{Sort: "newest"})
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.ResultData.(model.ListProductResponse).Products)
+
+		req := model.ListProductRequest{
+			BrandID:   "1",
+			MaxPrice:  "50000",
+			InStock:   "true",
+			SKUPrefix: " sku_ ",
+			Sort:      "-stock",
+			Limit:     "2",
+		}
+		mockProductRepo.On("List", mock.MatchedBy(func(filter model.ProductFilter) bool {
+			return filter.BrandID == 1 && *filter.MaxPrice == model.NewMoney(50000) && filter.InStock &&
+				filter.SKUPrefix == "sku_" && filter.SortBy == "stock" && filter.SortDesc && filter.Limit == 3 &&
+				filter.AfterValue == nil
+		})).Return([]*model.Product{
+			{ID: 4, SKU: "sku_product-4", Stock: 21},
+			{ID: 2, SKU: "sku_product-2", Stock: 10},
+			{ID: 3, SKU: "sku_product-3", Stock: 5},
+		}, nil)
+		httpCode, resp = productService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListProductResponse)
+		assert.Len(t, result.Products, 2)
+		assert.NotEmpty(t, result.NextCursor)
+
+		// Case: next page continues after the last row
+		req.Cursor = result.NextCursor
+		mockProductRepo.On("List", mock.MatchedBy(func(filter model.ProductFilter) bool {
+			return filter.AfterValue == int64(10) && filter.AfterID == 2
+		})).Return([]*model.Product{
+			{ID: 3, SKU: "sku_product-3", Stock: 5},
+		}, nil)
+		httpCode, resp = productService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result = resp.ResultData.(model.ListProductResponse)
+		assert.Len(t, result.Products, 1)
+		assert.Empty(t, result.NextCursor)
+
+		// Case: a cursor is refused with another sort or direction
+		for _, sort := range []string{"stock", "price", "-price", "newest"} {
+			req.Sort = sort
+			httpCode, resp = productService.List(context.Background(), req)
+			assert.Equal(t, httpCode, http.StatusBadRequest, sort)
+			assert.Equal(t, resp.RawMessage, "cursor is invalid", sort)
+		}
+		mockProductRepo.AssertNumberOfCalls(t, "List", 3)
+	}(t)
+}
+
+func TestUpdateProductContent(t *testing.T) {
+	prepare()
+
+	stringPtr := func(value string) *string {
+		return &value
+	}
+	statusPtr := func(value model.ProductStatus) *model.ProductStatus {
+		return &value
+	}
+
+	newService := func(current *model.Product) (*repoMock.ProductRepository, *repoMock.OutboxRepository, service.ProductService) {
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockBrandRepo := new(repoMock.BrandRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, current.ID).Return(current, nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		productService := service.NewProductService().
+			SetProductRepo(mockProductRepo).
+			SetBrandRepo(mockBrandRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+		return mockProductRepo, mockOutboxRepo, productService
+	}
+
+	// TestUpdateProductContentInvalidRequest
+	func(t *testing.T) {
+		productService := service.NewProductService()
+
+		// Case: unknown status
+		req := model.UpdateProductRequest{ID: "1", Status: statusPtr("sold"), IfMatch: "*", Partial: true}
+		httpCode, _ := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+
+		// Case: empty name
+		req = model.UpdateProductRequest{ID: "1", Name: stringPtr(""), IfMatch: "*", Partial: true}
+		httpCode, _ = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestUpdateProductContentStatus
+	func(t *testing.T) {
+		mockProductRepo, _, productService := newService(&model.Product{
+			ID: 1, SKU: "sku-test", Name: "Diver", Slug: "diver", Status: model.ProductStatusActive, Version: 2,
+		})
+		mockProductRepo.On("UpdateTx", mock.Anything, mock.Anything).Return(nil)
+
+		// Case: an active product is never a draft again
+		req := model.UpdateProductRequest{ID: "1", Status: statusPtr(model.ProductStatusDraft), IfMatch: "*", Partial: true}
+		httpCode, resp := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, "product status can not change from active to draft")
+		mockProductRepo.AssertNotCalled(t, "UpdateTx", mock.Anything, mock.Anything)
+
+		// Case: an active product is discontinued
+		req = model.UpdateProductRequest{ID: "1", Status: statusPtr(model.ProductStatusDiscontinued), IfMatch: "*", Partial: true}
+		httpCode, resp = productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Equal(t, resp.ResultData.(model.GetProductResponse).Status, model.ProductStatusDiscontinued)
+	}(t)
+
+	// TestUpdateProductContentSuccess
+	func(t *testing.T) {
+		mockProductRepo, mockOutboxRepo, productService := newService(&model.Product{
+			ID: 1, SKU: "sku-test", Name: "Diver", Slug: "diver", Status: model.ProductStatusActive, Version: 2,
+			Attributes: model.ProductAttributes{"movement": "quartz"},
+		})
+		mockProductRepo.On("UpdateTx", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+			return product.Name == "Diver Pro" && product.Slug == "diver" && product.Description.String == "<p>New</p>" &&
+				product.Attributes["movement"] == "automatic"
+		})).Return(nil)
+
+		// Case: only the given content changes
+		req := model.UpdateProductRequest{
+			ID:          "1",
+			Name:        stringPtr("Diver Pro"),
+			Description: stringPtr("<p>New</p>"),
+			Attributes:  model.ProductAttributes{"movement": "automatic"},
+			IfMatch:     `"2"`,
+			Partial:     true,
+		}
+		httpCode, resp := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.GetProductResponse)
+		assert.Equal(t, result.Name, "Diver Pro")
+		assert.Equal(t, result.Version, int64(3))
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductUpdated
+		}))
+	}(t)
+
+	// TestUpdateProductContentDuplicateSlug
+	func(t *testing.T) {
+		mockProductRepo, _, productService := newService(&model.Product{
+			ID: 1, SKU: "sku-test", Name: "Diver", Slug: "diver", Status: model.ProductStatusActive, Version: 2,
+		})
+		mockProductRepo.On("UpdateTx", mock.Anything, mock.Anything).Return(repository.ErrDuplicateProductSlug)
+
+		// Case: the slug written is reported when the request has none
+		req := model.UpdateProductRequest{ID: "1", Name: stringPtr("Diver Pro"), IfMatch: "*", Partial: true}
+		httpCode, resp := productService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, `product slug "diver" already exists`)
 	}(t)
 }
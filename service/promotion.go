@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// PromotionService manage logical syntax for promotion.
+type PromotionService interface {
+	Create(ctx context.Context, request model.CreatePromotionRequest) (int, *model.BaseResponse)
+	GetByID(ctx context.Context, promotionID string) (int, *model.BaseResponse)
+}
+
+type promotionServiceImpl struct {
+	promotionRepo repository.PromotionRepository
+}
+
+// NewPromotionService returns new instance of promotionServiceImpl.
+func NewPromotionService() *promotionServiceImpl {
+	return &promotionServiceImpl{}
+}
+
+// SetPromotionRepo injects promotion's repo for promotionServiceImpl.
+func (s *promotionServiceImpl) SetPromotionRepo(repo repository.PromotionRepository) *promotionServiceImpl {
+	s.promotionRepo = repo
+	return s
+}
+
+// Validate validates if all dependency for promotionServiceImpl is complete.
+func (s *promotionServiceImpl) Validate() *promotionServiceImpl {
+	if s.promotionRepo == nil {
+		log.Panic("Promotion service need promotion repository")
+	}
+	return s
+}
+
+// Create creates a new promotion and store it into the database.
+func (s *promotionServiceImpl) Create(ctx context.Context, request model.CreatePromotionRequest) (int, *model.BaseResponse) {
+	request.Code = normalizeCouponCode(request.Code)
+	if request.Scope == "" {
+		request.Scope = model.PromotionScopeOrder
+	}
+
+	// validate request
+	if strings.TrimSpace(request.Name) == "" {
+		return utils.RequestRequired("name")
+	}
+
+	switch request.Type {
+	case model.PromotionTypePercentage:
+		if !request.Value.IsNegative() && !request.Value.IsZero() && request.Value.Cmp(model.NewMoney(100)) <= 0 {
+			break
+		}
+		return utils.RequestInvalid("value")
+	case model.PromotionTypeFixedAmount:
+		if request.Value.IsNegative() || request.Value.IsZero() {
+			return utils.RequestInvalid("value")
+		}
+	case model.PromotionTypeBuyXGetY:
+		if request.BuyQuantity <= 0 {
+			return utils.RequestInvalid("buy_quantity")
+		} else if request.GetQuantity <= 0 {
+			return utils.RequestInvalid("get_quantity")
+		}
+	default:
+		return utils.RequestInvalid("type")
+	}
+
+	switch request.Scope {
+	case model.PromotionScopeOrder:
+	case model.PromotionScopeBrand:
+		if request.BrandID == 0 {
+			return utils.RequestRequired("brand_id")
+		}
+	case model.PromotionScopeSKU:
+		if strings.TrimSpace(request.SKU) == "" {
+			return utils.RequestRequired("sku")
+		}
+	default:
+		return utils.RequestInvalid("scope")
+	}
+
+	if request.StartsAt != nil && request.EndsAt != nil && !request.EndsAt.After(*request.StartsAt) {
+		return utils.RequestInvalid("ends_at")
+	} else if request.UsageLimit < 0 {
+		return utils.RequestInvalid("usage_limit")
+	} else if request.PerCustomerLimit < 0 {
+		return utils.RequestInvalid("per_customer_limit")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Create")
+
+	if request.Code != "" {
+		checkPromotion, err := s.promotionRepo.GetByCode(request.Code)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to get promotion by code, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+
+		if checkPromotion != nil {
+			return utils.RequestInvalid("code")
+		}
+	}
+
+	promotion := model.Promotion{
+		Code:             sql.NullString{String: request.Code, Valid: request.Code != ""},
+		Name:             request.Name,
+		Type:             request.Type,
+		Value:            request.Value,
+		BuyQuantity:      request.BuyQuantity,
+		GetQuantity:      request.GetQuantity,
+		Scope:            request.Scope,
+		BrandID:          sql.NullInt64{Int64: request.BrandID, Valid: request.Scope == model.PromotionScopeBrand},
+		SKU:              sql.NullString{String: request.SKU, Valid: request.Scope == model.PromotionScopeSKU},
+		Stackable:        request.Stackable,
+		Priority:         request.Priority,
+		UsageLimit:       sql.NullInt64{Int64: request.UsageLimit, Valid: request.UsageLimit > 0},
+		PerCustomerLimit: sql.NullInt64{Int64: request.PerCustomerLimit, Valid: request.PerCustomerLimit > 0},
+	}
+	if request.StartsAt != nil {
+		promotion.StartsAt = sql.NullTime{Time: *request.StartsAt, Valid: true}
+	}
+	if request.EndsAt != nil {
+		promotion.EndsAt = sql.NullTime{Time: *request.EndsAt, Valid: true}
+	}
+
+	err := s.promotionRepo.Create(&promotion)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to create promotion, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.CreatePromotionResponse{
+		ID: promotion.ID,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// GetByID returns a promotion details by the ID from the database.
+func (s *promotionServiceImpl) GetByID(ctx context.Context, promotionID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(promotionID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	id, err := strconv.ParseInt(promotionID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "GetByID")
+
+	promotion, err := s.promotionRepo.GetByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get promotion by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if promotion == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	resp := model.GetPromotionResponse{
+		ID:               promotion.ID,
+		Code:             promotion.Code.String,
+		Name:             promotion.Name,
+		Type:             promotion.Type,
+		Value:            promotion.Value,
+		BuyQuantity:      promotion.BuyQuantity,
+		GetQuantity:      promotion.GetQuantity,
+		Scope:            promotion.Scope,
+		BrandID:          promotion.BrandID.Int64,
+		SKU:              promotion.SKU.String,
+		Stackable:        promotion.Stackable,
+		Priority:         promotion.Priority,
+		StartsAt:         utils.TimePtr(promotion.StartsAt),
+		EndsAt:           utils.TimePtr(promotion.EndsAt),
+		UsageLimit:       promotion.UsageLimit.Int64,
+		PerCustomerLimit: promotion.PerCustomerLimit.Int64,
+		UsageCount:       promotion.UsageCount,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// normalizeCouponCode returns the stored form of a coupon code.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// promotionLine is an order line the promotion engine computes discounts on.
+type promotionLine struct {
+	sku      string
+	brandID  int64
+	quantity int64
+	price    model.Money
+	amount   model.Money // amount left after the discounts applied so far
+	discount model.Money
+}
+
+// eligible returns true when a promotion applies to the line.
+func (l *promotionLine) eligible(promotion *model.Promotion) bool {
+	switch promotion.Scope {
+	case model.PromotionScopeBrand:
+		return promotion.BrandID.Valid && promotion.BrandID.Int64 == l.brandID
+	case model.PromotionScopeSKU:
+		return promotion.SKU.Valid && strings.EqualFold(promotion.SKU.String, l.sku)
+	}
+	return true
+}
+
+// applyPromotions applies promotions to the lines, highest priority first, and
+// returns the discounts given. Stacking rules:
+//   - a stackable promotion is combined with every other stackable promotion,
+//     each one computed on the amount left by the previous ones;
+//   - a promotion that is not stackable is only applied when nothing has been
+//     applied before it, and nothing is applied after it.
+//
+// Discounts are rounded to the currency, never exceed the eligible amount and
+// are split over the eligible lines in proportion to their amount.
+func applyPromotions(lines []*promotionLine, promotions []*model.Promotion, currency string) []model.AppliedDiscount {
+	sorted := make([]*model.Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	applied := make([]model.AppliedDiscount, 0)
+	for _, promotion := range sorted {
+		if !promotion.Stackable && len(applied) > 0 {
+			continue
+		}
+
+		var eligibleAmount model.Money
+		eligible := make([]*promotionLine, 0)
+		for _, line := range lines {
+			if line.eligible(promotion) && !line.amount.IsZero() {
+				eligible = append(eligible, line)
+				eligibleAmount = eligibleAmount.Add(line.amount)
+			}
+		}
+
+		if len(eligible) == 0 {
+			continue
+		}
+
+		discount := computeDiscount(promotion, eligible, eligibleAmount).Round(currency).Min(eligibleAmount)
+		if discount.IsZero() || discount.IsNegative() {
+			continue
+		}
+
+		left := discount
+		for index, line := range eligible {
+			share := discount.Share(line.amount, eligibleAmount).Round(currency)
+			if index == len(eligible)-1 {
+				share = left
+			}
+			share = share.Min(line.amount).Min(left)
+
+			line.amount = line.amount.Sub(share)
+			line.discount = line.discount.Add(share)
+			left = left.Sub(share)
+		}
+
+		applied = append(applied, model.AppliedDiscount{
+			PromotionID: promotion.ID,
+			Code:        promotion.Code.String,
+			Name:        promotion.Name,
+			Type:        promotion.Type,
+			Amount:      discount.Sub(left),
+		})
+
+		if !promotion.Stackable {
+			break
+		}
+	}
+
+	return applied
+}
+
+// computeDiscount returns the discount of a promotion on the eligible lines before rounding.
+func computeDiscount(promotion *model.Promotion, eligible []*promotionLine, eligibleAmount model.Money) model.Money {
+	switch promotion.Type {
+	case model.PromotionTypePercentage:
+		return eligibleAmount.Percent(promotion.Value)
+	case model.PromotionTypeFixedAmount:
+		return promotion.Value
+	case model.PromotionTypeBuyXGetY:
+		group := promotion.BuyQuantity + promotion.GetQuantity
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return model.Money{}
+		}
+
+		// the cheapest units are the free ones
+		cheapest := make([]*promotionLine, len(eligible))
+		copy(cheapest, eligible)
+		sort.SliceStable(cheapest, func(i, j int) bool {
+			return cheapest[i].price.Cmp(cheapest[j].price) < 0
+		})
+
+		var units int64
+		for _, line := range cheapest {
+			units += line.quantity
+		}
+
+		var discount model.Money
+		free := units / group * promotion.GetQuantity
+		for _, line := range cheapest {
+			if free == 0 {
+				break
+			}
+
+			quantity := line.quantity
+			if quantity > free {
+				quantity = free
+			}
+			// never more than the amount of the line, so it can not overflow
+			amount, _ := line.price.Mul(quantity)
+			discount = discount.Add(amount)
+			free -= quantity
+		}
+		return discount
+	}
+	return model.Money{}
+}
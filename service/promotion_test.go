@@ -0,0 +1,363 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	prepare()
+
+	// TestCreatePromotionInvalidRequest
+	func(t *testing.T) {
+		promotionService := service.NewPromotionService()
+
+		// Case: empty name
+		req := model.CreatePromotionRequest{
+			Type:  model.PromotionTypePercentage,
+			Value: model.NewMoney(10),
+		}
+		httpCode, resp := promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: percentage above 100
+		req = model.CreatePromotionRequest{
+			Name:  "Big sale",
+			Type:  model.PromotionTypePercentage,
+			Value: model.NewMoney(150),
+		}
+		httpCode, resp = promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: buy x get y without quantities
+		req = model.CreatePromotionRequest{
+			Name: "Buy 2 get 1",
+			Type: model.PromotionTypeBuyXGetY,
+		}
+		httpCode, resp = promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: brand scope without brand
+		req = model.CreatePromotionRequest{
+			Name:  "Brand sale",
+			Type:  model.PromotionTypeFixedAmount,
+			Value: model.NewMoney(5000),
+			Scope: model.PromotionScopeBrand,
+		}
+		httpCode, resp = promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestCreatePromotionDuplicateCode
+	func(t *testing.T) {
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		promotionService := service.NewPromotionService().
+			SetPromotionRepo(mockPromotionRepo)
+
+		req := model.CreatePromotionRequest{
+			Code:  " save10 ",
+			Name:  "Save 10%",
+			Type:  model.PromotionTypePercentage,
+			Value: model.NewMoney(10),
+		}
+		mockPromotionRepo.On("GetByCode", "SAVE10").Return(&model.Promotion{ID: 1}, nil)
+		httpCode, resp := promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+		mockPromotionRepo.AssertNumberOfCalls(t, "Create", 0)
+	}(t)
+
+	// TestCreatePromotionSuccess
+	func(t *testing.T) {
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		promotionService := service.NewPromotionService().
+			SetPromotionRepo(mockPromotionRepo)
+
+		req := model.CreatePromotionRequest{
+			Code:  "save10",
+			Name:  "Save 10%",
+			Type:  model.PromotionTypePercentage,
+			Value: model.NewMoney(10),
+		}
+		mockPromotionRepo.On("GetByCode", "SAVE10").Return(nil, nil)
+		mockPromotionRepo.On("Create", mock.MatchedBy(func(promotion *model.Promotion) bool {
+			return promotion.Code.String == "SAVE10" && promotion.Scope == model.PromotionScopeOrder
+		})).Return(nil)
+		httpCode, resp := promotionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.RawMessage)
+		mockPromotionRepo.AssertNumberOfCalls(t, "Create", 1)
+	}(t)
+}
+
+func TestGetPromotion(t *testing.T) {
+	prepare()
+
+	// TestGetPromotionInvalidID
+	func(t *testing.T) {
+		promotionService := service.NewPromotionService()
+
+		httpCode, resp := promotionService.GetByID(context.Background(), "abc")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestGetPromotionNotFound
+	func(t *testing.T) {
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		promotionService := service.NewPromotionService().
+			SetPromotionRepo(mockPromotionRepo)
+
+		mockPromotionRepo.On("GetByID", int64(1)).Return(nil, nil)
+		httpCode, resp := promotionService.GetByID(context.Background(), "1")
+		assert.Equal(t, httpCode, http.StatusNotFound)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestGetPromotionSuccess
+	func(t *testing.T) {
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		promotionService := service.NewPromotionService().
+			SetPromotionRepo(mockPromotionRepo)
+
+		mockPromotionRepo.On("GetByID", int64(1)).Return(&model.Promotion{
+			ID:    1,
+			Code:  sql.NullString{String: "SAVE10", Valid: true},
+			Name:  "Save 10%",
+			Type:  model.PromotionTypePercentage,
+			Value: model.NewMoney(10),
+			Scope: model.PromotionScopeOrder,
+		}, nil)
+		httpCode, resp := promotionService.GetByID(context.Background(), "1")
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.GetPromotionResponse)
+		assert.Equal(t, "SAVE10", result.Code)
+	}(t)
+}
+
+func TestApplyPromotion(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.ProductRepository, *repoMock.PromotionRepository, service.TransactionService) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetCurrency("IDR")
+
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, mock.Anything).Return([]*model.Product{
+			{ID: 1, BrandID: 1, SKU: "sku-a", Stock: 10, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+			{ID: 2, BrandID: 2, SKU: "sku-b", Stock: 10, Price: model.NewMoney(5000), Status: model.ProductStatusActive},
+		}, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.Anything).Return(nil)
+		mockPromotionRepo.On("RecordUsageTx", mock.Anything, mock.Anything).Return(nil)
+		return mockProductRepo, mockPromotionRepo, transactionService
+	}
+
+	req := model.CreateTransactionRequest{
+		CustomerID: 7,
+		Items: []model.TransactionItem{
+			{SKU: "sku-a", Quantity: 2},
+			{SKU: "sku-b", Quantity: 1},
+		},
+		CouponCodes: []string{"save10"},
+	}
+
+	// TestApplyPromotionInvalidCoupon
+	func(t *testing.T) {
+		mockProductRepo, mockPromotionRepo, transactionService := newService()
+
+		// Case: coupon not found or expired
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{"SAVE10"}, mock.Anything).Return([]*model.Promotion{}, nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 0)
+	}(t)
+
+	// TestApplyPromotionUsageLimit
+	func(t *testing.T) {
+		_, mockPromotionRepo, transactionService := newService()
+
+		// Case: customer already used the coupon
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{"SAVE10"}, mock.Anything).Return([]*model.Promotion{
+			{
+				ID:               1,
+				Code:             sql.NullString{String: "SAVE10", Valid: true},
+				Type:             model.PromotionTypePercentage,
+				Value:            model.NewMoney(10),
+				Scope:            model.PromotionScopeOrder,
+				PerCustomerLimit: sql.NullInt64{Int64: 1, Valid: true},
+			},
+		}, nil)
+		mockPromotionRepo.On("LockTx", mock.Anything, int64(1)).Return(nil)
+		mockPromotionRepo.On("CountCustomerUsageTx", mock.Anything, int64(1), int64(7)).Return(int64(1), nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockPromotionRepo.AssertNumberOfCalls(t, "LockTx", 1)
+		mockPromotionRepo.AssertNumberOfCalls(t, "RecordUsageTx", 0)
+	}(t)
+
+	// TestApplyPromotionUsageLimitReached
+	func(t *testing.T) {
+		newLimitedService := func(promotion *model.Promotion) (*repoMock.PromotionRepository, service.TransactionService) {
+			mockTransactionRepo := new(repoMock.TransactionRepository)
+			mockProductRepo := new(repoMock.ProductRepository)
+			mockOrderRepo := new(repoMock.OrderRepository)
+			mockPromotionRepo := new(repoMock.PromotionRepository)
+			mockOutboxRepo := new(repoMock.OutboxRepository)
+			mockTxRepo := new(repoMock.TxRepository)
+			transactionService := service.NewTransactionService().
+				SetTransactionRepo(mockTransactionRepo).
+				SetProductRepo(mockProductRepo).
+				SetOrderRepo(mockOrderRepo).
+				SetPromotionRepo(mockPromotionRepo).
+				SetOutboxRepo(mockOutboxRepo).
+				SetTxRepo(mockTxRepo).
+				SetCurrency("IDR")
+
+			mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+			mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, mock.Anything).Return([]*model.Product{
+				{ID: 1, BrandID: 1, SKU: "sku-a", Stock: 10, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+				{ID: 2, BrandID: 2, SKU: "sku-b", Stock: 10, Price: model.NewMoney(5000), Status: model.ProductStatusActive},
+			}, nil)
+			mockPromotionRepo.On("GetActiveTx", mock.Anything, mock.Anything, mock.Anything).Return([]*model.Promotion{promotion}, nil)
+			mockPromotionRepo.On("RecordUsageTx", mock.Anything, mock.Anything).Return(repository.ErrPromotionUsageLimit)
+			return mockPromotionRepo, transactionService
+		}
+
+		// Case: a concurrent order used the last coupon
+		mockPromotionRepo, transactionService := newLimitedService(&model.Promotion{
+			ID:         1,
+			Code:       sql.NullString{String: "SAVE10", Valid: true},
+			Name:       "Save 10%",
+			Type:       model.PromotionTypePercentage,
+			Value:      model.NewMoney(10),
+			Scope:      model.PromotionScopeOrder,
+			UsageLimit: sql.NullInt64{Int64: 100, Valid: true},
+			UsageCount: 99,
+		})
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "coupon SAVE10 has reached its usage limit")
+		mockPromotionRepo.AssertNumberOfCalls(t, "LockTx", 0)
+
+		// Case: a concurrent order used the last automatic promotion
+		_, transactionService = newLimitedService(&model.Promotion{
+			ID:         2,
+			Name:       "Flash Sale",
+			Type:       model.PromotionTypeFixedAmount,
+			Value:      model.NewMoney(1000),
+			Scope:      model.PromotionScopeOrder,
+			UsageLimit: sql.NullInt64{Int64: 100, Valid: true},
+			UsageCount: 99,
+		})
+		httpCode, resp = transactionService.Create(context.Background(), model.CreateTransactionRequest{
+			Items: req.Items,
+		})
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, "promotion Flash Sale has reached its usage limit")
+	}(t)
+
+	// TestApplyPromotionStackable
+	func(t *testing.T) {
+		_, mockPromotionRepo, transactionService := newService()
+
+		// Case: automatic brand discount first, then the coupon on what is left
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{"SAVE10"}, mock.Anything).Return([]*model.Promotion{
+			{
+				ID:        1,
+				Code:      sql.NullString{String: "SAVE10", Valid: true},
+				Type:      model.PromotionTypePercentage,
+				Value:     model.NewMoney(10),
+				Scope:     model.PromotionScopeOrder,
+				Stackable: true,
+			},
+			{
+				ID:        2,
+				Type:      model.PromotionTypeFixedAmount,
+				Value:     model.NewMoney(5000),
+				Scope:     model.PromotionScopeBrand,
+				BrandID:   sql.NullInt64{Int64: 1, Valid: true},
+				Stackable: true,
+				Priority:  10,
+			},
+		}, nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.CreateTransactionResponse)
+		assert.Equal(t, model.NewMoney(25000), result.SubtotalPrice)
+		assert.Len(t, result.Discounts, 2)
+		assert.Equal(t, int64(2), result.Discounts[0].PromotionID)
+		assert.Equal(t, model.NewMoney(5000), result.Discounts[0].Amount)
+		assert.Equal(t, model.NewMoney(2000), result.Discounts[1].Amount)
+		assert.Equal(t, model.NewMoney(7000), result.DiscountAmount)
+		assert.Equal(t, model.NewMoney(18000), result.TotalPrice)
+		assert.Equal(t, model.NewMoney(13500), result.Items[0].Subtotal)
+		assert.Equal(t, model.NewMoney(4500), result.Items[1].Subtotal)
+		mockPromotionRepo.AssertNumberOfCalls(t, "RecordUsageTx", 2)
+	}(t)
+
+	// TestApplyPromotionNotStackable
+	func(t *testing.T) {
+		_, mockPromotionRepo, transactionService := newService()
+
+		// Case: buy 2 get 1 is not combined with the coupon, the cheapest unit is free
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{"SAVE10"}, mock.Anything).Return([]*model.Promotion{
+			{
+				ID:          1,
+				Type:        model.PromotionTypeBuyXGetY,
+				BuyQuantity: 2,
+				GetQuantity: 1,
+				Scope:       model.PromotionScopeOrder,
+				Priority:    10,
+			},
+			{
+				ID:        2,
+				Code:      sql.NullString{String: "SAVE10", Valid: true},
+				Type:      model.PromotionTypePercentage,
+				Value:     model.NewMoney(10),
+				Scope:     model.PromotionScopeOrder,
+				Stackable: true,
+			},
+		}, nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.CreateTransactionResponse)
+		assert.Len(t, result.Discounts, 1)
+		assert.Equal(t, model.NewMoney(5000), result.DiscountAmount)
+		assert.Equal(t, model.NewMoney(20000), result.TotalPrice)
+		mockPromotionRepo.AssertNumberOfCalls(t, "RecordUsageTx", 1)
+	}(t)
+}
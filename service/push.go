@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+)
+
+// PushChannel notifies the customer's app about the events shown in the inbox.
+// Events without a customer, or whose customer has no push token, are skipped.
+type PushChannel struct {
+	customerRepo repository.CustomerRepository
+	sender       *notifier.PushSender
+	guard        *notifier.Guard
+}
+
+// NewPushChannel returns new instance of PushChannel.
+func NewPushChannel() *PushChannel {
+	return &PushChannel{}
+}
+
+// SetCustomerRepo injects customer's repo for PushChannel.
+func (c *PushChannel) SetCustomerRepo(repo repository.CustomerRepository) *PushChannel {
+	c.customerRepo = repo
+	return c
+}
+
+// SetSender sets the client sending push notifications.
+func (c *PushChannel) SetSender(sender *notifier.PushSender) *PushChannel {
+	c.sender = sender
+	return c
+}
+
+// SetGuard sets the rate limit and circuit breaker of the push API.
+func (c *PushChannel) SetGuard(guard *notifier.Guard) *PushChannel {
+	c.guard = guard
+	return c
+}
+
+// Validate validates if all dependency for PushChannel is complete.
+func (c *PushChannel) Validate() *PushChannel {
+	if c.customerRepo == nil {
+		log.Panic("Push channel need customer repository")
+	}
+	if c.sender == nil {
+		log.Panic("Push channel need sender")
+	}
+	if c.guard == nil {
+		log.Panic("Push channel need guard")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *PushChannel) Name() string {
+	return "push"
+}
+
+// Send pushes the inbox message of the event to the customer's app, with the
+// inbox data so the app can open the right screen. A token the push API no
+// longer knows is removed from the customer.
+func (c *PushChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	var recipient struct {
+		CustomerID int64 `json:"customer_id"`
+	}
+	if json.Unmarshal(event.Payload, &recipient) != nil || recipient.CustomerID == 0 {
+		return nil
+	}
+
+	title, body, data, ok := inboxMessage(event.EventType, event.Payload)
+	if !ok {
+		return nil
+	}
+
+	customer, err := c.customerRepo.GetByID(recipient.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil || !customer.PushToken.Valid {
+		return nil
+	}
+
+	push := notifier.Push{
+		Token: customer.PushToken.String,
+		Title: title,
+		Body:  body,
+		Data:  pushData(data),
+	}
+	push.Data["event_id"] = strconv.FormatInt(event.OriginID(), 10)
+	push.Data["type"] = string(event.EventType)
+
+	err = c.guard.Do(func() error {
+		return c.sender.Send(ctx, push)
+	})
+	if !errors.Is(err, notifier.ErrPushTokenInvalid) {
+		return err
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Send")
+	log.Info(fmt.Sprintf("removing push token of customer %d, err : %s", customer.ID, err.Error()))
+
+	return c.customerRepo.ClearPushToken(customer.ID, push.Token)
+}
+
+// pushData returns the inbox data of an event as the string values push
+// notifications carry, values that are not strings are kept as JSON.
+func pushData(data interface{}) map[string]string {
+	values := make(map[string]string)
+
+	raw, _ := json.Marshal(data)
+	var fields map[string]json.RawMessage
+	json.Unmarshal(raw, &fields)
+
+	for key, field := range fields {
+		var value string
+		if json.Unmarshal(field, &value) != nil {
+			value = string(field)
+		}
+		values[key] = value
+	}
+	return values
+}
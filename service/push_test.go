@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/pkg/notifier/pushtest"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushChannel(t *testing.T) {
+	prepare()
+
+	server := pushtest.NewServer()
+	defer server.Close()
+
+	payload, _ := json.Marshal(model.OrderEvent{OrderID: "ORD-001", CustomerID: 7, Status: model.OrderStatusPaid})
+	event := &model.OutboxEvent{ID: 1, EventType: model.OutboxEventOrderStatusChanged, Payload: payload}
+
+	newChannel := func(guard *notifier.Guard) (*repoMock.CustomerRepository, *service.PushChannel) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		channel := service.NewPushChannel().
+			SetCustomerRepo(mockCustomerRepo).
+			SetSender(notifier.NewPushSender(server.URL(), "server-key", time.Second)).
+			SetGuard(guard).
+			Validate()
+		return mockCustomerRepo, channel
+	}
+
+	// TestPushChannelSend
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("push", 0, 1, 0, time.Minute))
+
+		// Case: the inbox message is pushed with its data
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{
+			ID:        7,
+			PushToken: sql.NullString{String: "device-1", Valid: true},
+		}, nil)
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		messages := server.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, messages[0].ServerKey, "server-key")
+		assert.Equal(t, messages[0].Token, "device-1")
+		assert.NotEmpty(t, messages[0].Title)
+		assert.Contains(t, messages[0].Body, "ORD-001")
+		assert.Equal(t, messages[0].Data, map[string]string{
+			"order_id": "ORD-001",
+			"status":   string(model.OrderStatusPaid),
+			"event_id": "1",
+			"type":     string(model.OutboxEventOrderStatusChanged),
+		})
+	}(t)
+
+	// TestPushChannelUnregistered
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("push", 0, 1, 1, time.Minute))
+
+		// Case: a token the push API does not know is removed without opening the breaker
+		server.Unregister("device-2")
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{
+			ID:        7,
+			PushToken: sql.NullString{String: "device-2", Valid: true},
+		}, nil)
+		mockCustomerRepo.On("ClearPushToken", int64(7), "device-2").Return(nil)
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		mockCustomerRepo.AssertNumberOfCalls(t, "ClearPushToken", 2)
+	}(t)
+
+	// TestPushChannelFailed
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("push", 0, 1, 1, time.Minute))
+		defer server.SetStatus(0)
+
+		// Case: an unavailable push API is retried later
+		server.SetStatus(http.StatusInternalServerError)
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{
+			ID:        7,
+			PushToken: sql.NullString{String: "device-1", Valid: true},
+		}, nil)
+		err := channel.Send(context.Background(), event)
+		assert.NotNil(t, err)
+
+		err = channel.Send(context.Background(), event)
+		assert.True(t, errors.Is(err, notifier.ErrCircuitOpen))
+		mockCustomerRepo.AssertNotCalled(t, "ClearPushToken", int64(7), "device-1")
+	}(t)
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// reminderEventTypes defines the outbox event of each reminder kind.
+var reminderEventTypes = map[model.ReminderKind]model.OutboxEventType{
+	model.ReminderKindCart:  model.OutboxEventCartReminder,
+	model.ReminderKindOrder: model.OutboxEventOrderReminder,
+}
+
+// ReminderService reminds customers of idle carts and unpaid orders.
+type ReminderService interface {
+	Run(ctx context.Context)
+	RunDue(ctx context.Context) (int, error)
+	Unsubscribe(ctx context.Context, token string) (int, *model.BaseResponse)
+}
+
+type reminderServiceImpl struct {
+	reminderRepo   repository.ReminderRepository
+	cartRepo       repository.CartRepository
+	orderRepo      repository.OrderRepository
+	outboxRepo     repository.OutboxRepository
+	txRepo         repository.TxRepository
+	schedules      map[model.ReminderKind][]time.Duration
+	pollInterval   time.Duration
+	batchSize      int
+	unsubscribeURL string
+}
+
+// NewReminderService returns new instance of reminderServiceImpl.
+func NewReminderService() *reminderServiceImpl {
+	return &reminderServiceImpl{
+		schedules: make(map[model.ReminderKind][]time.Duration),
+	}
+}
+
+// SetReminderRepo injects reminder's repo for reminderServiceImpl.
+func (s *reminderServiceImpl) SetReminderRepo(repo repository.ReminderRepository) *reminderServiceImpl {
+	s.reminderRepo = repo
+	return s
+}
+
+// SetCartRepo injects cart's repo for reminderServiceImpl.
+func (s *reminderServiceImpl) SetCartRepo(repo repository.CartRepository) *reminderServiceImpl {
+	s.cartRepo = repo
+	return s
+}
+
+// SetOrderRepo injects order's repo for reminderServiceImpl.
+func (s *reminderServiceImpl) SetOrderRepo(repo repository.OrderRepository) *reminderServiceImpl {
+	s.orderRepo = repo
+	return s
+}
+
+// SetOutboxRepo injects outbox's repo for reminderServiceImpl.
+func (s *reminderServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *reminderServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetTxRepo injects transaction runner for reminderServiceImpl.
+func (s *reminderServiceImpl) SetTxRepo(repo repository.TxRepository) *reminderServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
+// SetSchedule sets when the reminders of a kind are sent, counted from the last
+// activity on the target. The first one is also how long a target is idle
+// before its sequence starts. An empty schedule sends no reminder of the kind.
+func (s *reminderServiceImpl) SetSchedule(kind model.ReminderKind, schedule []time.Duration) *reminderServiceImpl {
+	s.schedules[kind] = schedule
+	return s
+}
+
+// SetPollInterval sets how often due reminders are checked.
+func (s *reminderServiceImpl) SetPollInterval(interval time.Duration) *reminderServiceImpl {
+	s.pollInterval = interval
+	return s
+}
+
+// SetBatchSize sets how many targets are enrolled, and how many reminders are
+// sent, at once.
+func (s *reminderServiceImpl) SetBatchSize(size int) *reminderServiceImpl {
+	s.batchSize = size
+	return s
+}
+
+// SetUnsubscribeURL sets the page reminders link to opt out, the token is added
+// as the token query parameter.
+func (s *reminderServiceImpl) SetUnsubscribeURL(unsubscribeURL string) *reminderServiceImpl {
+	s.unsubscribeURL = unsubscribeURL
+	return s
+}
+
+// Validate validates if all dependency for reminderServiceImpl is complete.
+func (s *reminderServiceImpl) Validate() *reminderServiceImpl {
+	if s.reminderRepo == nil {
+		log.Panic("Reminder service need reminder repository")
+	}
+	if s.cartRepo == nil {
+		log.Panic("Reminder service need cart repository")
+	}
+	if s.orderRepo == nil {
+		log.Panic("Reminder service need order repository")
+	}
+	if s.outboxRepo == nil {
+		log.Panic("Reminder service need outbox repository")
+	}
+	if s.txRepo == nil {
+		log.Panic("Reminder service need tx repository")
+	}
+	for kind, schedule := range s.schedules {
+		for index, delay := range schedule {
+			if delay <= 0 || (index > 0 && delay <= schedule[index-1]) {
+				log.Panic(fmt.Sprintf("Reminder service need increasing %s schedule", kind))
+			}
+		}
+	}
+	if s.pollInterval <= 0 {
+		log.Panic("Reminder service need poll interval")
+	}
+	if s.batchSize <= 0 {
+		log.Panic("Reminder service need batch size")
+	}
+	if s.unsubscribeURL == "" {
+		log.Panic("Reminder service need unsubscribe URL")
+	}
+	return s
+}
+
+// Run sends due reminders until ctx is done. A full batch is followed by the next
+// one right away, otherwise reminders are checked again after the interval.
+func (s *reminderServiceImpl) Run(ctx context.Context) {
+	log := logger.GetLoggerContext(ctx, "service", "Run")
+
+	for {
+		count, err := s.RunDue(ctx)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to send reminders, err : %s", err.Error()))
+		}
+
+		wait := s.pollInterval
+		if err == nil && count == s.batchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RunDue starts the sequences of idle targets, then sends one batch of due
+// reminders and returns how many were claimed. Several instances may run it at
+// once, a target gets one sequence and a reminder is claimed by one instance.
+func (s *reminderServiceImpl) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	err := s.enroll(now)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		reminders, err := s.reminderRepo.ClaimDueTx(tx, now, s.batchSize)
+		if err != nil {
+			return err
+		}
+		count = len(reminders)
+
+		for _, reminder := range reminders {
+			err = s.advance(tx, reminder, now)
+			if err != nil {
+				return fmt.Errorf("reminder %d: %s", reminder.ID, err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// enroll starts the reminder sequence of the carts and orders idle past the
+// first reminder of their schedule.
+func (s *reminderServiceImpl) enroll(now time.Time) error {
+	for _, kind := range []model.ReminderKind{model.ReminderKindCart, model.ReminderKindOrder} {
+		schedule := s.schedules[kind]
+		if len(schedule) == 0 {
+			continue
+		}
+
+		var targets []*model.ReminderTarget
+		var err error
+		if kind == model.ReminderKindCart {
+			targets, err = s.reminderRepo.ListIdleCarts(now, now.Add(-schedule[0]), s.batchSize)
+		} else {
+			targets, err = s.reminderRepo.ListIdleOrders(now.Add(-schedule[0]), s.batchSize)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, target := range targets {
+			token, err := generateReminderToken()
+			if err != nil {
+				return err
+			}
+
+			err = s.reminderRepo.Create(&model.Reminder{
+				Kind:             kind,
+				TargetID:         target.TargetID,
+				CustomerID:       target.CustomerID,
+				IdleSince:        target.LastActivityAt,
+				NextAt:           target.LastActivityAt.Add(schedule[0]),
+				Status:           model.ReminderStatusActive,
+				UnsubscribeToken: token,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// advance sends the due reminder of a sequence and schedules the next one. The
+// sequence stops when its target no longer waits for the customer, and starts
+// over when the customer came back to the target.
+func (s *reminderServiceImpl) advance(tx *sqlx.Tx, reminder *model.Reminder, now time.Time) error {
+	schedule := s.schedules[reminder.Kind]
+	if reminder.Step >= len(schedule) {
+		reminder.Status = model.ReminderStatusFinished
+		return s.reminderRepo.UpdateTx(tx, reminder)
+	}
+
+	waiting, idleSince, err := s.targetState(reminder, now)
+	if err != nil {
+		return err
+	}
+
+	if !waiting {
+		reminder.Status = model.ReminderStatusStopped
+		return s.reminderRepo.UpdateTx(tx, reminder)
+	}
+
+	if idleSince.After(reminder.IdleSince) {
+		reminder.Step = 0
+		reminder.IdleSince = idleSince
+		reminder.NextAt = idleSince.Add(schedule[0])
+		return s.reminderRepo.UpdateTx(tx, reminder)
+	}
+
+	// reminders missed while no instance was running are not sent one after another
+	for reminder.Step+1 < len(schedule) && !reminder.IdleSince.Add(schedule[reminder.Step+1]).After(now) {
+		reminder.Step++
+	}
+
+	payload := model.ReminderEvent{
+		ReminderID:     reminder.ID,
+		CustomerID:     reminder.CustomerID,
+		Kind:           reminder.Kind,
+		Step:           reminder.Step + 1,
+		IdleSince:      reminder.IdleSince,
+		UnsubscribeURL: s.unsubscribeLink(reminder.UnsubscribeToken),
+	}
+	if reminder.Kind == model.ReminderKindCart {
+		payload.CartID = reminder.TargetID
+	} else {
+		payload.OrderID = reminder.TargetID
+	}
+
+	err = writeOutboxEvent(tx, s.outboxRepo, reminderEventTypes[reminder.Kind], reminder.TargetID, payload)
+	if err != nil {
+		return err
+	}
+
+	reminder.Step++
+	reminder.LastSentAt = sql.NullTime{Time: now, Valid: true}
+	if reminder.Step >= len(schedule) {
+		reminder.Status = model.ReminderStatusFinished
+	} else {
+		reminder.NextAt = reminder.IdleSince.Add(schedule[reminder.Step])
+	}
+
+	return s.reminderRepo.UpdateTx(tx, reminder)
+}
+
+// targetState returns whether the target of a reminder still waits for the
+// customer, and the last activity on it.
+func (s *reminderServiceImpl) targetState(reminder *model.Reminder, now time.Time) (bool, time.Time, error) {
+	if reminder.Kind == model.ReminderKindOrder {
+		order, err := s.orderRepo.GetByOrderID(reminder.TargetID)
+		if err != nil || order == nil || order.Status != model.OrderStatusPending {
+			return false, time.Time{}, err
+		}
+		return true, order.CreatedAt, nil
+	}
+
+	cart, err := s.cartRepo.GetByCartID(reminder.TargetID)
+	if err != nil || cart == nil || cart.Status != model.CartStatusActive || !cart.ExpiresAt.After(now) {
+		return false, time.Time{}, err
+	}
+
+	items, err := s.cartRepo.GetItems(cart.CartID)
+	if err != nil || len(items) == 0 {
+		return false, time.Time{}, err
+	}
+
+	activity := latest(cart.CreatedAt, cart.UpdatedAt)
+	for _, item := range items {
+		activity = latest(latest(activity, sql.NullTime{Time: item.CreatedAt, Valid: true}), item.UpdatedAt)
+	}
+	return true, activity, nil
+}
+
+// unsubscribeLink returns the unsubscribe link of a token.
+func (s *reminderServiceImpl) unsubscribeLink(token string) string {
+	separator := "?"
+	if strings.Contains(s.unsubscribeURL, "?") {
+		separator = "&"
+	}
+	return s.unsubscribeURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// Unsubscribe opts the customer of an unsubscribe token out of every reminder.
+func (s *reminderServiceImpl) Unsubscribe(ctx context.Context, token string) (int, *model.BaseResponse) {
+	token = strings.TrimSpace(token)
+
+	// validate request
+	if token == "" {
+		return utils.RequestRequired("token")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Unsubscribe")
+
+	reminder, err := s.reminderRepo.GetByToken(token)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get reminder by token, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if reminder == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	err = s.reminderRepo.OptOut(reminder.CustomerID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to opt out of reminders, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{}
+}
+
+// latest returns the later of t and a nullable time.
+func latest(t time.Time, other sql.NullTime) time.Time {
+	if other.Valid && other.Time.After(t) {
+		return other.Time
+	}
+	return t
+}
+
+// generateReminderToken returns a random unsubscribe token.
+func generateReminderToken() (string, error) {
+	raw := make([]byte, 24)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunReminders(t *testing.T) {
+	prepare()
+
+	schedule := []time.Duration{time.Hour, 24 * time.Hour, 72 * time.Hour}
+
+	// TestRunRemindersEnroll
+	func(t *testing.T) {
+		mockReminderRepo := new(repoMock.ReminderRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		reminderService := service.NewReminderService().
+			SetReminderRepo(mockReminderRepo).
+			SetCartRepo(new(repoMock.CartRepository)).
+			SetOrderRepo(new(repoMock.OrderRepository)).
+			SetOutboxRepo(new(repoMock.OutboxRepository)).
+			SetTxRepo(mockTxRepo).
+			SetSchedule(model.ReminderKindCart, schedule).
+			SetPollInterval(time.Minute).
+			SetBatchSize(10).
+			SetUnsubscribeURL("http://localhost/reminder/unsubscribe").
+			Validate()
+
+		// Case: idle carts start a sequence, orders have no schedule
+		idleSince := time.Now().Add(-2 * time.Hour)
+		mockReminderRepo.On("ListIdleCarts", mock.Anything, mock.Anything, 10).
+			Return([]*model.ReminderTarget{{TargetID: "CART-1", CustomerID: 7, LastActivityAt: idleSince}}, nil)
+		mockReminderRepo.On("Create", mock.MatchedBy(func(reminder *model.Reminder) bool {
+			return reminder.Kind == model.ReminderKindCart && reminder.TargetID == "CART-1" &&
+				reminder.NextAt.Equal(idleSince.Add(time.Hour)) && len(reminder.UnsubscribeToken) == 48
+		})).Return(nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockReminderRepo.On("ClaimDueTx", mock.Anything, mock.Anything, 10).Return([]*model.Reminder{}, nil)
+		count, err := reminderService.RunDue(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, count, 0)
+		mockReminderRepo.AssertNumberOfCalls(t, "Create", 1)
+		mockReminderRepo.AssertNotCalled(t, "ListIdleOrders", mock.Anything, mock.Anything)
+	}(t)
+
+	// TestRunRemindersFailed
+	func(t *testing.T) {
+		mockReminderRepo := new(repoMock.ReminderRepository)
+		reminderService := service.NewReminderService().
+			SetReminderRepo(mockReminderRepo).
+			SetSchedule(model.ReminderKindOrder, schedule).
+			SetBatchSize(10)
+
+		mockReminderRepo.On("ListIdleOrders", mock.Anything, 10).Return(nil, errors.New("error"))
+		_, err := reminderService.RunDue(context.Background())
+		assert.NotNil(t, err)
+	}(t)
+
+	// TestRunRemindersSend
+	func(t *testing.T) {
+		mockReminderRepo := new(repoMock.ReminderRepository)
+		mockCartRepo := new(repoMock.CartRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		reminderService := service.NewReminderService().
+			SetReminderRepo(mockReminderRepo).
+			SetCartRepo(mockCartRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetSchedule(model.ReminderKindCart, schedule).
+			SetSchedule(model.ReminderKindOrder, schedule).
+			SetBatchSize(10).
+			SetUnsubscribeURL("http://localhost/reminder/unsubscribe")
+
+		now := time.Now()
+		orderCreated := now.Add(-2 * time.Hour)
+		unpaid := &model.Reminder{ID: 1, Kind: model.ReminderKindOrder, TargetID: "ORDER-1", CustomerID: 7,
+			IdleSince: orderCreated, NextAt: orderCreated.Add(time.Hour), Status: model.ReminderStatusActive, UnsubscribeToken: "token-1"}
+		paid := &model.Reminder{ID: 2, Kind: model.ReminderKindOrder, TargetID: "ORDER-2", CustomerID: 7,
+			IdleSince: orderCreated, Step: 1, Status: model.ReminderStatusActive}
+		cartIdle := now.Add(-80 * time.Hour)
+		returned := &model.Reminder{ID: 3, Kind: model.ReminderKindCart, TargetID: "CART-3", CustomerID: 8,
+			IdleSince: cartIdle, Status: model.ReminderStatusActive}
+		overdue := &model.Reminder{ID: 4, Kind: model.ReminderKindCart, TargetID: "CART-4", CustomerID: 9,
+			IdleSince: cartIdle, Status: model.ReminderStatusActive, UnsubscribeToken: "token-4"}
+
+		mockReminderRepo.On("ListIdleCarts", mock.Anything, mock.Anything, 10).Return([]*model.ReminderTarget{}, nil)
+		mockReminderRepo.On("ListIdleOrders", mock.Anything, 10).Return([]*model.ReminderTarget{}, nil)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockReminderRepo.On("ClaimDueTx", mock.Anything, mock.Anything, 10).
+			Return([]*model.Reminder{unpaid, paid, returned, overdue}, nil)
+		mockOrderRepo.On("GetByOrderID", "ORDER-1").Return(&model.Order{OrderID: "ORDER-1", Status: model.OrderStatusPending, CreatedAt: orderCreated}, nil)
+		mockOrderRepo.On("GetByOrderID", "ORDER-2").Return(&model.Order{OrderID: "ORDER-2", Status: model.OrderStatusPaid, CreatedAt: orderCreated}, nil)
+		mockCartRepo.On("GetByCartID", "CART-3").Return(&model.Cart{CartID: "CART-3", Status: model.CartStatusActive, CreatedAt: cartIdle, ExpiresAt: now.Add(time.Hour)}, nil)
+		mockCartRepo.On("GetItems", "CART-3").Return([]*model.CartItem{
+			{SKU: "sku-test", CreatedAt: cartIdle, UpdatedAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true}},
+		}, nil)
+		mockCartRepo.On("GetByCartID", "CART-4").Return(&model.Cart{CartID: "CART-4", Status: model.CartStatusActive, CreatedAt: cartIdle, ExpiresAt: now.Add(time.Hour)}, nil)
+		mockCartRepo.On("GetItems", "CART-4").Return([]*model.CartItem{{SKU: "sku-test", CreatedAt: cartIdle}}, nil)
+
+		events := make([]*model.OutboxEvent, 0)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			events = append(events, args.Get(1).(*model.OutboxEvent))
+		}).Return(nil)
+		mockReminderRepo.On("UpdateTx", mock.Anything, mock.Anything).Return(nil)
+
+		count, err := reminderService.RunDue(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, count, 4)
+		mockReminderRepo.AssertNumberOfCalls(t, "UpdateTx", 4)
+
+		// Case: the first reminder of an unpaid order is sent
+		assert.Equal(t, unpaid.Step, 1)
+		assert.Equal(t, unpaid.Status, model.ReminderStatusActive)
+		assert.True(t, unpaid.NextAt.Equal(orderCreated.Add(24*time.Hour)))
+
+		// Case: a paid order stops the sequence
+		assert.Equal(t, paid.Status, model.ReminderStatusStopped)
+
+		// Case: activity on the cart starts the sequence over
+		assert.Equal(t, returned.Step, 0)
+		assert.True(t, returned.IdleSince.After(cartIdle))
+
+		// Case: only the latest missed reminder is sent, and it ends the sequence
+		assert.Equal(t, overdue.Step, 3)
+		assert.Equal(t, overdue.Status, model.ReminderStatusFinished)
+
+		assert.Len(t, events, 2)
+		var payload model.ReminderEvent
+		json.Unmarshal(events[0].Payload, &payload)
+		assert.Equal(t, events[0].EventType, model.OutboxEventOrderReminder)
+		assert.Equal(t, payload.OrderID, "ORDER-1")
+		assert.Equal(t, payload.Step, 1)
+		assert.True(t, strings.HasSuffix(payload.UnsubscribeURL, "?token=token-1"))
+
+		json.Unmarshal(events[1].Payload, &payload)
+		assert.Equal(t, events[1].EventType, model.OutboxEventCartReminder)
+		assert.Equal(t, payload.CartID, "CART-4")
+		assert.Equal(t, payload.Step, 3)
+	}(t)
+}
+
+func TestUnsubscribeReminder(t *testing.T) {
+	prepare()
+
+	mockReminderRepo := new(repoMock.ReminderRepository)
+	reminderService := service.NewReminderService().
+		SetReminderRepo(mockReminderRepo)
+
+	// Case: missing token
+	httpCode, _ := reminderService.Unsubscribe(context.Background(), " ")
+	assert.Equal(t, httpCode, http.StatusBadRequest)
+
+	// Case: unknown token
+	mockReminderRepo.On("GetByToken", "unknown").Return(nil, nil)
+	httpCode, _ = reminderService.Unsubscribe(context.Background(), "unknown")
+	assert.Equal(t, httpCode, http.StatusNotFound)
+
+	// Case: the customer is opted out of every reminder
+	mockReminderRepo.On("GetByToken", "token-1").Return(&model.Reminder{ID: 1, CustomerID: 7}, nil)
+	mockReminderRepo.On("OptOut", int64(7)).Return(nil)
+	httpCode, _ = reminderService.Unsubscribe(context.Background(), "token-1")
+	assert.Equal(t, httpCode, http.StatusOK)
+	mockReminderRepo.AssertNumberOfCalls(t, "OptOut", 1)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+)
+
+// SMSChannel texts the customer about the events shown in the inbox. Events
+// without a customer, or whose customer has no phone number, are skipped.
+type SMSChannel struct {
+	customerRepo repository.CustomerRepository
+	gateway      *notifier.SMSGateway
+	guard        *notifier.Guard
+}
+
+// NewSMSChannel returns new instance of SMSChannel.
+func NewSMSChannel() *SMSChannel {
+	return &SMSChannel{}
+}
+
+// SetCustomerRepo injects customer's repo for SMSChannel.
+func (c *SMSChannel) SetCustomerRepo(repo repository.CustomerRepository) *SMSChannel {
+	c.customerRepo = repo
+	return c
+}
+
+// SetGateway sets the client sending text messages.
+func (c *SMSChannel) SetGateway(gateway *notifier.SMSGateway) *SMSChannel {
+	c.gateway = gateway
+	return c
+}
+
+// SetGuard sets the rate limit and circuit breaker of the gateway.
+func (c *SMSChannel) SetGuard(guard *notifier.Guard) *SMSChannel {
+	c.guard = guard
+	return c
+}
+
+// Validate validates if all dependency for SMSChannel is complete.
+func (c *SMSChannel) Validate() *SMSChannel {
+	if c.customerRepo == nil {
+		log.Panic("SMS channel need customer repository")
+	}
+	if c.gateway == nil {
+		log.Panic("SMS channel need gateway")
+	}
+	if c.guard == nil {
+		log.Panic("SMS channel need guard")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *SMSChannel) Name() string {
+	return "sms"
+}
+
+// Send texts the inbox message of the event to the customer's phone.
+func (c *SMSChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	var recipient struct {
+		CustomerID int64 `json:"customer_id"`
+	}
+	if json.Unmarshal(event.Payload, &recipient) != nil || recipient.CustomerID == 0 {
+		return nil
+	}
+
+	title, body, _, ok := inboxMessage(event.EventType, event.Payload)
+	if !ok {
+		return nil
+	}
+
+	customer, err := c.customerRepo.GetByID(recipient.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil || !customer.Phone.Valid {
+		return nil
+	}
+
+	sms := notifier.SMS{
+		To:   customer.Phone.String,
+		Text: title + ": " + body,
+	}
+	return c.guard.Do(func() error {
+		return c.gateway.Send(ctx, sms)
+	})
+}
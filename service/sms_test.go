@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/pkg/notifier/smstest"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMSChannel(t *testing.T) {
+	prepare()
+
+	server := smstest.NewServer()
+	defer server.Close()
+
+	payload, _ := json.Marshal(model.OrderEvent{OrderID: "ORD-001", CustomerID: 7, Status: model.OrderStatusPending, TotalAmount: model.NewMoney(100000)})
+	event := &model.OutboxEvent{ID: 1, EventType: model.OutboxEventOrderCreated, Payload: payload}
+	customer := &model.Customer{ID: 7, Phone: sql.NullString{String: "+6281234567890", Valid: true}, Locale: "id"}
+
+	newChannel := func(guard *notifier.Guard) (*repoMock.CustomerRepository, *service.SMSChannel) {
+		mockCustomerRepo := new(repoMock.CustomerRepository)
+		channel := service.NewSMSChannel().
+			SetCustomerRepo(mockCustomerRepo).
+			SetGateway(notifier.NewSMSGateway(server.URL(), "secret", "Jamtangan", time.Second)).
+			SetGuard(guard).
+			Validate()
+		return mockCustomerRepo, channel
+	}
+
+	// TestSMSChannelSend
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("sms", 0, 1, 0, time.Minute))
+
+		// Case: the inbox message is texted to the customer's phone
+		mockCustomerRepo.On("GetByID", int64(7)).Return(customer, nil)
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		messages := server.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, messages[0].APIKey, "secret")
+		assert.Equal(t, messages[0].From, "Jamtangan")
+		assert.Equal(t, messages[0].To, "+6281234567890")
+		assert.Contains(t, messages[0].Text, "Order ORD-001 has been placed")
+	}(t)
+
+	// TestSMSChannelSkip
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("sms", 0, 1, 0, time.Minute))
+		sent := server.Requests()
+
+		// Case: event not shown in the inbox
+		err := channel.Send(context.Background(), &model.OutboxEvent{ID: 2, EventType: model.OutboxEventProductCreated, Payload: []byte(`{}`)})
+		assert.Nil(t, err)
+
+		// Case: customer without phone number
+		mockCustomerRepo.On("GetByID", int64(7)).Return(&model.Customer{ID: 7, Locale: "en"}, nil)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		assert.Equal(t, server.Requests(), sent)
+	}(t)
+
+	// TestSMSChannelRateLimit
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("sms", 1, 1, 0, time.Minute))
+		mockCustomerRepo.On("GetByID", int64(7)).Return(customer, nil)
+		sent := server.Requests()
+
+		// Case: sends over the burst are refused until a token is available
+		err := channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+
+		err = channel.Send(context.Background(), event)
+		var unavailable *notifier.UnavailableError
+		assert.True(t, errors.As(err, &unavailable))
+		assert.True(t, errors.Is(err, notifier.ErrRateLimited))
+		assert.True(t, unavailable.RetryAt.After(time.Now()))
+		assert.Equal(t, server.Requests(), sent+1)
+	}(t)
+
+	// TestSMSChannelCircuitBreaker
+	func(t *testing.T) {
+		mockCustomerRepo, channel := newChannel(notifier.NewGuard("sms", 0, 1, 2, 50*time.Millisecond))
+		mockCustomerRepo.On("GetByID", int64(7)).Return(customer, nil)
+		defer server.SetStatus(0)
+
+		// Case: rejected messages do not open the breaker
+		server.SetStatus(http.StatusBadRequest)
+		for i := 0; i < 3; i++ {
+			err := channel.Send(context.Background(), event)
+			assert.True(t, notifier.IsPermanent(err))
+		}
+
+		// Case: the breaker opens after consecutive failures of the gateway
+		server.SetStatus(http.StatusServiceUnavailable)
+		for i := 0; i < 2; i++ {
+			err := channel.Send(context.Background(), event)
+			assert.NotNil(t, err)
+			assert.False(t, notifier.IsPermanent(err))
+		}
+
+		sent := server.Requests()
+		err := channel.Send(context.Background(), event)
+		assert.True(t, errors.Is(err, notifier.ErrCircuitOpen))
+		assert.Equal(t, server.Requests(), sent)
+
+		// Case: after the cooldown a successful send closes the breaker
+		server.SetStatus(0)
+		time.Sleep(60 * time.Millisecond)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		err = channel.Send(context.Background(), event)
+		assert.Nil(t, err)
+		assert.Equal(t, server.Requests(), sent+2)
+	}(t)
+}
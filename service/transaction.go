@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/richardsahvic/jamtangan/domain/model"
 	"github.com/richardsahvic/jamtangan/domain/repository"
 	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/pubsub"
 	"github.com/richardsahvic/jamtangan/pkg/utils"
 )
 
@@ -17,10 +23,158 @@ import (
 type TransactionService interface {
 	Create(ctx context.Context, request model.CreateTransactionRequest) (int, *model.BaseResponse)
 	GetDetail(ctx context.Context, orderID string) (int, *model.BaseResponse)
+	UpdateStatus(ctx context.Context, request model.UpdateOrderStatusRequest) (int, *model.BaseResponse)
+	Cancel(ctx context.Context, request model.CancelOrderRequest) (int, *model.BaseResponse)
+	List(ctx context.Context, request model.ListOrderRequest) (int, *model.BaseResponse)
+	Delete(ctx context.Context, orderID string) (int, *model.BaseResponse)
+	Restore(ctx context.Context, orderID string) (int, *model.BaseResponse)
+}
+
+// List of order listing page size.
+const (
+	defaultOrderListLimit = 20
+	maxOrderListLimit     = 100
+)
+
+// orderTransitions defines the statuses an order is allowed to move to from its current status.
+var orderTransitions = map[model.OrderStatus][]model.OrderStatus{
+	model.OrderStatusPending: {model.OrderStatusPaid, model.OrderStatusCancelled},
+	model.OrderStatusPaid:    {model.OrderStatusPacked, model.OrderStatusCancelled},
+	model.OrderStatusPacked:  {model.OrderStatusShipped, model.OrderStatusCancelled},
+	model.OrderStatusShipped: {model.OrderStatusDelivered},
+}
+
+// cancellationType defines how an order is reversed in each cancellable status,
+// an unpaid order is voided while a paid order is refunded.
+var cancellationType = map[model.OrderStatus]model.TransactionType{
+	model.OrderStatusPending: model.TransactionTypeVoid,
+	model.OrderStatusPaid:    model.TransactionTypeRefund,
+	model.OrderStatusPacked:  model.TransactionTypeRefund,
+}
+
+// canTransition returns true when an order is allowed to move from one status to another.
+func canTransition(from, to model.OrderStatus) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isOrderStatus returns true when status is a known order status.
+func isOrderStatus(status model.OrderStatus) bool {
+	switch status {
+	case model.OrderStatusPending, model.OrderStatusPaid, model.OrderStatusPacked,
+		model.OrderStatusShipped, model.OrderStatusDelivered, model.OrderStatusCancelled:
+		return true
+	}
+	return false
 }
 
 type transactionServiceImpl struct {
 	transactionRepo repository.TransactionRepository
+	productRepo     repository.ProductRepository
+	orderRepo       repository.OrderRepository
+	promotionRepo   repository.PromotionRepository
+	outboxRepo      repository.OutboxRepository
+	txRepo          repository.TxRepository
+	orderHub        *pubsub.Hub
+	currency        string
+	lowStock        int64
+}
+
+// invalidSKUError is returned when an ordered SKU does not exist or has been deleted.
+type invalidSKUError struct {
+	sku string
+}
+
+func (e *invalidSKUError) Error() string {
+	return fmt.Sprintf("sku %s is invalid", e.sku)
+}
+
+// unavailableProductError is returned when an ordered product is not active.
+type unavailableProductError struct {
+	sku    string
+	status model.ProductStatus
+}
+
+func (e *unavailableProductError) Error() string {
+	return fmt.Sprintf("sku %s is %s and can not be ordered", e.sku, e.status)
+}
+
+// invalidTransitionError is returned when an order is not allowed to move to the requested status.
+type invalidTransitionError struct {
+	from model.OrderStatus
+	to   model.OrderStatus
+}
+
+func (e *invalidTransitionError) Error() string {
+	return fmt.Sprintf("order status can not change from %s to %s", e.from, e.to)
+}
+
+// invalidCancelItemError is returned when a cancelled line is not in the order or exceeds its remaining quantity.
+type invalidCancelItemError struct {
+	sku string
+}
+
+func (e *invalidCancelItemError) Error() string {
+	return fmt.Sprintf("sku %s can not be cancelled", e.sku)
+}
+
+// invalidCouponError is returned when a requested coupon can not be applied to the order.
+type invalidCouponError struct {
+	code   string
+	reason string
+}
+
+func (e *invalidCouponError) Error() string {
+	return fmt.Sprintf("coupon %s %s", e.code, e.reason)
+}
+
+// amountOverflowError is returned when the amount of an ordered SKU is too large.
+type amountOverflowError struct {
+	sku string
+}
+
+func (e *amountOverflowError) Error() string {
+	return fmt.Sprintf("amount of sku %s is too large", e.sku)
+}
+
+// exhaustedPromotionError is returned when an automatic promotion reaches its
+// usage limit while the order is placed.
+type exhaustedPromotionError struct {
+	name string
+}
+
+func (e *exhaustedPromotionError) Error() string {
+	return fmt.Sprintf("promotion %s has reached its usage limit", e.name)
+}
+
+// insufficientStockError is returned when ordered SKUs have not enough stock.
+type insufficientStockError struct {
+	items []model.InsufficientStockItem
+}
+
+func (e *insufficientStockError) Error() string {
+	skus := make([]string, len(e.items))
+	for index, item := range e.items {
+		skus[index] = item.SKU
+	}
+	return fmt.Sprintf("insufficient stock for sku %s", strings.Join(skus, ", "))
+}
+
+// priceChangedError is returned when an ordered SKU is not at the price the customer expects.
+type priceChangedError struct {
+	items []model.PriceChangedItem
+}
+
+func (e *priceChangedError) Error() string {
+	skus := make([]string, len(e.items))
+	for index, item := range e.items {
+		skus[index] = item.SKU
+	}
+	return fmt.Sprintf("price has changed for sku %s", strings.Join(skus, ", "))
 }
 
 // NewTransactionService returns new instance of transactionServiceImpl.
@@ -34,11 +188,77 @@ func (s *transactionServiceImpl) SetTransactionRepo(repo repository.TransactionR
 	return s
 }
 
+// SetProductRepo injects product's repo for transactionServiceImpl.
+func (s *transactionServiceImpl) SetProductRepo(repo repository.ProductRepository) *transactionServiceImpl {
+	s.productRepo = repo
+	return s
+}
+
+// SetOrderRepo injects order's repo for transactionServiceImpl.
+func (s *transactionServiceImpl) SetOrderRepo(repo repository.OrderRepository) *transactionServiceImpl {
+	s.orderRepo = repo
+	return s
+}
+
+// SetPromotionRepo injects promotion's repo for transactionServiceImpl.
+func (s *transactionServiceImpl) SetPromotionRepo(repo repository.PromotionRepository) *transactionServiceImpl {
+	s.promotionRepo = repo
+	return s
+}
+
+// SetOutboxRepo injects outbox's repo for transactionServiceImpl.
+func (s *transactionServiceImpl) SetOutboxRepo(repo repository.OutboxRepository) *transactionServiceImpl {
+	s.outboxRepo = repo
+	return s
+}
+
+// SetTxRepo injects tx's repo for transactionServiceImpl.
+func (s *transactionServiceImpl) SetTxRepo(repo repository.TxRepository) *transactionServiceImpl {
+	s.txRepo = repo
+	return s
+}
+
+// SetCurrency sets the currency used to round amounts for transactionServiceImpl.
+func (s *transactionServiceImpl) SetCurrency(currency string) *transactionServiceImpl {
+	s.currency = currency
+	return s
+}
+
+// SetLowStockThreshold sets the stock at or below which product.low_stock is sent.
+func (s *transactionServiceImpl) SetLowStockThreshold(threshold int64) *transactionServiceImpl {
+	s.lowStock = threshold
+	return s
+}
+
+// SetOrderHub sets the hub order changes are published to once committed.
+func (s *transactionServiceImpl) SetOrderHub(hub *pubsub.Hub) *transactionServiceImpl {
+	s.orderHub = hub
+	return s
+}
+
 // Validate validates if all dependency for transactionServiceImpl is complete.
 func (s *transactionServiceImpl) Validate() *transactionServiceImpl {
 	if s.transactionRepo == nil {
 		log.Panic("Transaction service need transaction repository")
 	}
+	if s.productRepo == nil {
+		log.Panic("Transaction service need product repository")
+	}
+	if s.orderRepo == nil {
+		log.Panic("Transaction service need order repository")
+	}
+	if s.promotionRepo == nil {
+		log.Panic("Transaction service need promotion repository")
+	}
+	if s.outboxRepo == nil {
+		log.Panic("Transaction service need outbox repository")
+	}
+	if s.orderHub == nil {
+		log.Panic("Transaction service need order hub")
+	}
+	if s.txRepo == nil {
+		log.Panic("Transaction service need tx repository")
+	}
 	return s
 }
 
@@ -49,38 +269,331 @@ func (s *transactionServiceImpl) Create(ctx context.Context, request model.Creat
 		return utils.RequestRequired("items")
 	}
 
+	for _, item := range request.Items {
+		if strings.TrimSpace(item.SKU) == "" {
+			return utils.RequestRequired("sku")
+		} else if item.Quantity <= 0 {
+			return utils.RequestInvalid("quantity")
+		}
+	}
+
 	log := logger.GetLoggerContext(ctx, "service", "Create")
 
+	// quantity requested per SKU, an SKU may appear in several lines
+	skus := make([]string, 0)
+	requested := make(map[string]int64)
+	for _, item := range request.Items {
+		if _, ok := requested[item.SKU]; !ok {
+			skus = append(skus, item.SKU)
+		}
+		requested[item.SKU] += item.Quantity
+	}
+
+	for sku, price := range request.ExpectedPrices {
+		if _, ok := requested[sku]; !ok || price.IsNegative() {
+			return utils.RequestInvalid("expected_prices")
+		}
+	}
+
 	orderID := utils.GenerateOrderID()
 
-	var totalPrice float64
+	// coupon codes are case insensitive and counted once
+	couponCodes := make([]string, 0)
+	for _, code := range request.CouponCodes {
+		code = normalizeCouponCode(code)
+		if code != "" && !containsString(couponCodes, code) {
+			couponCodes = append(couponCodes, code)
+		}
+	}
+
+	var subtotalPrice, discountAmount, totalPrice model.Money
+	var discounts []model.AppliedDiscount
+	var event model.OrderEvent
 
 	order := make([]model.Transaction, 0)
-	for _, item := range request.Items {
-		order = append(order, model.Transaction{
-			OrderID:  orderID,
-			SKU:      item.SKU,
-			Quantity: item.Quantity,
-			Subtotal: item.Subtotal,
+	prices := make([]model.TransactionItemPrice, 0)
+
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		products, err := s.productRepo.GetBySKUsForUpdate(tx, skus)
+		if err != nil {
+			return err
+		}
+
+		productBySKU := make(map[string]*model.Product)
+		for _, product := range products {
+			productBySKU[product.SKU] = product
+		}
+
+		changed := make([]model.PriceChangedItem, 0)
+		insufficient := make([]model.InsufficientStockItem, 0)
+		for _, sku := range skus {
+			product, ok := productBySKU[sku]
+			if !ok || product.DeletedAt.Valid {
+				return &invalidSKUError{sku: sku}
+			}
+
+			if product.Status != model.ProductStatusActive {
+				return &unavailableProductError{sku: sku, status: product.Status}
+			}
+
+			// checked under the lock so the price can not change before the order is placed
+			if expected, ok := request.ExpectedPrices[sku]; ok && expected.Cmp(product.Price) != 0 {
+				changed = append(changed, model.PriceChangedItem{
+					SKU:           sku,
+					ExpectedPrice: expected,
+					Price:         product.Price,
+				})
+			}
+
+			if product.Stock < requested[sku] {
+				insufficient = append(insufficient, model.InsufficientStockItem{
+					SKU:       sku,
+					Requested: requested[sku],
+					Available: product.Stock,
+				})
+			}
+		}
+
+		if len(changed) > 0 {
+			return &priceChangedError{items: changed}
+		}
+
+		if len(insufficient) > 0 {
+			return &insufficientStockError{items: insufficient}
+		}
+
+		// price is always taken from the product, never from the request
+		lines := make([]*promotionLine, len(request.Items))
+		for index, item := range request.Items {
+			product := productBySKU[item.SKU]
+			amount, err := product.Price.Mul(item.Quantity)
+			if err != nil {
+				return &amountOverflowError{sku: item.SKU}
+			}
+
+			lines[index] = &promotionLine{
+				sku:      item.SKU,
+				brandID:  product.BrandID,
+				quantity: item.Quantity,
+				price:    product.Price,
+				amount:   amount,
+			}
+			subtotalPrice = subtotalPrice.Add(lines[index].amount)
+		}
+
+		promotions, err := s.eligiblePromotions(tx, couponCodes, request.CustomerID)
+		if err != nil {
+			return err
+		}
+
+		discounts = applyPromotions(lines, promotions, s.currency)
+		for _, discount := range discounts {
+			discountAmount = discountAmount.Add(discount.Amount)
+
+			err = s.promotionRepo.RecordUsageTx(tx, &model.PromotionUsage{
+				PromotionID:    discount.PromotionID,
+				OrderID:        orderID,
+				CustomerID:     sql.NullInt64{Int64: request.CustomerID, Valid: request.CustomerID != 0},
+				DiscountAmount: discount.Amount,
+			})
+			if errors.Is(err, repository.ErrPromotionUsageLimit) && discount.Code != "" {
+				return &invalidCouponError{code: discount.Code, reason: "has reached its usage limit"}
+			} else if errors.Is(err, repository.ErrPromotionUsageLimit) {
+				return &exhaustedPromotionError{name: discount.Name}
+			} else if err != nil {
+				return err
+			}
+		}
+
+		for _, line := range lines {
+			order = append(order, model.Transaction{
+				OrderID:        orderID,
+				SKU:            line.sku,
+				Quantity:       line.quantity,
+				Subtotal:       line.amount,
+				Type:           model.TransactionTypeSale,
+				DiscountAmount: line.discount,
+			})
+			prices = append(prices, model.TransactionItemPrice{
+				SKU:      line.sku,
+				Quantity: line.quantity,
+				Price:    line.price,
+				Discount: line.discount,
+				Subtotal: line.amount,
+			})
+
+			totalPrice = totalPrice.Add(line.amount)
+		}
+
+		for _, sku := range skus {
+			product := productBySKU[sku]
+			err = s.productRepo.UpdateStockTx(tx, product.ID, -requested[sku])
+			if err != nil {
+				return err
+			}
+
+			// notify once, when the stock crosses the threshold
+			stock := product.Stock - requested[sku]
+			if product.Stock > s.lowStock && stock <= s.lowStock {
+				err = writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventProductLowStock, product.SKU, model.LowStockEvent{
+					ProductID: product.ID,
+					SKU:       product.SKU,
+					Stock:     stock,
+					Threshold: s.lowStock,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		err = s.orderRepo.CreateTx(tx, &model.Order{
+			OrderID:        orderID,
+			CustomerID:     sql.NullInt64{Int64: request.CustomerID, Valid: request.CustomerID != 0},
+			Status:         model.OrderStatusPending,
+			TotalAmount:    totalPrice,
+			DiscountAmount: discountAmount,
 		})
+		if err != nil {
+			return err
+		}
 
-		totalPrice += item.Subtotal
-	}
+		err = s.transactionRepo.InsertListTx(tx, order)
+		if err != nil {
+			return err
+		}
 
-	err := s.transactionRepo.InsertList(order)
-	if err != nil {
+		event = model.OrderEvent{
+			OrderID:     orderID,
+			CustomerID:  request.CustomerID,
+			Status:      model.OrderStatusPending,
+			TotalAmount: totalPrice,
+			Items:       prices,
+		}
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventOrderCreated, orderID, event)
+	})
+
+	var skuErr *invalidSKUError
+	var unavailableErr *unavailableProductError
+	var stockErr *insufficientStockError
+	var couponErr *invalidCouponError
+	var promotionErr *exhaustedPromotionError
+	var overflowErr *amountOverflowError
+	var priceErr *priceChangedError
+	if errors.As(err, &skuErr) {
+		return utils.RequestInvalid(fmt.Sprintf("sku %s", skuErr.sku))
+	} else if errors.As(err, &overflowErr) {
+		return utils.RequestInvalid(fmt.Sprintf("quantity of sku %s", overflowErr.sku))
+	} else if errors.As(err, &unavailableErr) {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: unavailableErr.Error()}
+	} else if errors.As(err, &couponErr) {
+		return http.StatusBadRequest, &model.BaseResponse{RawMessage: couponErr.Error()}
+	} else if errors.As(err, &promotionErr) {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: promotionErr.Error()}
+	} else if errors.As(err, &priceErr) {
+		return http.StatusConflict, &model.BaseResponse{
+			RawMessage: priceErr.Error(),
+			ResultData: model.PriceChangedResponse{Items: priceErr.items},
+		}
+	} else if errors.As(err, &stockErr) {
+		return http.StatusConflict, &model.BaseResponse{
+			RawMessage: stockErr.Error(),
+			ResultData: model.InsufficientStockResponse{Items: stockErr.items},
+		}
+	} else if err != nil {
 		log.Error(fmt.Sprintf("failed to create transaction, err : %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
 	}
 
+	s.publishOrderEvent(ctx, model.OutboxEventOrderCreated, event)
+
 	resp := model.CreateTransactionResponse{
-		OrderID:    orderID,
-		TotalPrice: totalPrice,
+		OrderID:        orderID,
+		Items:          prices,
+		SubtotalPrice:  subtotalPrice,
+		Discounts:      discounts,
+		DiscountAmount: discountAmount,
+		TotalPrice:     totalPrice,
 	}
 
 	return http.StatusOK, &model.BaseResponse{ResultData: resp}
 }
 
+// publishOrderEvent publishes a committed order change to the subscribers of the order.
+func (s *transactionServiceImpl) publishOrderEvent(ctx context.Context, eventType model.OutboxEventType, event model.OrderEvent) {
+	err := s.orderHub.Publish(event.OrderID, string(eventType), event)
+	if err != nil {
+		log := logger.GetLoggerContext(ctx, "service", "publishOrderEvent")
+		log.Warn(fmt.Sprintf("failed to publish order event, err : %s", err.Error()))
+	}
+}
+
+// eligiblePromotions returns the automatic promotions and requested coupons that
+// can be applied to the order of a customer. Coupons that can not be applied are
+// rejected, while automatic promotions over their limits are skipped. Only the
+// promotions with a limit per customer are locked, while they are counted.
+func (s *transactionServiceImpl) eligiblePromotions(tx *sqlx.Tx, couponCodes []string, customerID int64) ([]*model.Promotion, error) {
+	promotions, err := s.promotionRepo.GetActiveTx(tx, couponCodes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, promotion := range promotions {
+		if promotion.Code.Valid {
+			found[normalizeCouponCode(promotion.Code.String)] = true
+		}
+	}
+
+	for _, code := range couponCodes {
+		if !found[code] {
+			return nil, &invalidCouponError{code: code, reason: "is invalid or expired"}
+		}
+	}
+
+	eligible := make([]*model.Promotion, 0)
+	for _, promotion := range promotions {
+		reason := ""
+		if promotion.UsageLimit.Valid && promotion.UsageCount >= promotion.UsageLimit.Int64 {
+			reason = "has reached its usage limit"
+		} else if promotion.PerCustomerLimit.Valid && customerID == 0 {
+			reason = "requires customer_id"
+		} else if promotion.PerCustomerLimit.Valid {
+			err := s.promotionRepo.LockTx(tx, promotion.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			count, err := s.promotionRepo.CountCustomerUsageTx(tx, promotion.ID, customerID)
+			if err != nil {
+				return nil, err
+			}
+
+			if count >= promotion.PerCustomerLimit.Int64 {
+				reason = "has reached its usage limit for the customer"
+			}
+		}
+
+		if reason == "" {
+			eligible = append(eligible, promotion)
+		} else if promotion.Code.Valid {
+			return nil, &invalidCouponError{code: promotion.Code.String, reason: reason}
+		}
+	}
+
+	return eligible, nil
+}
+
+// containsString returns true when value is in list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // GetDetail returns the detail of a transaction by the order ID from the database,
 // and the total price amount of the transaction.
 func (s *transactionServiceImpl) GetDetail(ctx context.Context, orderID string) (int, *model.BaseResponse) {
@@ -91,33 +604,538 @@ func (s *transactionServiceImpl) GetDetail(ctx context.Context, orderID string)
 
 	log := logger.GetLoggerContext(ctx, "service", "GetDetail")
 
-	transaction, err := s.transactionRepo.GetDetail(orderID)
+	order, err := s.orderRepo.GetByOrderID(orderID)
 	if err != nil {
-		log.Error(fmt.Sprintf("failed to get transaction detail, err : %s", err.Error()))
+		log.Error(fmt.Sprintf("failed to get order, err : %s", err.Error()))
 		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
 	}
 
-	if transaction == nil {
+	if order == nil {
 		return http.StatusNotFound, &model.BaseResponse{ResultData: nil}
 	}
 
-	var totalAmount float64
+	transaction, err := s.transactionRepo.GetDetail(orderID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get transaction detail, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	var totalAmount model.Money
 	items := make([]model.TransactionItem, 0)
 
 	for _, item := range transaction {
-		totalAmount += item.Subtotal
+		totalAmount = totalAmount.Add(item.Subtotal)
 		items = append(items, model.TransactionItem{
 			SKU:      item.SKU,
 			Quantity: item.Quantity,
 			Subtotal: item.Subtotal,
+			Type:     item.Type,
 		})
 	}
 
 	resp := model.GetTranscationDetailResponse{
 		OrderID:     orderID,
+		Status:      order.Status,
 		Items:       items,
 		TotalAmount: totalAmount,
+		CreatedAt:   order.CreatedAt,
+		PaidAt:      utils.TimePtr(order.PaidAt),
+		PackedAt:    utils.TimePtr(order.PackedAt),
+		ShippedAt:   utils.TimePtr(order.ShippedAt),
+		DeliveredAt: utils.TimePtr(order.DeliveredAt),
+		CancelledAt: utils.TimePtr(order.CancelledAt),
+		UpdatedAt:   utils.TimePtr(order.UpdatedAt),
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// UpdateStatus moves an order to a new status, only transitions defined in
+// orderTransitions are allowed.
+func (s *transactionServiceImpl) UpdateStatus(ctx context.Context, request model.UpdateOrderStatusRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.OrderID) == "" {
+		return utils.RequestRequired("order_id")
+	} else if strings.TrimSpace(string(request.Status)) == "" {
+		return utils.RequestRequired("status")
+	} else if !isOrderStatus(request.Status) {
+		return utils.RequestInvalid("status")
+	}
+
+	// cancellation has to reverse the items and restore the stock
+	if request.Status == model.OrderStatusCancelled {
+		return s.Cancel(ctx, model.CancelOrderRequest{OrderID: request.OrderID})
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "UpdateStatus")
+
+	var order *model.Order
+	var event model.OrderEvent
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		order, err = s.orderRepo.GetByOrderIDForUpdate(tx, request.OrderID)
+		if err != nil || order == nil {
+			return err
+		}
+
+		if !canTransition(order.Status, request.Status) {
+			return &invalidTransitionError{from: order.Status, to: request.Status}
+		}
+
+		err = s.orderRepo.UpdateStatusTx(tx, request.OrderID, request.Status)
+		if err != nil {
+			return err
+		}
+
+		event = model.OrderEvent{
+			OrderID:     request.OrderID,
+			CustomerID:  order.CustomerID.Int64,
+			Status:      request.Status,
+			TotalAmount: order.TotalAmount,
+		}
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventOrderStatusChanged, request.OrderID, event)
+	})
+
+	var transitionErr *invalidTransitionError
+	if errors.As(err, &transitionErr) {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: transitionErr.Error()}
+	} else if err != nil {
+		log.Error(fmt.Sprintf("failed to update order status, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if order == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	s.publishOrderEvent(ctx, model.OutboxEventOrderStatusChanged, event)
+
+	resp := model.UpdateOrderStatusResponse{
+		OrderID: request.OrderID,
+		Status:  request.Status,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Cancel cancels a whole order or some of its lines. A pending order is voided
+// and a paid order is refunded, the reversal is recorded as transactions with
+// negative quantity and the cancelled quantity is returned to the product's stock.
+func (s *transactionServiceImpl) Cancel(ctx context.Context, request model.CancelOrderRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.OrderID) == "" {
+		return utils.RequestRequired("order_id")
+	}
+
+	for _, item := range request.Items {
+		if strings.TrimSpace(item.SKU) == "" {
+			return utils.RequestRequired("sku")
+		} else if item.Quantity <= 0 {
+			return utils.RequestInvalid("quantity")
+		}
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Cancel")
+
+	var order *model.Order
+	var resp model.CancelOrderResponse
+	var event model.OrderEvent
+
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		order, err = s.orderRepo.GetByOrderIDForUpdate(tx, request.OrderID)
+		if err != nil || order == nil {
+			return err
+		}
+
+		reversalType, ok := cancellationType[order.Status]
+		if !ok {
+			return &invalidTransitionError{from: order.Status, to: model.OrderStatusCancelled}
+		}
+
+		transaction, err := s.transactionRepo.GetDetailTx(tx, request.OrderID)
+		if err != nil {
+			return err
+		}
+
+		// remaining quantity and amount, and the sold quantity and amount of every SKU in the order
+		skus := make([]string, 0)
+		remaining := make(map[string]int64)
+		remainingAmount := make(map[string]model.Money)
+		sold := make(map[string]int64)
+		soldAmount := make(map[string]model.Money)
+		for _, item := range transaction {
+			if _, ok := remaining[item.SKU]; !ok {
+				skus = append(skus, item.SKU)
+			}
+			remaining[item.SKU] += item.Quantity
+			remainingAmount[item.SKU] = remainingAmount[item.SKU].Add(item.Subtotal)
+			if item.Type == model.TransactionTypeSale {
+				sold[item.SKU] += item.Quantity
+				soldAmount[item.SKU] = soldAmount[item.SKU].Add(item.Subtotal)
+			}
+		}
+
+		cancelled := make(map[string]int64)
+		cancelledSKUs := make([]string, 0)
+		if len(request.Items) == 0 {
+			for _, sku := range skus {
+				if remaining[sku] > 0 {
+					cancelled[sku] = remaining[sku]
+					cancelledSKUs = append(cancelledSKUs, sku)
+				}
+			}
+		} else {
+			for _, item := range request.Items {
+				if _, ok := cancelled[item.SKU]; !ok {
+					cancelledSKUs = append(cancelledSKUs, item.SKU)
+				}
+				cancelled[item.SKU] += item.Quantity
+				if cancelled[item.SKU] > remaining[item.SKU] {
+					return &invalidCancelItemError{sku: item.SKU}
+				}
+			}
+		}
+
+		// deleted products get their stock back too, so restoring them later
+		// does not leave them short of the cancelled units
+		products, err := s.productRepo.GetBySKUsForUpdate(tx, cancelledSKUs, repository.IncludeDeleted)
+		if err != nil {
+			return err
+		}
+
+		productBySKU := make(map[string]*model.Product)
+		for _, product := range products {
+			productBySKU[product.SKU] = product
+		}
+
+		reversal := make([]model.Transaction, 0)
+		resp.Items = make([]model.TransactionItemPrice, 0)
+		for _, sku := range cancelledSKUs {
+			quantity := cancelled[sku]
+
+			// the last units take whatever amount is left so rounding never leaves a remainder
+			subtotal := remainingAmount[sku]
+			if quantity < remaining[sku] {
+				share, err := soldAmount[sku].MulDiv(quantity, sold[sku])
+				if err != nil {
+					return err
+				}
+				subtotal = share.Round(s.currency)
+			}
+
+			price, err := soldAmount[sku].MulDiv(1, sold[sku])
+			if err != nil {
+				return err
+			}
+
+			reversal = append(reversal, model.Transaction{
+				OrderID:  request.OrderID,
+				SKU:      sku,
+				Quantity: -quantity,
+				Subtotal: subtotal.Neg(),
+				Type:     reversalType,
+			})
+			resp.Items = append(resp.Items, model.TransactionItemPrice{
+				SKU:      sku,
+				Quantity: quantity,
+				Price:    price,
+				Subtotal: subtotal,
+			})
+			resp.CancelledAmount = resp.CancelledAmount.Add(subtotal)
+			remaining[sku] -= quantity
+
+			if product, ok := productBySKU[sku]; ok {
+				err = s.productRepo.UpdateStockTx(tx, product.ID, quantity)
+				if err != nil {
+					return err
+				}
+
+				// customers are only told about products still for sale
+				if !product.DeletedAt.Valid {
+					err = writeBackInStockEvent(tx, s.outboxRepo, product, product.Stock+quantity)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if len(reversal) > 0 {
+			err = s.transactionRepo.InsertListTx(tx, reversal)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp.TotalAmount = order.TotalAmount.Sub(resp.CancelledAmount)
+		err = s.orderRepo.UpdateTotalAmountTx(tx, request.OrderID, resp.TotalAmount)
+		if err != nil {
+			return err
+		}
+
+		resp.Status = model.OrderStatusCancelled
+		for _, sku := range skus {
+			if remaining[sku] > 0 {
+				resp.Status = order.Status
+			}
+		}
+
+		if resp.Status == model.OrderStatusCancelled {
+			err = s.orderRepo.UpdateStatusTx(tx, request.OrderID, model.OrderStatusCancelled)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a partial cancellation keeps the status of the order in the event
+		event = model.OrderEvent{
+			OrderID:     request.OrderID,
+			CustomerID:  order.CustomerID.Int64,
+			Status:      resp.Status,
+			TotalAmount: resp.TotalAmount,
+			Items:       resp.Items,
+		}
+		return writeOutboxEvent(tx, s.outboxRepo, model.OutboxEventOrderCancelled, request.OrderID, event)
+	})
+
+	var transitionErr *invalidTransitionError
+	var itemErr *invalidCancelItemError
+	if errors.As(err, &transitionErr) {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: transitionErr.Error()}
+	} else if errors.As(err, &itemErr) {
+		return http.StatusBadRequest, &model.BaseResponse{RawMessage: itemErr.Error()}
+	} else if err != nil {
+		log.Error(fmt.Sprintf("failed to cancel order, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if order == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	s.publishOrderEvent(ctx, model.OutboxEventOrderCancelled, event)
+
+	resp.OrderID = request.OrderID
+	resp.Type = cancellationType[order.Status]
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Delete soft-deletes an order along with its transactions. Only finished orders,
+// cancelled or delivered, can be deleted.
+func (s *transactionServiceImpl) Delete(ctx context.Context, orderID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(orderID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Delete")
+
+	var order *model.Order
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		order, err = s.orderRepo.GetByOrderIDForUpdate(tx, orderID)
+		if err != nil || order == nil {
+			return err
+		}
+
+		if order.Status != model.OrderStatusCancelled && order.Status != model.OrderStatusDelivered {
+			return nil
+		}
+
+		// transactions deleted with the order share its deletion time
+		at := time.Now().UTC().Truncate(time.Second)
+		err = s.orderRepo.DeleteTx(tx, orderID, at)
+		if err != nil {
+			return err
+		}
+		order.DeletedAt = sql.NullTime{Time: at, Valid: true}
+
+		return s.transactionRepo.DeleteByOrderIDTx(tx, orderID, at)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete order, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if order == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	if !order.DeletedAt.Valid {
+		return http.StatusConflict, &model.BaseResponse{RawMessage: fmt.Sprintf("cannot delete a %s order", order.Status)}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: deleteOrderResponse(order)}
+}
+
+// Restore brings back a soft-deleted order along with the transactions deleted
+// with it. Restoring an order that is not deleted does nothing.
+func (s *transactionServiceImpl) Restore(ctx context.Context, orderID string) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(orderID) == "" {
+		return utils.RequestRequired("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Restore")
+
+	var order *model.Order
+	err := s.txRepo.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		order, err = s.orderRepo.GetByOrderIDForUpdate(tx, orderID, repository.IncludeDeleted)
+		if err != nil || order == nil || !order.DeletedAt.Valid {
+			return err
+		}
+
+		err = s.orderRepo.RestoreTx(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		err = s.transactionRepo.RestoreByOrderIDTx(tx, orderID, order.DeletedAt.Time)
+		if err != nil {
+			return err
+		}
+		order.DeletedAt = sql.NullTime{}
+
+		return nil
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to restore order, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if order == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: deleteOrderResponse(order)}
+}
+
+// deleteOrderResponse returns the response of deleting or restoring an order.
+func deleteOrderResponse(order *model.Order) model.DeleteOrderResponse {
+	return model.DeleteOrderResponse{
+		OrderID:   order.OrderID,
+		Status:    order.Status,
+		DeletedAt: utils.TimePtr(order.DeletedAt),
+	}
+}
+
+// List returns orders matching the filters of the request, a page at a time.
+// The next page is requested with the next_cursor of the previous page.
+func (s *transactionServiceImpl) List(ctx context.Context, request model.ListOrderRequest) (int, *model.BaseResponse) {
+	filter := model.OrderFilter{
+		SKU:      strings.TrimSpace(request.SKU),
+		Status:   model.OrderStatus(strings.TrimSpace(request.Status)),
+		SortBy:   "created_at",
+		SortDesc: true,
+		Limit:    defaultOrderListLimit,
+	}
+
+	// validate request
+	if request.CreatedFrom != "" {
+		createdFrom, _, err := parseDateTime(request.CreatedFrom)
+		if err != nil {
+			return utils.RequestInvalid("created_from")
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+	if request.CreatedTo != "" {
+		createdTo, dateOnly, err := parseDateTime(request.CreatedTo)
+		if err != nil {
+			return utils.RequestInvalid("created_to")
+		}
+		// a date includes the whole day
+		if dateOnly {
+			createdTo = createdTo.Add(24*time.Hour - time.Second)
+		}
+		filter.CreatedTo = &createdTo
+	}
+	if request.MinTotal != "" {
+		minTotal, err := model.ParseMoney(request.MinTotal)
+		if err != nil {
+			return utils.RequestInvalid("min_total")
+		}
+		filter.MinTotal = &minTotal
+	}
+	if request.MaxTotal != "" {
+		maxTotal, err := model.ParseMoney(request.MaxTotal)
+		if err != nil {
+			return utils.RequestInvalid("max_total")
+		}
+		filter.MaxTotal = &maxTotal
+	}
+	if filter.Status != "" && !isOrderStatus(filter.Status) {
+		return utils.RequestInvalid("status")
+	}
+	if request.Sort != "" {
+		filter.SortBy = strings.TrimPrefix(request.Sort, "-")
+		filter.SortDesc = strings.HasPrefix(request.Sort, "-")
+		if filter.SortBy != "created_at" && filter.SortBy != "total_amount" {
+			return utils.RequestInvalid("sort")
+		}
+	}
+	if request.Limit != "" {
+		limit, err := strconv.Atoi(request.Limit)
+		if err != nil || limit <= 0 || limit > maxOrderListLimit {
+			return utils.RequestInvalid("limit")
+		}
+		filter.Limit = limit
+	}
+	if request.Cursor != "" {
+		value, id, err := utils.DecodeCursor(request.Cursor)
+		if err != nil {
+			return utils.RequestInvalid("cursor")
+		}
+
+		if filter.SortBy == "created_at" {
+			filter.AfterValue, err = time.Parse(time.RFC3339Nano, value)
+		} else {
+			filter.AfterValue, err = model.ParseMoney(value)
+		}
+		if err != nil {
+			return utils.RequestInvalid("cursor")
+		}
+		filter.AfterID = id
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	// one more row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	orders, err := s.orderRepo.List(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list orders, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListOrderResponse{
+		Orders: orders,
+	}
+
+	if len(orders) > limit {
+		resp.Orders = orders[:limit]
+
+		last := resp.Orders[limit-1]
+		if filter.SortBy == "created_at" {
+			resp.NextCursor = utils.EncodeCursor(last.CreatedAt.Format(time.RFC3339Nano), last.ID)
+		} else {
+			resp.NextCursor = utils.EncodeCursor(last.TotalAmount.String(), last.ID)
+		}
 	}
 
 	return http.StatusOK, &model.BaseResponse{ResultData: resp}
 }
+
+// parseDateTime parses either an RFC3339 time or a date, and tells which one it was.
+func parseDateTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
@@ -2,15 +2,20 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
 	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/pubsub"
 	"github.com/richardsahvic/jamtangan/service"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateTransaction(t *testing.T) {
@@ -28,34 +33,340 @@ func TestCreateTransaction(t *testing.T) {
 		assert.Nil(t, resp.ResultData)
 	}(t)
 
+	// TestCreateTransactionInvalidSKU
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: unknown SKU
+		req := model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-unknown",
+					Quantity: 1,
+				},
+			},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-unknown"}).Return([]*model.Product{}, nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+
+		// Case: soft-deleted SKU
+		req = model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-deleted",
+					Quantity: 1,
+				},
+			},
+		}
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-deleted"}).Return([]*model.Product{
+			{
+				SKU:       "sku-deleted",
+				Stock:     10,
+				Price:     model.NewMoney(10000),
+				DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+		}, nil)
+		httpCode, resp = transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+
+		// Case: only active products can be ordered
+		req = model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-draft",
+					Quantity: 1,
+				},
+			},
+		}
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-draft"}).Return([]*model.Product{
+			{
+				SKU:    "sku-draft",
+				Stock:  10,
+				Price:  model.NewMoney(10000),
+				Status: model.ProductStatusDraft,
+			},
+		}, nil)
+		httpCode, resp = transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Equal(t, resp.RawMessage, "sku sku-draft is draft and can not be ordered")
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+
+		// Case: subtotal too large for an amount
+		req = model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-bulk",
+					Quantity: 10000000,
+				},
+			},
+		}
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-bulk"}).Return([]*model.Product{
+			{
+				SKU:    "sku-bulk",
+				Stock:  10000000,
+				Price:  model.NewMoney(1000000000000),
+				Status: model.ProductStatusActive,
+			},
+		}, nil)
+		httpCode, resp = transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "quantity of sku sku-bulk is invalid")
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+
+		// Case: invalid quantity
+		req = model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-test",
+					Quantity: 0,
+				},
+			},
+		}
+		httpCode, resp = transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestCreateTransactionInsufficientStock
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: quantity of the same SKU in several lines exceeds the stock
+		req := model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-test",
+					Quantity: 2,
+				},
+				{
+					SKU:      "sku-test",
+					Quantity: 2,
+				},
+			},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+
+		result := resp.ResultData.(model.InsufficientStockResponse)
+		assert.Equal(t, []model.InsufficientStockItem{{SKU: "sku-test", Requested: 4, Available: 3}}, result.Items)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 0)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+	}(t)
+
+	// TestCreateTransactionPriceChanged
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: expected price of an SKU that is not ordered
+		req := model.CreateTransactionRequest{
+			Items:          []model.TransactionItem{{SKU: "sku-test", Quantity: 1}},
+			ExpectedPrices: map[string]model.Money{"sku-other": model.NewMoney(10000)},
+		}
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, "expected_prices is invalid", resp.RawMessage)
+
+		// Case: the price read under the lock is not the expected one
+		req.ExpectedPrices = map[string]model.Money{"sku-test": model.NewMoney(10000)}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(12000), Status: model.ProductStatusActive},
+		}, nil)
+		httpCode, resp = transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+
+		result := resp.ResultData.(model.PriceChangedResponse)
+		assert.Equal(t, []model.PriceChangedItem{
+			{SKU: "sku-test", ExpectedPrice: model.NewMoney(10000), Price: model.NewMoney(12000)},
+		}, result.Items)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 0)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+	}(t)
+
 	// TestCreateTransactionErrorDatabase
-	// func(t *testing.T) {
-	// 	mockTransactionRepo := new(repoMock.TransactionRepository)
-	// 	transactionService := service.NewTransactionService().
-	// 		SetTransactionRepo(mockTransactionRepo)
-
-	// 	req := model.CreateTransactionRequest{
-	// 		Items: []model.TransactionItem{
-	// 			{
-	// 				SKU:      "sku-test",
-	// 				Quantity: 1,
-	// 				Subtotal: 10000,
-	// 			},
-	// 		},
-	// 	}
-	// 	mockTransactionRepo.On("InsertList", []model.Transaction{
-	// 		{
-	// 			OrderID:  utils.GenerateOrderID(),
-	// 			SKU:      "sku-test",
-	// 			Quantity: 1,
-	// 			Subtotal: 10000,
-	// 		},
-	// 	}).Return(errors.New("error"))
-	// 	httpCode, resp := transactionService.Create(context.Background(), req)
-	// 	assert.Equal(t, httpCode, http.StatusInternalServerError)
-	// 	assert.Nil(t, resp.ResultData)
-	// 	assert.NotEmpty(t, resp.RawMessage)
-	// }(t)
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		req := model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-test",
+					Quantity: 1,
+				},
+			},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{}, mock.Anything).Return([]*model.Promotion{}, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(1), int64(-1)).Return(nil)
+		mockOrderRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.Anything).Return(errors.New("error"))
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+		assert.Nil(t, resp.ResultData)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestCreateTransactionSuccess
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: client subtotal is ignored
+		req := model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{
+					SKU:      "sku-test",
+					Quantity: 3,
+					Subtotal: model.NewMoney(1),
+				},
+			},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-test"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-test", Stock: 3, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{}, mock.Anything).Return([]*model.Promotion{}, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(1), int64(-3)).Return(nil)
+		mockOrderRepo.On("CreateTx", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
+			return order.Status == model.OrderStatusPending && order.TotalAmount == model.NewMoney(30000)
+		})).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.MatchedBy(func(order []model.Transaction) bool {
+			return len(order) == 1 && order[0].Subtotal == model.NewMoney(30000)
+		})).Return(nil)
+		httpCode, resp := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.RawMessage)
+
+		result := resp.ResultData.(model.CreateTransactionResponse)
+		assert.Equal(t, model.NewMoney(30000), result.TotalPrice)
+		assert.Equal(t, model.NewMoney(10000), result.Items[0].Price)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 1)
+		mockOrderRepo.AssertNumberOfCalls(t, "CreateTx", 1)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 1)
+	}(t)
+
+	// TestCreateTransactionEvents
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockPromotionRepo := new(repoMock.PromotionRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetPromotionRepo(mockPromotionRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetLowStockThreshold(5)
+
+		// Case: only the SKU crossing the threshold is reported as low stock
+		req := model.CreateTransactionRequest{
+			Items: []model.TransactionItem{
+				{SKU: "sku-a", Quantity: 3},
+				{SKU: "sku-b", Quantity: 1},
+			},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-a", "sku-b"}).Return([]*model.Product{
+			{ID: 1, SKU: "sku-a", Stock: 7, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+			{ID: 2, SKU: "sku-b", Stock: 4, Price: model.NewMoney(10000), Status: model.ProductStatusActive},
+		}, nil)
+		mockPromotionRepo.On("GetActiveTx", mock.Anything, []string{}, mock.Anything).Return([]*model.Promotion{}, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.Anything).Return(nil)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		httpCode, _ := transactionService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockOutboxRepo.AssertNumberOfCalls(t, "CreateTx", 2)
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductLowStock && event.AggregateID == "sku-a" &&
+				string(event.Payload) == `{"product_id":1,"sku":"sku-a","stock":4,"threshold":5}`
+		}))
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventOrderCreated
+		}))
+	}(t)
 }
 
 func TestGetTransaction(t *testing.T) {
@@ -74,10 +385,13 @@ func TestGetTransaction(t *testing.T) {
 	// TestGetTransactionErrorDatabase
 	func(t *testing.T) {
 		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
 		transactionService := service.NewTransactionService().
-			SetTransactionRepo(mockTransactionRepo)
+			SetTransactionRepo(mockTransactionRepo).
+			SetOrderRepo(mockOrderRepo)
 
 		req := "orderID"
+		mockOrderRepo.On("GetByOrderID", req).Return(&model.Order{OrderID: req}, nil)
 		mockTransactionRepo.On("GetDetail", req).Return(nil, errors.New("error"))
 		httpCode, resp := transactionService.GetDetail(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusInternalServerError)
@@ -89,30 +403,598 @@ func TestGetTransaction(t *testing.T) {
 	// TestGetTransactionNotFound
 	func(t *testing.T) {
 		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
 		transactionService := service.NewTransactionService().
-			SetTransactionRepo(mockTransactionRepo)
+			SetTransactionRepo(mockTransactionRepo).
+			SetOrderRepo(mockOrderRepo)
 
 		req := "orderID"
-		mockTransactionRepo.On("GetDetail", req).Return(nil, nil)
+		mockOrderRepo.On("GetByOrderID", req).Return(nil, nil)
 		httpCode, resp := transactionService.GetDetail(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusNotFound)
 		assert.Nil(t, resp.ResultData)
 		assert.Empty(t, resp.RawMessage)
-		mockTransactionRepo.AssertNumberOfCalls(t, "GetDetail", 1)
+		mockOrderRepo.AssertNumberOfCalls(t, "GetByOrderID", 1)
+		mockTransactionRepo.AssertNumberOfCalls(t, "GetDetail", 0)
 	}(t)
 
 	// TestGetTransactionSuccess
 	func(t *testing.T) {
 		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
 		transactionService := service.NewTransactionService().
-			SetTransactionRepo(mockTransactionRepo)
+			SetTransactionRepo(mockTransactionRepo).
+			SetOrderRepo(mockOrderRepo)
 
 		req := "orderID"
+		paidAt := time.Now()
+		mockOrderRepo.On("GetByOrderID", req).Return(&model.Order{
+			OrderID: req,
+			Status:  model.OrderStatusPaid,
+			PaidAt:  sql.NullTime{Time: paidAt, Valid: true},
+		}, nil)
 		mockTransactionRepo.On("GetDetail", req).Return([]*model.Transaction{}, nil)
 		httpCode, resp := transactionService.GetDetail(context.Background(), req)
 		assert.Equal(t, httpCode, http.StatusOK)
 		assert.NotNil(t, resp.ResultData)
 		assert.Empty(t, resp.RawMessage)
+
+		result := resp.ResultData.(model.GetTranscationDetailResponse)
+		assert.Equal(t, model.OrderStatusPaid, result.Status)
+		assert.Equal(t, &paidAt, result.PaidAt)
+		assert.Nil(t, result.ShippedAt)
 		mockTransactionRepo.AssertNumberOfCalls(t, "GetDetail", 1)
 	}(t)
 }
+
+func TestUpdateOrderStatus(t *testing.T) {
+	prepare()
+
+	// TestUpdateOrderStatusEmptyRequest
+	func(t *testing.T) {
+		transactionService := service.NewTransactionService()
+
+		// Case: empty order ID
+		req := model.UpdateOrderStatusRequest{
+			Status: model.OrderStatusPaid,
+		}
+		httpCode, resp := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: unknown status
+		req = model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  "lost",
+		}
+		httpCode, resp = transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestUpdateOrderStatusNotFound
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		req := model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  model.OrderStatusPaid,
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(nil, nil)
+		httpCode, resp := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusNotFound)
+		assert.Nil(t, resp.ResultData)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatusTx", 0)
+	}(t)
+
+	// TestUpdateOrderStatusIllegalTransition
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: pending order can not be shipped
+		req := model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  model.OrderStatusShipped,
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusPending,
+		}, nil)
+		httpCode, resp := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatusTx", 0)
+	}(t)
+
+	// TestUpdateOrderStatusSuccess
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		req := model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  model.OrderStatusPaid,
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusPending,
+		}, nil)
+		mockOrderRepo.On("UpdateStatusTx", mock.Anything, req.OrderID, model.OrderStatusPaid).Return(nil)
+		httpCode, resp := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.RawMessage)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatusTx", 1)
+		mockOutboxRepo.AssertCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventOrderStatusChanged && event.AggregateID == req.OrderID
+		}))
+	}(t)
+}
+
+func TestCancelOrder(t *testing.T) {
+	prepare()
+
+	sales := []*model.Transaction{
+		{SKU: "sku-a", Quantity: 2, Subtotal: model.NewMoney(20000), Type: model.TransactionTypeSale},
+		{SKU: "sku-b", Quantity: 1, Subtotal: model.NewMoney(50000), Type: model.TransactionTypeSale},
+	}
+	products := []*model.Product{
+		{ID: 1, SKU: "sku-a", Stock: 5, Price: model.NewMoney(10000)},
+		{ID: 2, SKU: "sku-b", Stock: 0, Price: model.NewMoney(50000)},
+	}
+
+	// TestCancelOrderEmptyRequest
+	func(t *testing.T) {
+		transactionService := service.NewTransactionService()
+
+		req := model.CancelOrderRequest{OrderID: " "}
+		httpCode, resp := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestCancelOrderNotCancellable
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: shipped order
+		req := model.CancelOrderRequest{OrderID: "orderID"}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusShipped,
+		}, nil)
+		httpCode, resp := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestCancelOrderExceedQuantity
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		req := model.CancelOrderRequest{
+			OrderID: "orderID",
+			Items:   []model.CancelOrderItem{{SKU: "sku-a", Quantity: 3}},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusPaid,
+		}, nil)
+		mockTransactionRepo.On("GetDetailTx", mock.Anything, req.OrderID).Return(sales, nil)
+		httpCode, resp := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockTransactionRepo.AssertNumberOfCalls(t, "InsertListTx", 0)
+	}(t)
+
+	// TestCancelOrderWhole
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: pending order is voided
+		req := model.CancelOrderRequest{OrderID: "orderID"}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID:     req.OrderID,
+			Status:      model.OrderStatusPending,
+			TotalAmount: model.NewMoney(70000),
+		}, nil)
+		mockTransactionRepo.On("GetDetailTx", mock.Anything, req.OrderID).Return(sales, nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-a", "sku-b"}, repository.IncludeDeleted).Return(products, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(1), int64(2)).Return(nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(2), int64(1)).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.MatchedBy(func(order []model.Transaction) bool {
+			return len(order) == 2 && order[0].Quantity == -2 && order[0].Subtotal == model.NewMoney(-20000) &&
+				order[0].Type == model.TransactionTypeVoid
+		})).Return(nil)
+		mockOrderRepo.On("UpdateTotalAmountTx", mock.Anything, req.OrderID, model.NewMoney(0)).Return(nil)
+		mockOrderRepo.On("UpdateStatusTx", mock.Anything, req.OrderID, model.OrderStatusCancelled).Return(nil)
+		httpCode, resp := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.RawMessage)
+
+		result := resp.ResultData.(model.CancelOrderResponse)
+		assert.Equal(t, model.OrderStatusCancelled, result.Status)
+		assert.Equal(t, model.TransactionTypeVoid, result.Type)
+		assert.Equal(t, model.NewMoney(70000), result.CancelledAmount)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 2)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatusTx", 1)
+	}(t)
+
+	// TestCancelOrderLine
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: one unit of a paid order is refunded
+		req := model.CancelOrderRequest{
+			OrderID: "orderID",
+			Items:   []model.CancelOrderItem{{SKU: "sku-a", Quantity: 1}},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID:     req.OrderID,
+			Status:      model.OrderStatusPaid,
+			TotalAmount: model.NewMoney(70000),
+		}, nil)
+		mockTransactionRepo.On("GetDetailTx", mock.Anything, req.OrderID).Return(sales, nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-a"}, repository.IncludeDeleted).Return(products[:1], nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(1), int64(1)).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.MatchedBy(func(order []model.Transaction) bool {
+			return len(order) == 1 && order[0].Quantity == -1 && order[0].Type == model.TransactionTypeRefund
+		})).Return(nil)
+		mockOrderRepo.On("UpdateTotalAmountTx", mock.Anything, req.OrderID, model.NewMoney(60000)).Return(nil)
+		httpCode, resp := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.RawMessage)
+
+		result := resp.ResultData.(model.CancelOrderResponse)
+		assert.Equal(t, model.OrderStatusPaid, result.Status)
+		assert.Equal(t, model.TransactionTypeRefund, result.Type)
+		assert.Equal(t, model.NewMoney(60000), result.TotalAmount)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatusTx", 0)
+	}(t)
+
+	// TestCancelOrderDeletedProduct
+	func(t *testing.T) {
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockProductRepo := new(repoMock.ProductRepository)
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		transactionService := service.NewTransactionService().
+			SetTransactionRepo(mockTransactionRepo).
+			SetProductRepo(mockProductRepo).
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo)
+
+		// Case: a product deleted since the order was placed gets its stock back
+		req := model.CancelOrderRequest{
+			OrderID: "orderID",
+			Items:   []model.CancelOrderItem{{SKU: "sku-b", Quantity: 1}},
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID:     req.OrderID,
+			Status:      model.OrderStatusPaid,
+			TotalAmount: model.NewMoney(70000),
+		}, nil)
+		mockTransactionRepo.On("GetDetailTx", mock.Anything, req.OrderID).Return(sales, nil)
+		mockProductRepo.On("GetBySKUsForUpdate", mock.Anything, []string{"sku-b"}, repository.IncludeDeleted).Return([]*model.Product{
+			{ID: 2, SKU: "sku-b", Stock: 0, Price: model.NewMoney(50000), DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+		}, nil)
+		mockProductRepo.On("UpdateStockTx", mock.Anything, int64(2), int64(1)).Return(nil)
+		mockTransactionRepo.On("InsertListTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("UpdateTotalAmountTx", mock.Anything, req.OrderID, model.NewMoney(20000)).Return(nil)
+		httpCode, _ := transactionService.Cancel(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		mockProductRepo.AssertNumberOfCalls(t, "UpdateStockTx", 1)
+		mockOutboxRepo.AssertNotCalled(t, "CreateTx", mock.Anything, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+			return event.EventType == model.OutboxEventProductBackInStock
+		}))
+	}(t)
+}
+
+func TestListOrder(t *testing.T) {
+	prepare()
+
+	// TestListOrderInvalidRequest
+	func(t *testing.T) {
+		transactionService := service.NewTransactionService()
+
+		requests := []model.ListOrderRequest{
+			{CreatedFrom: "yesterday"},
+			{MinTotal: "a"},
+			{Status: "lost"},
+			{Sort: "sku"},
+			{Limit: "1000"},
+			{Cursor: "%%%"},
+		}
+		for _, req := range requests {
+			httpCode, resp := transactionService.List(context.Background(), req)
+			assert.Equal(t, httpCode, http.StatusBadRequest)
+			assert.Nil(t, resp.ResultData)
+		}
+	}(t)
+
+	// TestListOrderErrorDatabase
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		transactionService := service.NewTransactionService().SetOrderRepo(mockOrderRepo)
+
+		mockOrderRepo.On("List", mock.Anything).Return(nil, errors.New("error"))
+		httpCode, resp := transactionService.List(context.Background(), model.ListOrderRequest{})
+		assert.Equal(t, httpCode, http.StatusInternalServerError)
+		assert.NotEmpty(t, resp.RawMessage)
+	}(t)
+
+	// TestListOrderSuccess
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		transactionService := service.NewTransactionService().SetOrderRepo(mockOrderRepo)
+
+		req := model.ListOrderRequest{
+			CreatedTo: "2022-02-01",
+			MinTotal:  "1000",
+			Sort:      "total_amount",
+			Limit:     "2",
+		}
+		mockOrderRepo.On("List", mock.MatchedBy(func(filter model.OrderFilter) bool {
+			return filter.SortBy == "total_amount" && !filter.SortDesc && filter.Limit == 3 &&
+				*filter.MinTotal == model.NewMoney(1000) && filter.CreatedTo.Hour() == 23 && filter.AfterValue == nil
+		})).Return([]*model.OrderSummary{
+			{ID: 1, OrderID: "order-1", TotalAmount: model.NewMoney(1000)},
+			{ID: 2, OrderID: "order-2", TotalAmount: model.NewMoney(2000)},
+			{ID: 3, OrderID: "order-3", TotalAmount: model.NewMoney(3000)},
+		}, nil)
+		httpCode, resp := transactionService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListOrderResponse)
+		assert.Len(t, result.Orders, 2)
+		assert.NotEmpty(t, result.NextCursor)
+
+		// Case: next page continues after the last row
+		req.Cursor = result.NextCursor
+		mockOrderRepo.On("List", mock.MatchedBy(func(filter model.OrderFilter) bool {
+			return filter.AfterValue == model.NewMoney(2000) && filter.AfterID == 2
+		})).Return([]*model.OrderSummary{
+			{ID: 3, OrderID: "order-3", TotalAmount: model.NewMoney(3000)},
+		}, nil)
+		httpCode, resp = transactionService.List(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result = resp.ResultData.(model.ListOrderResponse)
+		assert.Len(t, result.Orders, 1)
+		assert.Empty(t, result.NextCursor)
+	}(t)
+}
+
+func TestPublishOrderEvents(t *testing.T) {
+	prepare()
+
+	// TestPublishOrderEventsStatusChanged
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockOutboxRepo := new(repoMock.OutboxRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		orderHub := pubsub.NewHub(10)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetOutboxRepo(mockOutboxRepo).
+			SetTxRepo(mockTxRepo).
+			SetOrderHub(orderHub)
+
+		orderSubscription := orderHub.Subscribe("orderID", 0)
+		defer orderSubscription.Close()
+		otherSubscription := orderHub.Subscribe("otherID", 0)
+		defer otherSubscription.Close()
+
+		// Case: committed status change is published to the subscribers of the order
+		req := model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  model.OrderStatusShipped,
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOutboxRepo.On("CreateTx", mock.Anything, mock.Anything).Return(nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusPacked,
+		}, nil)
+		mockOrderRepo.On("UpdateStatusTx", mock.Anything, req.OrderID, model.OrderStatusShipped).Return(nil)
+		httpCode, _ := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		event := <-orderSubscription.Events()
+		assert.Equal(t, event.Type, string(model.OutboxEventOrderStatusChanged))
+		assert.Contains(t, string(event.Data), `"status":"shipped"`)
+		assert.Len(t, otherSubscription.Events(), 0)
+
+		// Case: a reconnecting subscriber resumes after the last event it received
+		resumed := orderHub.Subscribe("orderID", event.ID-1)
+		defer resumed.Close()
+		assert.False(t, resumed.Missed)
+		replayed := <-resumed.Events()
+		assert.Equal(t, replayed.ID, event.ID)
+
+		// Case: events of a previous process are reported as missed
+		restarted := orderHub.Subscribe("orderID", 1)
+		defer restarted.Close()
+		assert.True(t, restarted.Missed)
+	}(t)
+
+	// TestPublishOrderEventsFailed
+	func(t *testing.T) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		orderHub := pubsub.NewHub(10)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetTxRepo(mockTxRepo).
+			SetOrderHub(orderHub)
+
+		subscription := orderHub.Subscribe("", 0)
+		defer subscription.Close()
+
+		// Case: rejected transitions are not published
+		req := model.UpdateOrderStatusRequest{
+			OrderID: "orderID",
+			Status:  model.OrderStatusShipped,
+		}
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, req.OrderID).Return(&model.Order{
+			OrderID: req.OrderID,
+			Status:  model.OrderStatusPending,
+		}, nil)
+		httpCode, _ := transactionService.UpdateStatus(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.Len(t, subscription.Events(), 0)
+	}(t)
+}
+
+func TestDeleteOrder(t *testing.T) {
+	prepare()
+
+	newService := func() (*repoMock.OrderRepository, *repoMock.TransactionRepository, service.TransactionService) {
+		mockOrderRepo := new(repoMock.OrderRepository)
+		mockTransactionRepo := new(repoMock.TransactionRepository)
+		mockTxRepo := new(repoMock.TxRepository)
+		mockTxRepo.On("WithTx", mock.Anything).Return(runTx)
+		transactionService := service.NewTransactionService().
+			SetOrderRepo(mockOrderRepo).
+			SetTransactionRepo(mockTransactionRepo).
+			SetTxRepo(mockTxRepo)
+		return mockOrderRepo, mockTransactionRepo, transactionService
+	}
+
+	// TestDeleteOrderEmptyRequest
+	func(t *testing.T) {
+		transactionService := service.NewTransactionService()
+
+		// Case: empty order ID
+		httpCode, _ := transactionService.Delete(context.Background(), "")
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+	}(t)
+
+	// TestDeleteOrderNotFinished
+	func(t *testing.T) {
+		mockOrderRepo, _, transactionService := newService()
+
+		// Case: an order still in progress is not deleted
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, "orderID").Return(&model.Order{
+			OrderID: "orderID",
+			Status:  model.OrderStatusPaid,
+		}, nil)
+		httpCode, resp := transactionService.Delete(context.Background(), "orderID")
+		assert.Equal(t, httpCode, http.StatusConflict)
+		assert.NotEmpty(t, resp.RawMessage)
+		mockOrderRepo.AssertNotCalled(t, "DeleteTx", mock.Anything, mock.Anything, mock.Anything)
+	}(t)
+
+	// TestDeleteOrderSuccess
+	func(t *testing.T) {
+		mockOrderRepo, mockTransactionRepo, transactionService := newService()
+
+		// Case: a delivered order is deleted along with its transactions
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, "orderID").Return(&model.Order{
+			OrderID: "orderID",
+			Status:  model.OrderStatusDelivered,
+		}, nil)
+		mockOrderRepo.On("DeleteTx", mock.Anything, "orderID", mock.Anything).Return(nil)
+		mockTransactionRepo.On("DeleteByOrderIDTx", mock.Anything, "orderID", mock.Anything).Return(nil)
+		httpCode, resp := transactionService.Delete(context.Background(), "orderID")
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.DeleteOrderResponse)
+		assert.NotNil(t, result.DeletedAt)
+		mockTransactionRepo.AssertCalled(t, "DeleteByOrderIDTx", mock.Anything, "orderID", *result.DeletedAt)
+	}(t)
+
+	// TestRestoreOrder
+	func(t *testing.T) {
+		mockOrderRepo, mockTransactionRepo, transactionService := newService()
+		deletedAt := time.Date(2022, 2, 18, 9, 0, 0, 0, time.UTC)
+
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, "orderID", repository.IncludeDeleted).Return(&model.Order{
+			OrderID:   "orderID",
+			Status:    model.OrderStatusCancelled,
+			DeletedAt: sql.NullTime{Time: deletedAt, Valid: true},
+		}, nil)
+		mockOrderRepo.On("GetByOrderIDForUpdate", mock.Anything, "unknown", repository.IncludeDeleted).Return(nil, nil)
+		mockOrderRepo.On("RestoreTx", mock.Anything, "orderID").Return(nil)
+		mockTransactionRepo.On("RestoreByOrderIDTx", mock.Anything, "orderID", deletedAt).Return(nil)
+
+		// Case: order is restored with the transactions deleted along with it
+		httpCode, resp := transactionService.Restore(context.Background(), "orderID")
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Nil(t, resp.ResultData.(model.DeleteOrderResponse).DeletedAt)
+		mockTransactionRepo.AssertNumberOfCalls(t, "RestoreByOrderIDTx", 1)
+
+		// Case: unknown order
+		httpCode, _ = transactionService.Restore(context.Background(), "unknown")
+		assert.Equal(t, httpCode, http.StatusNotFound)
+	}(t)
+}
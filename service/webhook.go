@@ -0,0 +1,528 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	"github.com/richardsahvic/jamtangan/domain/repository"
+	"github.com/richardsahvic/jamtangan/pkg/logger"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/pkg/utils"
+)
+
+// List of webhook delivery listing page size.
+const (
+	defaultWebhookDeliveryLimit = 20
+	maxWebhookDeliveryLimit     = 100
+)
+
+// maxWebhookErrorLength is the longest error or response body kept in the delivery log.
+const maxWebhookErrorLength = 1000
+
+// webhookEventTypes defines the event types a webhook can subscribe to.
+var webhookEventTypes = map[string]bool{
+	string(model.OutboxEventOrderCreated):        true,
+	string(model.OutboxEventOrderCancelled):      true,
+	string(model.OutboxEventOrderStatusChanged):  true,
+	string(model.OutboxEventProductCreated):      true,
+	string(model.OutboxEventProductUpdated):      true,
+	string(model.OutboxEventProductDeleted):      true,
+	string(model.OutboxEventProductRestored):     true,
+	string(model.OutboxEventProductBackInStock):  true,
+	string(model.OutboxEventProductLowStock):     true,
+	string(model.OutboxEventProductPriceChanged): true,
+	string(model.OutboxEventBrandCreated):        true,
+	string(model.OutboxEventBrandUpdated):        true,
+	string(model.OutboxEventBrandDeleted):        true,
+	string(model.OutboxEventBrandRestored):       true,
+	model.WebhookAllEvents:                       true,
+}
+
+// WebhookService manage logical syntax for webhook subscription.
+type WebhookService interface {
+	Create(ctx context.Context, request model.CreateWebhookRequest) (int, *model.BaseResponse)
+	Get(ctx context.Context, webhookID string) (int, *model.BaseResponse)
+	List(ctx context.Context) (int, *model.BaseResponse)
+	Update(ctx context.Context, request model.UpdateWebhookRequest) (int, *model.BaseResponse)
+	Delete(ctx context.Context, webhookID string) (int, *model.BaseResponse)
+	ListDeliveries(ctx context.Context, request model.ListWebhookDeliveryRequest) (int, *model.BaseResponse)
+}
+
+type webhookServiceImpl struct {
+	webhookRepo repository.WebhookRepository
+	resolver    notifier.Resolver
+}
+
+// NewWebhookService returns new instance of webhookServiceImpl.
+func NewWebhookService() *webhookServiceImpl {
+	return &webhookServiceImpl{
+		resolver: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// SetWebhookRepo injects webhook's repo for webhookServiceImpl.
+func (s *webhookServiceImpl) SetWebhookRepo(repo repository.WebhookRepository) *webhookServiceImpl {
+	s.webhookRepo = repo
+	return s
+}
+
+// SetResolver sets the resolver endpoint hosts are checked with.
+func (s *webhookServiceImpl) SetResolver(resolver notifier.Resolver) *webhookServiceImpl {
+	s.resolver = resolver
+	return s
+}
+
+// Validate validates if all dependency for webhookServiceImpl is complete.
+func (s *webhookServiceImpl) Validate() *webhookServiceImpl {
+	if s.webhookRepo == nil {
+		log.Panic("Webhook service need webhook repository")
+	}
+	return s
+}
+
+// Create registers a new webhook endpoint.
+func (s *webhookServiceImpl) Create(ctx context.Context, request model.CreateWebhookRequest) (int, *model.BaseResponse) {
+	// validate request
+	if strings.TrimSpace(request.URL) == "" {
+		return utils.RequestRequired("url")
+	} else if !s.isWebhookURL(ctx, request.URL) {
+		return utils.RequestInvalid("url")
+	}
+
+	eventTypes, ok := normalizeEventTypes(request.EventTypes)
+	if len(eventTypes) == 0 {
+		return utils.RequestRequired("event_types")
+	} else if !ok {
+		return utils.RequestInvalid("event_types")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Create")
+
+	secret := request.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		_, err := rand.Read(raw)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to generate webhook secret, err : %s", err.Error()))
+			return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+		}
+		secret = hex.EncodeToString(raw)
+	}
+
+	subscription := model.WebhookSubscription{
+		URL:        strings.TrimSpace(request.URL),
+		EventTypes: strings.Join(eventTypes, ","),
+		Secret:     secret,
+		Active:     true,
+	}
+
+	err := s.webhookRepo.Create(&subscription)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to create webhook, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.CreateWebhookResponse{
+		ID:     subscription.ID,
+		Secret: secret,
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Get returns a webhook endpoint by the ID.
+func (s *webhookServiceImpl) Get(ctx context.Context, webhookID string) (int, *model.BaseResponse) {
+	// validate request
+	id, err := strconv.ParseInt(webhookID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Get")
+
+	subscription, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get webhook by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if subscription == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: webhookResponse(subscription)}
+}
+
+// List returns every webhook endpoint.
+func (s *webhookServiceImpl) List(ctx context.Context) (int, *model.BaseResponse) {
+	log := logger.GetLoggerContext(ctx, "service", "List")
+
+	subscriptions, err := s.webhookRepo.List()
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list webhooks, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListWebhookResponse{
+		Webhooks: make([]model.WebhookResponse, len(subscriptions)),
+	}
+	for index, subscription := range subscriptions {
+		resp.Webhooks[index] = webhookResponse(subscription)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// Update changes the URL, event types or state of a webhook endpoint.
+func (s *webhookServiceImpl) Update(ctx context.Context, request model.UpdateWebhookRequest) (int, *model.BaseResponse) {
+	// validate request
+	if request.ID == 0 {
+		return utils.RequestRequired("id")
+	} else if request.URL != nil && !s.isWebhookURL(ctx, *request.URL) {
+		return utils.RequestInvalid("url")
+	}
+
+	eventTypes, ok := normalizeEventTypes(request.EventTypes)
+	if request.EventTypes != nil && (len(eventTypes) == 0 || !ok) {
+		return utils.RequestInvalid("event_types")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Update")
+
+	subscription, err := s.webhookRepo.GetByID(request.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get webhook by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if subscription == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	if request.URL != nil {
+		subscription.URL = strings.TrimSpace(*request.URL)
+	}
+	if request.EventTypes != nil {
+		subscription.EventTypes = strings.Join(eventTypes, ",")
+	}
+	if request.Active != nil && *request.Active != subscription.Active {
+		subscription.Active = *request.Active
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledAt = sql.NullTime{}
+		if !subscription.Active {
+			subscription.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+
+	err = s.webhookRepo.Update(subscription)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to update webhook, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: webhookResponse(subscription)}
+}
+
+// Delete removes a webhook endpoint, it stops receiving events right away.
+func (s *webhookServiceImpl) Delete(ctx context.Context, webhookID string) (int, *model.BaseResponse) {
+	// validate request
+	id, err := strconv.ParseInt(webhookID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("id")
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "Delete")
+
+	subscription, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get webhook by id, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	if subscription == nil {
+		return http.StatusNotFound, &model.BaseResponse{}
+	}
+
+	err = s.webhookRepo.Delete(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to delete webhook, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	return http.StatusOK, &model.BaseResponse{}
+}
+
+// ListDeliveries returns the delivery log of a webhook endpoint, newest first.
+// The next page is requested with the next_cursor of the previous page.
+func (s *webhookServiceImpl) ListDeliveries(ctx context.Context, request model.ListWebhookDeliveryRequest) (int, *model.BaseResponse) {
+	// validate request
+	var err error
+	filter := model.WebhookDeliveryFilter{
+		Status: model.WebhookDeliveryStatus(request.Status),
+		Limit:  defaultWebhookDeliveryLimit,
+	}
+
+	if strings.TrimSpace(request.SubscriptionID) == "" {
+		return utils.RequestRequired("webhook_id")
+	}
+
+	filter.SubscriptionID, err = strconv.ParseInt(request.SubscriptionID, 10, 64)
+	if err != nil {
+		return utils.RequestInvalid("webhook_id")
+	}
+
+	if request.EventID != "" {
+		filter.EventID, err = strconv.ParseInt(request.EventID, 10, 64)
+		if err != nil {
+			return utils.RequestInvalid("event_id")
+		}
+	}
+
+	if filter.Status != "" && filter.Status != model.WebhookDeliverySuccess && filter.Status != model.WebhookDeliveryFailed {
+		return utils.RequestInvalid("status")
+	}
+
+	if request.Limit != "" {
+		filter.Limit, err = strconv.Atoi(request.Limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > maxWebhookDeliveryLimit {
+			return utils.RequestInvalid("limit")
+		}
+	}
+
+	if request.Cursor != "" {
+		_, filter.BeforeID, err = utils.DecodeCursor(request.Cursor)
+		if err != nil || filter.BeforeID <= 0 {
+			return utils.RequestInvalid("cursor")
+		}
+	}
+
+	log := logger.GetLoggerContext(ctx, "service", "ListDeliveries")
+
+	deliveries, err := s.webhookRepo.ListDeliveries(filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to list webhook deliveries, err : %s", err.Error()))
+		return http.StatusInternalServerError, &model.BaseResponse{RawMessage: err.Error()}
+	}
+
+	resp := model.ListWebhookDeliveryResponse{
+		Deliveries: make([]model.WebhookDeliveryResponse, len(deliveries)),
+	}
+	for index, delivery := range deliveries {
+		resp.Deliveries[index] = model.WebhookDeliveryResponse{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Attempt:        delivery.Attempt,
+			Status:         delivery.Status,
+			ResponseCode:   delivery.ResponseCode.Int64,
+			ResponseBody:   delivery.ResponseBody.String,
+			Error:          delivery.Error.String,
+			DurationMS:     delivery.DurationMS,
+			CreatedAt:      delivery.CreatedAt,
+		}
+	}
+
+	if len(deliveries) == filter.Limit {
+		resp.NextCursor = utils.EncodeCursor("", deliveries[len(deliveries)-1].ID)
+	}
+
+	return http.StatusOK, &model.BaseResponse{ResultData: resp}
+}
+
+// isWebhookURL returns true when value is an absolute http or https URL whose
+// host only resolves to public addresses.
+func (s *webhookServiceImpl) isWebhookURL(ctx context.Context, value string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return false
+	}
+
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil {
+		return notifier.IsPublicIP(ip)
+	}
+
+	addresses, err := s.resolver(ctx, parsed.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return false
+	}
+	for _, address := range addresses {
+		if !notifier.IsPublicIP(address.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeEventTypes trims and dedupes event types, it returns false when one is unknown.
+func normalizeEventTypes(values []string) ([]string, bool) {
+	eventTypes := make([]string, 0)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !webhookEventTypes[value] {
+			return eventTypes, false
+		}
+		if !containsString(eventTypes, value) {
+			eventTypes = append(eventTypes, value)
+		}
+	}
+	return eventTypes, true
+}
+
+// webhookResponse returns the response of a webhook subscription without its secret.
+func webhookResponse(subscription *model.WebhookSubscription) model.WebhookResponse {
+	return model.WebhookResponse{
+		ID:                  subscription.ID,
+		URL:                 subscription.URL,
+		EventTypes:          strings.Split(subscription.EventTypes, ","),
+		Active:              subscription.Active,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		DisabledAt:          utils.TimePtr(subscription.DisabledAt),
+		CreatedAt:           subscription.CreatedAt,
+	}
+}
+
+// WebhookChannel delivers outbox events to the webhook endpoints subscribed to them.
+// Every attempt is written to the delivery log. An endpoint that already received
+// an event is skipped when the event is retried for another endpoint, and an
+// endpoint is disabled after maxFailures failed deliveries in a row.
+type WebhookChannel struct {
+	webhookRepo repository.WebhookRepository
+	sender      *notifier.WebhookSender
+	maxFailures int64
+}
+
+// NewWebhookChannel returns new instance of WebhookChannel.
+func NewWebhookChannel() *WebhookChannel {
+	return &WebhookChannel{}
+}
+
+// SetWebhookRepo injects webhook's repo for WebhookChannel.
+func (c *WebhookChannel) SetWebhookRepo(repo repository.WebhookRepository) *WebhookChannel {
+	c.webhookRepo = repo
+	return c
+}
+
+// SetSender sets the client posting webhook requests.
+func (c *WebhookChannel) SetSender(sender *notifier.WebhookSender) *WebhookChannel {
+	c.sender = sender
+	return c
+}
+
+// SetMaxFailures sets how many deliveries in a row may fail before an endpoint is disabled.
+func (c *WebhookChannel) SetMaxFailures(maxFailures int64) *WebhookChannel {
+	c.maxFailures = maxFailures
+	return c
+}
+
+// Validate validates if all dependency for WebhookChannel is complete.
+func (c *WebhookChannel) Validate() *WebhookChannel {
+	if c.webhookRepo == nil {
+		log.Panic("Webhook channel need webhook repository")
+	}
+	if c.sender == nil {
+		log.Panic("Webhook channel need sender")
+	}
+	if c.maxFailures <= 0 {
+		log.Panic("Webhook channel need max failures")
+	}
+	return c
+}
+
+// Name returns the name of the channel.
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// Send posts the event to every endpoint subscribed to its type, it returns an
+// error when at least one endpoint failed so the event is retried.
+func (c *WebhookChannel) Send(ctx context.Context, event *model.OutboxEvent) error {
+	log := logger.GetLoggerContext(ctx, "service", "Send")
+
+	subscriptions, err := c.webhookRepo.GetActiveByEventType(event.EventType)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:        event.OriginID(),
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	failures := make([]string, 0)
+	for _, subscription := range subscriptions {
+		delivered, err := c.webhookRepo.HasDelivered(subscription.ID, event.OriginID())
+		if err != nil {
+			return err
+		} else if delivered {
+			continue
+		}
+
+		start := time.Now()
+		resp, sendErr := c.sender.Send(ctx, subscription.URL, subscription.Secret, event.OriginID(), string(event.EventType), body)
+
+		delivery := model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.OriginID(),
+			EventType:      event.EventType,
+			Attempt:        event.Attempts + 1,
+			Status:         model.WebhookDeliverySuccess,
+			DurationMS:     time.Since(start).Milliseconds(),
+		}
+		if resp != nil {
+			delivery.ResponseCode = sql.NullInt64{Int64: int64(resp.StatusCode), Valid: true}
+			delivery.ResponseBody = sql.NullString{String: truncate(resp.Body, maxWebhookErrorLength), Valid: true}
+		}
+		if sendErr != nil {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.Error = sql.NullString{String: truncate(sendErr.Error(), maxWebhookErrorLength), Valid: true}
+			failures = append(failures, fmt.Sprintf("webhook %d: %s", subscription.ID, sendErr.Error()))
+		}
+
+		err = c.webhookRepo.CreateDelivery(&delivery)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to create webhook delivery, err : %s", err.Error()))
+		}
+
+		err = c.webhookRepo.RecordResult(subscription.ID, sendErr == nil, c.maxFailures)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to record webhook result, err : %s", err.Error()))
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// truncate returns at most max bytes of value as valid UTF-8, it is cut on a
+// rune boundary and invalid bytes are dropped so the value can be stored.
+func truncate(value string, max int) string {
+	if len(value) > max {
+		for max > 0 && !utf8.RuneStart(value[max]) {
+			max--
+		}
+		value = value[:max]
+	}
+	return strings.ToValidUTF8(value, "")
+}
@@ -0,0 +1,378 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/richardsahvic/jamtangan/domain/model"
+	repoMock "github.com/richardsahvic/jamtangan/domain/repository/mocks"
+	"github.com/richardsahvic/jamtangan/pkg/notifier"
+	"github.com/richardsahvic/jamtangan/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// resolveHosts returns a resolver answering with the given addresses of each host.
+func resolveHosts(hosts map[string]string) notifier.Resolver {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		address, ok := hosts[host]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []net.IPAddr{{IP: net.ParseIP(address)}}, nil
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	prepare()
+
+	resolver := resolveHosts(map[string]string{
+		"partner.example.com":  "93.184.216.34",
+		"internal.example.com": "10.0.0.5",
+	})
+
+	// TestCreateWebhookInvalidRequest
+	func(t *testing.T) {
+		webhookService := service.NewWebhookService().
+			SetResolver(resolver)
+
+		// Case: relative URL
+		req := model.CreateWebhookRequest{
+			URL:        "/hook",
+			EventTypes: []string{"order.created"},
+		}
+		httpCode, resp := webhookService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+
+		// Case: not an http URL
+		req = model.CreateWebhookRequest{
+			URL:        "ftp://partner.example.com/hook",
+			EventTypes: []string{"order.created"},
+		}
+		httpCode, resp = webhookService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Equal(t, resp.RawMessage, "url is invalid")
+
+		// Case: loopback and private addresses
+		for _, url := range []string{
+			"http://127.0.0.1:8080/hook",
+			"http://[::1]/hook",
+			"http://169.254.169.254/latest/meta-data",
+			"https://internal.example.com/hook",
+			"https://unknown.example.com/hook",
+		} {
+			req = model.CreateWebhookRequest{
+				URL:        url,
+				EventTypes: []string{"order.created"},
+			}
+			httpCode, resp = webhookService.Create(context.Background(), req)
+			assert.Equal(t, httpCode, http.StatusBadRequest, url)
+			assert.Equal(t, resp.RawMessage, "url is invalid", url)
+		}
+
+		// Case: unknown event type
+		req = model.CreateWebhookRequest{
+			URL:        "https://partner.example.com/hook",
+			EventTypes: []string{"order.created", "order.unknown"},
+		}
+		httpCode, resp = webhookService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestCreateWebhookSuccess
+	func(t *testing.T) {
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookService := service.NewWebhookService().
+			SetWebhookRepo(mockWebhookRepo).
+			SetResolver(resolver)
+
+		// Case: secret is generated
+		req := model.CreateWebhookRequest{
+			URL:        "https://partner.example.com/hook",
+			EventTypes: []string{"order.created", "product.low_stock", "order.created"},
+		}
+		mockWebhookRepo.On("Create", mock.MatchedBy(func(subscription *model.WebhookSubscription) bool {
+			return subscription.EventTypes == "order.created,product.low_stock" && len(subscription.Secret) == 64 && subscription.Active
+		})).Return(nil)
+		httpCode, resp := webhookService.Create(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.CreateWebhookResponse)
+		assert.Len(t, result.Secret, 64)
+	}(t)
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	prepare()
+
+	// TestUpdateWebhookEnable
+	func(t *testing.T) {
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookService := service.NewWebhookService().
+			SetWebhookRepo(mockWebhookRepo)
+
+		// Case: enabling a disabled webhook resets its failures
+		active := true
+		req := model.UpdateWebhookRequest{
+			ID:     1,
+			Active: &active,
+		}
+		mockWebhookRepo.On("GetByID", int64(1)).Return(&model.WebhookSubscription{
+			ID:                  1,
+			URL:                 "https://partner.example.com/hook",
+			EventTypes:          "order.created",
+			ConsecutiveFailures: 10,
+		}, nil)
+		mockWebhookRepo.On("Update", mock.MatchedBy(func(subscription *model.WebhookSubscription) bool {
+			return subscription.Active && subscription.ConsecutiveFailures == 0 && !subscription.DisabledAt.Valid
+		})).Return(nil)
+		httpCode, resp := webhookService.Update(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.WebhookResponse)
+		assert.True(t, result.Active)
+		mockWebhookRepo.AssertNumberOfCalls(t, "Update", 1)
+	}(t)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	prepare()
+
+	// TestListWebhookDeliveriesInvalidRequest
+	func(t *testing.T) {
+		webhookService := service.NewWebhookService()
+
+		req := model.ListWebhookDeliveryRequest{
+			SubscriptionID: "1",
+			Status:         "pending",
+		}
+		httpCode, resp := webhookService.ListDeliveries(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusBadRequest)
+		assert.Nil(t, resp.ResultData)
+	}(t)
+
+	// TestListWebhookDeliveriesSuccess
+	func(t *testing.T) {
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookService := service.NewWebhookService().
+			SetWebhookRepo(mockWebhookRepo)
+
+		// Case: a full page has a cursor to the next one
+		req := model.ListWebhookDeliveryRequest{
+			SubscriptionID: "1",
+			Limit:          "1",
+		}
+		mockWebhookRepo.On("ListDeliveries", model.WebhookDeliveryFilter{SubscriptionID: 1, Limit: 1}).Return([]*model.WebhookDelivery{
+			{ID: 9, SubscriptionID: 1, EventID: 3, Status: model.WebhookDeliveryFailed},
+		}, nil)
+		httpCode, resp := webhookService.ListDeliveries(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+
+		result := resp.ResultData.(model.ListWebhookDeliveryResponse)
+		assert.Len(t, result.Deliveries, 1)
+		assert.NotEmpty(t, result.NextCursor)
+
+		// Case: next page
+		req.Cursor = result.NextCursor
+		mockWebhookRepo.On("ListDeliveries", model.WebhookDeliveryFilter{SubscriptionID: 1, BeforeID: 9, Limit: 1}).Return([]*model.WebhookDelivery{}, nil)
+		httpCode, resp = webhookService.ListDeliveries(context.Background(), req)
+		assert.Equal(t, httpCode, http.StatusOK)
+		assert.Empty(t, resp.ResultData.(model.ListWebhookDeliveryResponse).NextCursor)
+	}(t)
+}
+
+func TestSendWebhook(t *testing.T) {
+	prepare()
+
+	event := &model.OutboxEvent{
+		ID:        7,
+		EventType: model.OutboxEventOrderCreated,
+		Payload:   json.RawMessage(`{"order_id":"ORDER-test"}`),
+		CreatedAt: time.Now(),
+	}
+
+	// TestSendWebhookSigned
+	func(t *testing.T) {
+		var received *http.Request
+		var receivedBody []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			receivedBody, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookChannel := service.NewWebhookChannel().
+			SetWebhookRepo(mockWebhookRepo).
+			SetSender(notifier.NewWebhookSender(time.Second).SetAllowPrivate(true)).
+			SetMaxFailures(3).
+			Validate()
+
+		// Case: the receiver can verify the signature with the shared secret
+		mockWebhookRepo.On("GetActiveByEventType", model.OutboxEventOrderCreated).Return([]*model.WebhookSubscription{
+			{ID: 1, URL: server.URL, Secret: "secret", Active: true},
+		}, nil)
+		mockWebhookRepo.On("HasDelivered", int64(1), int64(7)).Return(false, nil)
+		mockWebhookRepo.On("CreateDelivery", mock.MatchedBy(func(delivery *model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliverySuccess && delivery.ResponseCode.Int64 == http.StatusNoContent
+		})).Return(nil)
+		mockWebhookRepo.On("RecordResult", int64(1), true, int64(3)).Return(nil)
+		err := webhookChannel.Send(context.Background(), event)
+		assert.NoError(t, err)
+
+		timestamp, _ := strconv.ParseInt(received.Header.Get(notifier.WebhookTimestampHeader), 10, 64)
+		assert.InDelta(t, time.Now().Unix(), timestamp, 5)
+		assert.Equal(t, notifier.SignWebhook("secret", timestamp, receivedBody), received.Header.Get(notifier.WebhookSignatureHeader))
+		assert.Equal(t, "7", received.Header.Get(notifier.WebhookEventIDHeader))
+		assert.Contains(t, string(receivedBody), `"data":{"order_id":"ORDER-test"}`)
+	}(t)
+
+	// TestSendWebhookFailed
+	func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("down"))
+		}))
+		defer server.Close()
+
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookChannel := service.NewWebhookChannel().
+			SetWebhookRepo(mockWebhookRepo).
+			SetSender(notifier.NewWebhookSender(time.Second).SetAllowPrivate(true)).
+			SetMaxFailures(3).
+			Validate()
+
+		// Case: an endpoint that already received the event is not called again
+		mockWebhookRepo.On("GetActiveByEventType", model.OutboxEventOrderCreated).Return([]*model.WebhookSubscription{
+			{ID: 1, URL: "http://127.0.0.1:1", Secret: "secret", Active: true},
+			{ID: 2, URL: server.URL, Secret: "secret", Active: true},
+		}, nil)
+		mockWebhookRepo.On("HasDelivered", int64(1), int64(7)).Return(true, nil)
+		mockWebhookRepo.On("HasDelivered", int64(2), int64(7)).Return(false, nil)
+		mockWebhookRepo.On("CreateDelivery", mock.MatchedBy(func(delivery *model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliveryFailed && delivery.ResponseCode.Int64 == http.StatusInternalServerError &&
+				delivery.ResponseBody.String == "down"
+		})).Return(nil)
+		mockWebhookRepo.On("RecordResult", int64(2), false, int64(3)).Return(nil)
+		err := webhookChannel.Send(context.Background(), event)
+		assert.Error(t, err)
+		mockWebhookRepo.AssertNumberOfCalls(t, "CreateDelivery", 1)
+		mockWebhookRepo.AssertNumberOfCalls(t, "RecordResult", 1)
+	}(t)
+
+	// TestSendWebhookRetriedDeadLetter
+	func(t *testing.T) {
+		var received *http.Request
+		var receivedBody []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			receivedBody, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookChannel := service.NewWebhookChannel().
+			SetWebhookRepo(mockWebhookRepo).
+			SetSender(notifier.NewWebhookSender(time.Second).SetAllowPrivate(true)).
+			SetMaxFailures(3).
+			Validate()
+
+		retried := &model.OutboxEvent{
+			ID:            60,
+			OriginEventID: sql.NullInt64{Int64: 7, Valid: true},
+			EventType:     model.OutboxEventOrderCreated,
+			Payload:       json.RawMessage(`{"order_id":"ORDER-test"}`),
+			CreatedAt:     time.Now(),
+		}
+
+		// Case: a dead letter retried after a partial success only goes to the endpoint that failed, as the same event
+		mockWebhookRepo.On("GetActiveByEventType", model.OutboxEventOrderCreated).Return([]*model.WebhookSubscription{
+			{ID: 1, URL: "http://127.0.0.1:1", Secret: "secret", Active: true},
+			{ID: 2, URL: server.URL, Secret: "secret", Active: true},
+		}, nil)
+		mockWebhookRepo.On("HasDelivered", int64(1), int64(7)).Return(true, nil)
+		mockWebhookRepo.On("HasDelivered", int64(2), int64(7)).Return(false, nil)
+		mockWebhookRepo.On("CreateDelivery", mock.MatchedBy(func(delivery *model.WebhookDelivery) bool {
+			return delivery.SubscriptionID == 2 && delivery.EventID == 7 && delivery.Status == model.WebhookDeliverySuccess
+		})).Return(nil)
+		mockWebhookRepo.On("RecordResult", int64(2), true, int64(3)).Return(nil)
+		err := webhookChannel.Send(context.Background(), retried)
+		assert.NoError(t, err)
+		assert.Equal(t, "7", received.Header.Get(notifier.WebhookEventIDHeader))
+		assert.Contains(t, string(receivedBody), `"id":7,`)
+		mockWebhookRepo.AssertNumberOfCalls(t, "CreateDelivery", 1)
+	}(t)
+
+	// TestSendWebhookPrivateAddress
+	func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookChannel := service.NewWebhookChannel().
+			SetWebhookRepo(mockWebhookRepo).
+			SetSender(notifier.NewWebhookSender(time.Second)).
+			SetMaxFailures(3).
+			Validate()
+
+		// Case: an endpoint now resolving to a loopback address is not called
+		mockWebhookRepo.On("GetActiveByEventType", model.OutboxEventOrderCreated).Return([]*model.WebhookSubscription{
+			{ID: 1, URL: server.URL, Secret: "secret", Active: true},
+		}, nil)
+		mockWebhookRepo.On("HasDelivered", int64(1), int64(7)).Return(false, nil)
+		mockWebhookRepo.On("CreateDelivery", mock.MatchedBy(func(delivery *model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliveryFailed && !delivery.ResponseCode.Valid
+		})).Return(nil)
+		mockWebhookRepo.On("RecordResult", int64(1), false, int64(3)).Return(nil)
+		err := webhookChannel.Send(context.Background(), event)
+		assert.Error(t, err)
+		assert.False(t, called)
+	}(t)
+
+	// TestSendWebhookMultibyteResponse
+	func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Repeat("a", 999) + "é" + "\xff"))
+		}))
+		defer server.Close()
+
+		mockWebhookRepo := new(repoMock.WebhookRepository)
+		webhookChannel := service.NewWebhookChannel().
+			SetWebhookRepo(mockWebhookRepo).
+			SetSender(notifier.NewWebhookSender(time.Second).SetAllowPrivate(true)).
+			SetMaxFailures(3).
+			Validate()
+
+		// Case: a response cut in the middle of a rune is stored as valid UTF-8
+		mockWebhookRepo.On("GetActiveByEventType", model.OutboxEventOrderCreated).Return([]*model.WebhookSubscription{
+			{ID: 1, URL: server.URL, Secret: "secret", Active: true},
+		}, nil)
+		mockWebhookRepo.On("HasDelivered", int64(1), int64(7)).Return(false, nil)
+		mockWebhookRepo.On("CreateDelivery", mock.MatchedBy(func(delivery *model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliverySuccess && delivery.ResponseBody.String == strings.Repeat("a", 999) &&
+				utf8.ValidString(delivery.ResponseBody.String)
+		})).Return(nil)
+		mockWebhookRepo.On("RecordResult", int64(1), true, int64(3)).Return(nil)
+		err := webhookChannel.Send(context.Background(), event)
+		assert.NoError(t, err)
+		mockWebhookRepo.AssertNumberOfCalls(t, "CreateDelivery", 1)
+	}(t)
+}